@@ -0,0 +1,179 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "path/filepath"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Completed miners that ended long ago are moved out of miners.json into archive.json next to
+// it, which keeps the working set small. They stay viewable and can still count toward totals.
+const maxArchiveAfterDays = 36500
+
+func archiveFilePath() string {
+    return filepath.Join(portfolioDir(configManager.GetConfig().ActivePortfolio), "archive.json")
+}
+
+func loadArchivedMiners() ([]Miner, error) {
+    minersMutex.RLock()
+    defer minersMutex.RUnlock()
+    file, err := os.Open(archiveFilePath())
+    if err != nil {
+        if os.IsNotExist(err) {
+            return []Miner{}, nil
+        }
+        return nil, err
+    }
+    defer file.Close()
+    var miners []Miner
+    if err := json.NewDecoder(file).Decode(&miners); err != nil {
+        return nil, err
+    }
+    return miners, nil
+}
+
+func saveArchivedMiners(miners []Miner) error {
+    minersMutex.Lock()
+    defer minersMutex.Unlock()
+    data, err := json.MarshalIndent(miners, "", "  ")
+    if err != nil {
+        return err
+    }
+    return writeFileSynced(archiveFilePath(), append(data, '\n'))
+}
+
+// Splits off completed miners whose end date is more than days before now
+func splitAgedCompleted(miners []Miner, now time.Time, days int) ([]Miner, []Miner) {
+    kept := []Miner{}
+    var aged []Miner
+    cutoff := calendarDay(now).AddDate(0, 0, -days)
+    for _, miner := range miners {
+        end, err := time.Parse(dateLayout, miner.EndDate)
+        if miner.Status == "completed" && err == nil && calendarDay(end).Before(cutoff) {
+            aged = append(aged, miner)
+            continue
+        }
+        kept = append(kept, miner)
+    }
+    return kept, aged
+}
+
+// Appends miners not archived yet, an ID already in the archive was moved by an earlier
+// pass that didn't get to save miners.json
+func appendArchived(archived, miners []Miner) []Miner {
+    for _, miner := range miners {
+        seen := false
+        for _, existing := range archived {
+            if existing.ID != "" && existing.ID == miner.ID {
+                seen = true
+                break
+            }
+        }
+        if !seen {
+            archived = append(archived, miner)
+        }
+    }
+    return archived
+}
+
+// Moves aged-out completed miners of the active portfolio into the archive, returns how many
+func archiveOldMiners(now time.Time) (int, error) {
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    return archiveAgedMiners(now)
+}
+
+// archiveOldMiners for callers already holding minersTxMutex
+func archiveAgedMiners(now time.Time) (int, error) {
+    days := configManager.GetConfig().ArchiveAfterDays
+    if days <= 0 {
+        return 0, nil
+    }
+    miners, err := loadMiners()
+    if err != nil {
+        return 0, err
+    }
+    kept, aged := splitAgedCompleted(miners, now, days)
+    if len(aged) == 0 {
+        return 0, nil
+    }
+    archived, err := loadArchivedMiners()
+    if err != nil {
+        return 0, err
+    }
+    // Archive first, a crash in between leaves a miner in both files rather than in neither
+    if err := saveArchivedMiners(appendArchived(archived, aged)); err != nil {
+        return 0, err
+    }
+    for _, miner := range aged {
+        journalAppend(journalEntry{Op: "delete", ID: miner.ID})
+    }
+    if err := saveMiners(kept); err != nil {
+        return 0, err
+    }
+    log.Println("Archived", len(aged), "completed miners")
+    return len(aged), nil
+}
+
+func runArchivePass() {
+    if _, err := archiveOldMiners(time.Now()); err != nil {
+        log.Println("Error archiving miners:", err)
+    }
+}
+
+// Miners the totals are computed from, with the archive when it counts toward them
+func totalsMiners(miners []Miner) []Miner {
+    if !configManager.GetConfig().IncludeArchivedInTotals {
+        return miners
+    }
+    archived, err := loadArchivedMiners()
+    if err != nil {
+        log.Println("Error loading archived miners:", err)
+        return miners
+    }
+    return append(copyMiners(miners), archived...)
+}
+
+// Portfolio summary following the totals settings (completed and archived miners)
+func currentPortfolioSummary(miners []Miner, data LiveData, now time.Time) portfolioSummary {
+    return computePortfolioSummary(totalsMiners(miners), data, now, configManager.GetConfig().IncludeCompletedInTotals)
+}
+
+func showArchivedMinersWindow(archived []Miner) {
+    archivedWindow := fyne.CurrentApp().NewWindow("Archived Miners")
+    archivedWindow.Resize(fyne.NewSize(600, 400))
+
+    const itemsPerPage = 10
+
+    minersBox := container.NewVBox()
+    updateMiners := func(startIndex, endIndex int) {
+        minersBox.Objects = nil
+        for i := startIndex; i < endIndex; i++ {
+            miner := archived[i]
+            label := widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %s", miner.StartDate, miner.EndDate, formatTShares(miner.TShares)))
+            label.Wrapping = fyne.TextWrapOff
+            minersBox.Add(label)
+        }
+        minersBox.Refresh()
+    }
+
+    navBar := newPageNav(len(archived), itemsPerPage, updateMiners).Bar
+    closeButton := widget.NewButton("Close", func() {
+        archivedWindow.Close()
+    })
+
+    archivedWindow.SetContent(container.NewVBox(
+        widget.NewLabel("Archived Miners"),
+        container.NewMax(minersBox),
+        navBar,
+        closeButton,
+    ))
+    archivedWindow.Show()
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+
+    "fyne.io/fyne/v2/test"
+)
+
+func TestSplitAgedCompleted(t *testing.T) {
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {ID: "old", EndDate: "01-05-2025", Status: "completed"},
+        {ID: "recent", EndDate: "20-05-2025", Status: "completed"},
+        {ID: "cutoff", EndDate: "02-05-2025", Status: "completed"}, // Exactly 30 days, kept
+        {ID: "active", EndDate: "01-01-2020"},
+        {ID: "bad", EndDate: "someday", Status: "completed"},
+    }
+    kept, aged := splitAgedCompleted(miners, now, 30)
+    ids := func(miners []Miner) []string {
+        var result []string
+        for _, miner := range miners {
+            result = append(result, miner.ID)
+        }
+        return result
+    }
+    if got := ids(aged); !reflect.DeepEqual(got, []string{"old"}) {
+        t.Errorf("aged = %q, want only the completed miner past the cutoff", got)
+    }
+    if got := ids(kept); !reflect.DeepEqual(got, []string{"recent", "cutoff", "active", "bad"}) {
+        t.Errorf("kept = %q", got)
+    }
+}
+
+// Aged-out completed miners move into archive.json, an earlier interrupted pass doesn't
+// archive a miner twice, and the totals can take the archive in
+func TestArchiveOldMiners(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.ArchiveAfterDays = 30
+    })
+    useTempStorage(t)
+    now := testDay(t, "01-06-2025")
+    old := Miner{ID: "old", StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 4, Status: "completed"}
+    older := Miner{ID: "older", StartDate: "01-01-2023", EndDate: "01-01-2024", TShares: 2, Status: "completed"}
+    active := Miner{ID: "active", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 1}
+    if err := saveMiners([]Miner{old, older, active}); err != nil {
+        t.Fatal(err)
+    }
+    if err := saveArchivedMiners([]Miner{older}); err != nil { // Archived before a crash
+        t.Fatal(err)
+    }
+
+    count, err := archiveOldMiners(now)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if count != 2 {
+        t.Errorf("archived %d miners, want 2", count)
+    }
+    if miners, _ := loadMiners(); !reflect.DeepEqual(miners, []Miner{active}) {
+        t.Errorf("miners after archiving = %+v, want only the active one", miners)
+    }
+    if archived, _ := loadArchivedMiners(); !reflect.DeepEqual(archived, []Miner{older, old}) {
+        t.Errorf("archive = %+v, want each miner once", archived)
+    }
+    if count, err := archiveOldMiners(now); err != nil || count != 0 {
+        t.Errorf("second pass archived %d (%v), want nothing left to move", count, err)
+    }
+
+    miners := []Miner{active}
+    if got := len(totalsMiners(miners)); got != 1 {
+        t.Errorf("totals use %d miners, want the archive left out by default", got)
+    }
+    useConfig(t, func(c *Config) {
+        c.IncludeArchivedInTotals = true
+    })
+    if got := len(totalsMiners(miners)); got != 3 {
+        t.Errorf("totals use %d miners, want the archive included", got)
+    }
+    if len(miners) != 1 {
+        t.Error("totalsMiners changed the slice it was given")
+    }
+
+    // Off leaves everything in place
+    useConfig(t, nil)
+    if err := saveMiners([]Miner{old}); err != nil {
+        t.Fatal(err)
+    }
+    if count, _ := archiveOldMiners(now); count != 0 {
+        t.Errorf("archived %d miners with archiving off", count)
+    }
+}
+
+// Shared portfolios are read-only and have no archive of their own
+func TestProfileArchivedButton(t *testing.T) {
+    useTestApp(t)
+    useConfig(t, nil)
+    useTempStorage(t)
+    useLiveData(t, LiveData{})
+    if err := saveArchivedMiners([]Miner{{ID: "old", StartDate: "01-01-2023", EndDate: "01-01-2024", TShares: 2, Status: "completed"}}); err != nil {
+        t.Fatal(err)
+    }
+    miners := []Miner{{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2040", TShares: 2}}
+    w := test.NewWindow(nil)
+    defer w.Close()
+    if button := findButton(createProfileTab(miners, w, func() {}, false), "View Archived Miners (1)"); button == nil || !button.Visible() {
+        t.Error("own portfolio has no archived miners button")
+    }
+    if button := findButton(createProfileTab(miners, w, func() {}, true), "View Archived Miners"); button != nil && button.Visible() {
+        t.Error("read-only portfolio shows the archived miners button")
+    }
+}
@@ -0,0 +1,141 @@
+package main
+
+// HEX runs on Ethereum and on PulseChain (its fork), each miner is a stake on one of them.
+// Miners saved before chains were tracked have no chain and are PulseChain stakes.
+const (
+    chainPulsechain = "pulsechain"
+    chainEthereum   = "ethereum"
+)
+
+var chains = []string{chainPulsechain, chainEthereum}
+
+var chainNames = map[string]string{
+    chainPulsechain: "PulseChain",
+    chainEthereum:   "Ethereum",
+}
+
+// Display names for chain selects, in the order of chains
+func chainOptions() []string {
+    options := make([]string, len(chains))
+    for i, chain := range chains {
+        options[i] = chainName(chain)
+    }
+    return options
+}
+
+func isChain(chain string) bool {
+    return contains(chains, chain)
+}
+
+func chainName(chain string) string {
+    if name, ok := chainNames[chain]; ok {
+        return name
+    }
+    return chainNames[chainPulsechain]
+}
+
+// Chain key of a display name, PulseChain for unknown names
+func chainByName(name string) string {
+    for chain, chainDisplayName := range chainNames {
+        if chainDisplayName == name {
+            return chain
+        }
+    }
+    return chainPulsechain
+}
+
+func minerChain(miner Miner) string {
+    if miner.Chain == "" {
+        return chainPulsechain
+    }
+    return miner.Chain
+}
+
+// Chain as stored on a miner, PulseChain is left empty like on miners saved before chains
+func storedChain(chain string) string {
+    if chain == chainPulsechain {
+        return ""
+    }
+    return chain
+}
+
+// Row suffix naming the chain, empty for PulseChain miners so single-chain portfolios look as before
+func chainNote(miner Miner) string {
+    if minerChain(miner) == chainPulsechain {
+        return ""
+    }
+    return " [" + chainName(minerChain(miner)) + "]"
+}
+
+// Live figures of one chain
+type chainLiveData struct {
+    Price           float64
+    TsharePrice     float64
+    TshareRateHEX   float64
+    PenaltiesHEX    float64
+    PayoutPerTshare float64
+}
+
+func (d LiveData) Chain(chain string) chainLiveData {
+    if chain == chainEthereum {
+        return chainLiveData{
+            Price:           d.PriceEthereum,
+            TsharePrice:     d.TsharePriceEthereum,
+            TshareRateHEX:   d.TshareRateHEXEthereum,
+            PenaltiesHEX:    d.PenaltiesHEXEthereum,
+            PayoutPerTshare: d.PayoutPerTshareEthereum,
+        }
+    }
+    return chainLiveData{
+        Price:           d.PricePulsechain,
+        TsharePrice:     d.TsharePricePulsechain,
+        TshareRateHEX:   d.TshareRateHEXPulsechain,
+        PenaltiesHEX:    d.PenaltiesHEXPulsechain,
+        PayoutPerTshare: d.PayoutPerTsharePulsechain,
+    }
+}
+
+// Local history file of a chain, the PulseChain one keeps its original name
+func historyFilePath(chain string) string {
+    if chain == chainEthereum {
+        return "data/hexjson_ethereum.json"
+    }
+    return "data/hexjson.json"
+}
+
+// HEXDailyStats endpoint with the full history of a chain
+func historyEndpoint(chain string) string {
+    if chain == chainEthereum {
+        return "/fulldata"
+    }
+    return "/fulldatapulsechain"
+}
+
+// Value of T-Shares held per chain at the chain's T-Share price. A pinned price is a PulseChain
+// T-Share price, Ethereum T-Shares stay at the live one.
+func chainValue(tShares map[string]float64, data LiveData, pinned float64) float64 {
+    var value float64
+    for chain, amount := range tShares {
+        value += amount * chainTsharePrice(chain, data, pinned)
+    }
+    return value
+}
+
+// Whether every chain holding T-Shares has a price to value them at, their value reads too low before
+func chainPricesKnown(tShares map[string]float64, data LiveData, pinned float64) bool {
+    for chain := range tShares {
+        if chainTsharePrice(chain, data, pinned) <= 0 {
+            return false
+        }
+    }
+    return true
+}
+
+// T-Share price of chain, a pinned price only stands in for the PulseChain one
+func chainTsharePrice(chain string, data LiveData, pinned float64) float64 {
+    price := data.Chain(chain).TsharePrice
+    if chain == chainPulsechain {
+        price, _ = valuationPrice(price, pinned)
+    }
+    return price
+}
@@ -0,0 +1,382 @@
+//go:build !nocharts
+
+package main
+
+import (
+    "bytes"
+    "context"
+    "fmt"
+    "io"
+    "log"
+    "strings"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/storage"
+    "fyne.io/fyne/v2/widget"
+
+    "github.com/wcharczuk/go-chart"
+)
+
+// Charts drawn with go-chart, building with -tags nocharts leaves them (and go-chart) out,
+// see charts_stub.go
+const chartsAvailable = true
+
+// Dash pattern for a line style, nil for a solid line
+func chartDashArray(style string) []float64 {
+    switch style {
+    case "dashed":
+        return []float64{8, 4}
+    case "dotted":
+        return []float64{2, 3}
+    }
+    return nil
+}
+
+// Builds the line chart of field over the history, or the days in opts when set
+func buildChart(data HEXJSON, field string, opts chartOptions) chart.Chart {
+    points := chartSeries(data, field)
+    if opts.ToDay > 0 {
+        points = visiblePoints(points, chartViewport{From: float64(opts.FromDay), To: float64(opts.ToDay)}, 0)
+    }
+    series := chart.ContinuousSeries{
+        XValues: make([]float64, len(points)),
+        YValues: make([]float64, len(points)),
+        Style: chart.Style{
+            Show:            true,
+            StrokeWidth:     2,
+            StrokeDashArray: chartDashArray(opts.LineStyle),
+        },
+    }
+    if opts.ShowMarkers {
+        series.Style.DotWidth = 3
+    }
+    for i, point := range points {
+        series.XValues[i] = float64(point.Day)
+        series.YValues[i] = point.Value
+    }
+    graph := chart.Chart{
+        Width:  opts.Width,
+        Height: opts.Height,
+        XAxis:  chart.XAxis{Name: "Current Day"},
+        YAxis:  chart.YAxis{Name: chartFieldName(field, opts.Chain)},
+        Series: []chart.Series{series},
+    }
+    if opts.ShowToday && len(points) > 0 {
+        latest := 0
+        for i, x := range series.XValues {
+            if x > series.XValues[latest] {
+                latest = i
+            }
+        }
+        todayStyle := chart.Style{Show: true, StrokeColor: chart.ColorRed, StrokeWidth: 1, StrokeDashArray: []float64{4, 4}}
+        graph.XAxis.GridMajorStyle = todayStyle
+        graph.XAxis.GridLines = []chart.GridLine{{Value: series.XValues[latest], Style: todayStyle}}
+        graph.Series = append(graph.Series, chart.AnnotationSeries{
+            Style: chart.Style{Show: true},
+            Annotations: []chart.Value2{{
+                XValue: series.XValues[latest],
+                YValue: series.YValues[latest],
+                Label:  fmt.Sprintf("Today (day %d)", int(series.XValues[latest])),
+            }},
+        })
+    }
+    return graph
+}
+
+// Axis name of field, the Ethereum history has its price from Uniswap
+func chartFieldName(field, chain string) string {
+    if field == "pricePulseX" && chain == chainEthereum {
+        return "priceUV2UV3"
+    }
+    return field
+}
+
+// Renders the chart as chart.PNG or chart.SVG, shared by the tab and exports
+func renderChart(w io.Writer, data HEXJSON, field string, opts chartOptions, format chart.RendererProvider) error {
+    graph := buildChart(data, field, opts)
+    return graph.Render(format, w)
+}
+
+// Export format picked by the file extension, PNG unless it is .svg
+func chartFormatForExtension(ext string) chart.RendererProvider {
+    if strings.EqualFold(ext, ".svg") {
+        return chart.SVG
+    }
+    return chart.PNG
+}
+
+// Loads the local history and renders field, nil without an error while there's no history yet
+func renderLocalChart(field string, opts chartOptions, format chart.RendererProvider) ([]byte, error) {
+    data, err := loadLocalHEXJSON(opts.Chain)
+    if err != nil {
+        log.Println("Error loading HEXJSON:", err)
+        return nil, fmt.Errorf("reading history failed")
+    }
+    if len(data) == 0 {
+        return nil, nil
+    }
+    buffer := bytes.NewBuffer(nil)
+    if err := renderChart(buffer, data, field, opts, format); err != nil {
+        log.Println("Error rendering chart:", err)
+        return nil, fmt.Errorf("rendering failed")
+    }
+    return buffer.Bytes(), nil
+}
+
+// Shown while there's no local history to chart
+func chartPlaceholderText(remoteEmpty bool) string {
+    if remoteEmpty {
+        return "No historical data - the API returned none, trying again on the next sync"
+    }
+    return "Historical data not yet available - syncing"
+}
+
+// Provenance line under the chart, source is the API that answered the last sync or ""
+func chartCaption(data HEXJSON, source string, lastSync, now time.Time) string {
+    newest := "no history yet"
+    if len(data) > 0 {
+        day := data[0].CurrentDay
+        for _, entry := range data {
+            day = max(day, entry.CurrentDay)
+        }
+        newest = fmt.Sprintf("newest day %d", day)
+    }
+    synced := "not synced since launch"
+    if !lastSync.IsZero() {
+        synced = "last synced " + syncAgeText(now.Sub(lastSync))
+    }
+    if source == "" {
+        source = "HEXDailyStats API"
+    }
+    return fmt.Sprintf("Source: %s - %s - %s", source, newest, synced)
+}
+
+func createChartTab(w fyne.Window) fyne.CanvasObject {
+    chartView := newInteractiveChart(chartMinSize(configManager.GetConfig().ChartWidth, configManager.GetConfig().ChartHeight))
+    placeholder := widget.NewLabel("")
+    placeholder.Alignment = fyne.TextAlignCenter
+    placeholder.Hide()
+    errorLabel := widget.NewLabel("")
+    errorLabel.Alignment = fyne.TextAlignCenter
+    errorLabel.Importance = widget.DangerImportance
+    retryButton := widget.NewButton("Retry", nil)
+    errorBox := container.NewVBox(errorLabel, container.NewCenter(retryButton))
+    errorBox.Hide()
+    captionLabel := widget.NewLabel("")
+    captionLabel.Importance = widget.LowImportance
+    captionLabel.Alignment = fyne.TextAlignCenter
+    updateCaption := func() {
+        if !configManager.GetConfig().ChartShowSource {
+            captionLabel.Hide()
+            return
+        }
+        lastSync := lastHistorySyncTime()
+        chain := configManager.GetConfig().Chain
+        go func() {
+            data, err := loadLocalHEXJSON(chain)
+            if err != nil {
+                log.Println("Error loading HEXJSON:", err)
+            }
+            caption := chartCaption(data, answeredBy(historyEndpoint(chain)), lastSync, time.Now())
+            fyne.Do(func() {
+                captionLabel.SetText(caption)
+                captionLabel.Show()
+            })
+        }()
+    }
+
+    // Zooming or panning is kept until another range is picked or Reset is tapped
+    rangeSelect := widget.NewSelect(chartRanges, func(rangeName string) {
+        if rangeName != configManager.GetConfig().ChartRange {
+            if err := updateConfig(func(c *Config) {
+                c.ChartRange = rangeName
+            }); err != nil {
+                log.Println("Error saving config:", err)
+            }
+        }
+        chartView.ShowRange(rangeName)
+    })
+    rangeSelect.SetSelected(configManager.GetConfig().ChartRange)
+    resetZoomButton := widget.NewButton("Reset", func() {
+        chartView.ShowRange(rangeSelect.Selected)
+    })
+
+    // Loads in the background, a load finishing after a newer one was requested is dropped
+    renders := &renderGeneration{}
+    var shown chartOptions
+    updateChart := func(field string) {
+        updateCaption()
+        gen := renders.Next()
+        opts := chartOptionsFromConfig(configManager.GetConfig())
+        shown = opts
+        chartLoads.Add(1)
+        go func() {
+            defer chartLoads.Done()
+            if !renders.IsLatest(gen) {
+                return
+            }
+            data, err := loadLocalHEXJSON(opts.Chain)
+            if err != nil {
+                log.Println("Error loading HEXJSON:", err)
+            }
+            fyne.Do(func() {
+                if !renders.IsLatest(gen) {
+                    return
+                }
+                if err != nil {
+                    chartView.SetData(nil, rangeSelect.Selected, opts)
+                    placeholder.Hide()
+                    errorLabel.SetText("Chart could not be loaded: reading history failed")
+                    errorBox.Show()
+                    return
+                }
+                errorBox.Hide()
+                if len(data) == 0 {
+                    chartView.SetData(nil, rangeSelect.Selected, opts)
+                    placeholder.SetText(chartPlaceholderText(historyEmpty[opts.Chain].Load()))
+                    placeholder.Show()
+                    return
+                }
+                placeholder.Hide()
+                chartView.SetData(chartSeries(data, field), rangeSelect.Selected, opts)
+            })
+        }()
+    }
+
+    // Handler is set at construction so the select is never usable without it
+    selectField := widget.NewSelect(chartFields, func(field string) {
+        if field != configManager.GetConfig().LastChartField {
+            if err := updateConfig(func(c *Config) {
+                c.LastChartField = field
+            }); err != nil {
+                log.Println("Error saving config:", err)
+            }
+        }
+        updateChart(field)
+    })
+    field := configManager.GetConfig().LastChartField
+    if !isChartField(field) {
+        field = defaultChartField
+    }
+    selectField.SetSelected(field) // Also renders the default field
+    retryButton.OnTapped = func() {
+        updateChart(selectField.Selected)
+    }
+
+    controls := container.NewHBox()
+    container := container.NewBorder(container.NewBorder(nil, nil, nil, controls, selectField), captionLabel, nil, nil, container.NewStack(chartView, container.NewCenter(placeholder), container.NewCenter(errorBox)))
+
+    lineStyleSelect := widget.NewSelect(chartLineStyles, func(style string) {
+        if style == configManager.GetConfig().ChartLineStyle {
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.ChartLineStyle = style
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        updateChart(selectField.Selected)
+    })
+    lineStyleSelect.SetSelected(configManager.GetConfig().ChartLineStyle)
+    markersCheck := widget.NewCheck("Markers", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.ChartShowMarkers = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        updateChart(selectField.Selected)
+    })
+    markersCheck.Checked = configManager.GetConfig().ChartShowMarkers
+    todayCheck := widget.NewCheck("Today", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.ChartShowToday = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        updateChart(selectField.Selected)
+    })
+    todayCheck.Checked = configManager.GetConfig().ChartShowToday
+    sourceCheck := widget.NewCheck("Source", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.ChartShowSource = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        updateCaption()
+    })
+    sourceCheck.Checked = configManager.GetConfig().ChartShowSource
+    exportButton := widget.NewButton("Export", func() {
+        saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+            if err != nil {
+                showError(err, w)
+                return
+            }
+            if writer == nil {
+                return
+            }
+            defer writer.Close()
+            opts := chartOptionsFromConfig(configManager.GetConfig())
+            opts.FromDay, opts.ToDay = chartView.VisibleDays() // What is on screen
+            image, err := renderLocalChart(selectField.Selected, opts, chartFormatForExtension(writer.URI().Extension()))
+            if err == nil && image == nil {
+                err = fmt.Errorf("no history yet")
+            }
+            if err == nil {
+                _, err = writer.Write(image)
+            }
+            if err != nil {
+                log.Println("Error exporting chart:", err)
+                showError(fmt.Errorf("Failed to export chart: %v", err), w)
+                return
+            }
+            dialog.ShowInformation("Chart Exported", fmt.Sprintf("Chart written to %s", writer.URI().Name()), w)
+        }, w)
+        saveDialog.SetFileName(selectField.Selected + ".png")
+        saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".png", ".svg"}))
+        saveDialog.Show()
+    })
+
+    controls.Add(rangeSelect)
+    controls.Add(resetZoomButton)
+    controls.Add(lineStyleSelect)
+    controls.Add(markersCheck)
+    controls.Add(todayCheck)
+    controls.Add(sourceCheck)
+    controls.Add(exportButton)
+
+    // Redraw once the background history sync has written new data, and after the other tabs
+    // were rebuilt only when that changed what is charted (e.g. the chain was switched)
+    ctx, cancel := context.WithCancel(context.Background())
+    go func() {
+        syncCh := historySynced.Subscribe()
+        rebuiltCh := tabsRebuilt.Subscribe()
+        defer historySynced.Unsubscribe(syncCh)
+        defer tabsRebuilt.Unsubscribe(rebuiltCh)
+        for {
+            select {
+            case <-syncCh:
+                fyne.Do(func() {
+                    updateChart(selectField.Selected)
+                })
+            case <-rebuiltCh:
+                fyne.Do(func() {
+                    if chartOptionsFromConfig(configManager.GetConfig()) != shown {
+                        updateChart(selectField.Selected)
+                    }
+                })
+            case <-ctx.Done():
+                return
+            }
+        }
+    }()
+
+    return withTabWork(container, cancel)
+}
+
+// PNG of field over the local history for the Dashboard, nil while there's no history yet
+func renderChartPNG(field string, opts chartOptions) ([]byte, error) {
+    return renderLocalChart(field, opts, chart.PNG)
+}
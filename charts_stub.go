@@ -0,0 +1,21 @@
+//go:build nocharts
+
+package main
+
+import (
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Build without go-chart: the Charts tab only says so and the Dashboard leaves out its chart
+const chartsAvailable = false
+
+func createChartTab(w fyne.Window) fyne.CanvasObject {
+    label := widget.NewLabel("Charts are disabled in this build")
+    label.Alignment = fyne.TextAlignCenter
+    return label
+}
+
+func renderChartPNG(field string, opts chartOptions) ([]byte, error) {
+    return nil, nil
+}
@@ -0,0 +1,27 @@
+//go:build nocharts
+
+package main
+
+import (
+    "testing"
+
+    "fyne.io/fyne/v2/test"
+)
+
+// Without go-chart the Charts tab still renders and only says charts are off
+func TestChartTabStub(t *testing.T) {
+    useTestApp(t)
+    w := test.NewWindow(nil)
+    defer w.Close()
+    tab := createChartTab(w)
+    w.SetContent(tab)
+    if label := findLabel(tab, "Charts are disabled in this build"); label == nil {
+        t.Error("the stub tab doesn't say charts are disabled")
+    }
+    if chartsAvailable {
+        t.Error("chartsAvailable is set in a nocharts build")
+    }
+    if png, err := renderChartPNG("price", chartOptions{Width: 400, Height: 200}); png != nil || err != nil {
+        t.Errorf("renderChartPNG = %d bytes, %v, want nothing without go-chart", len(png), err)
+    }
+}
@@ -0,0 +1,327 @@
+//go:build !nocharts
+
+package main
+
+import (
+    "bytes"
+    "errors"
+    "reflect"
+    "sync"
+    "testing"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/test"
+    "fyne.io/fyne/v2/widget"
+    "github.com/wcharczuk/go-chart"
+)
+
+func testHistory() HEXJSON {
+    var data HEXJSON
+    for day := 30; day >= 1; day-- { // Newest first, like the API
+        data = append(data, HEXJSONEntry{CurrentDay: day, PricePulseX: 0.01 + float64(day)/1000, TshareRateHEX: 10000 + float64(day), DailyPayoutHEX: 2})
+    }
+    return data
+}
+
+func TestRenderChartStyles(t *testing.T) {
+    for _, style := range chartLineStyles {
+        for _, markers := range []bool{false, true} {
+            opts := chartOptions{LineStyle: style, ShowMarkers: markers, Width: 400, Height: 200}
+            graph := buildChart(testHistory(), "pricePulseX", opts)
+            series := graph.Series[0].(chart.ContinuousSeries)
+            if (series.Style.StrokeDashArray == nil) != (style == "solid") {
+                t.Errorf("%s line has dash array %v", style, series.Style.StrokeDashArray)
+            }
+            if (series.Style.DotWidth > 0) != markers {
+                t.Errorf("%s line with markers %v has dot width %v", style, markers, series.Style.DotWidth)
+            }
+            var buffer bytes.Buffer
+            if err := renderChart(&buffer, testHistory(), "pricePulseX", opts, chart.PNG); err != nil {
+                t.Errorf("rendering a %s line with markers %v: %v", style, markers, err)
+                continue
+            }
+            if !bytes.HasPrefix(buffer.Bytes(), []byte("\x89PNG")) {
+                t.Errorf("rendering a %s line with markers %v didn't produce a PNG", style, markers)
+            }
+        }
+    }
+}
+
+// The export format follows the file extension
+func TestRenderChartFormats(t *testing.T) {
+    opts := chartOptions{LineStyle: "solid", Width: 400, Height: 200}
+    tests := []struct {
+        ext    string
+        prefix string
+    }{
+        {".svg", "<svg"},
+        {".SVG", "<svg"},
+        {".png", "\x89PNG"},
+        {"", "\x89PNG"},
+    }
+    for _, tt := range tests {
+        var buffer bytes.Buffer
+        if err := renderChart(&buffer, testHistory(), "pricePulseX", opts, chartFormatForExtension(tt.ext)); err != nil {
+            t.Fatalf("rendering for %q: %v", tt.ext, err)
+        }
+        if !bytes.HasPrefix(buffer.Bytes(), []byte(tt.prefix)) {
+            t.Errorf("chart for %q starts with %q, want %q", tt.ext, buffer.Bytes()[:min(8, buffer.Len())], tt.prefix)
+        }
+    }
+}
+
+func TestBuildChartToday(t *testing.T) {
+    opts := chartOptions{LineStyle: "solid", Width: 400, Height: 200}
+    if graph := buildChart(testHistory(), "pricePulseX", opts); len(graph.Series) != 1 || len(graph.XAxis.GridLines) != 0 {
+        t.Error("today marker drawn while it is off")
+    }
+    opts.ShowToday = true
+    graph := buildChart(testHistory(), "pricePulseX", opts)
+    if len(graph.XAxis.GridLines) != 1 || graph.XAxis.GridLines[0].Value != 30 {
+        t.Errorf("grid lines = %+v, want one at the latest day 30", graph.XAxis.GridLines)
+    }
+    if len(graph.Series) != 2 {
+        t.Fatalf("got %d series, want the line and the annotation", len(graph.Series))
+    }
+    annotations, ok := graph.Series[1].(chart.AnnotationSeries)
+    if !ok || len(annotations.Annotations) != 1 || annotations.Annotations[0].Label != "Today (day 30)" {
+        t.Errorf("annotation series = %+v, want the today label at day 30", graph.Series[1])
+    }
+    var buffer bytes.Buffer
+    if err := renderChart(&buffer, testHistory(), "pricePulseX", opts, chart.SVG); err != nil {
+        t.Fatal(err)
+    }
+    if !bytes.Contains(buffer.Bytes(), []byte("Today (day 30)")) {
+        t.Error("rendered chart has no today label")
+    }
+
+    // No points, nothing to mark
+    if graph := buildChart(HEXJSON{}, "pricePulseX", opts); len(graph.Series) != 1 {
+        t.Error("today marker drawn without history")
+    }
+}
+
+// Storage serving a fixed history, or failing to read it
+type historyStore struct {
+    jsonStorage
+    mu      sync.Mutex
+    history HEXJSON
+    err     error
+    reads   int
+}
+
+func (s *historyStore) ReadHistory(chain string) (HEXJSON, error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.reads++
+    return s.history, s.err
+}
+
+func (s *historyStore) readCount() int {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    return s.reads
+}
+
+// Waits until the history was read more than n times, for loads started off the test goroutine
+func (s *historyStore) waitForReads(t *testing.T, n int) {
+    t.Helper()
+    for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(5 * time.Millisecond) {
+        s.mu.Lock()
+        reads := s.reads
+        s.mu.Unlock()
+        if reads > n {
+            return
+        }
+    }
+    t.Fatal("chart load didn't start")
+}
+
+func (s *historyStore) set(history HEXJSON, err error) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.history, s.err = history, err
+}
+
+func useHistoryStore(t *testing.T, history HEXJSON, err error) *historyStore {
+    t.Helper()
+    useTempStorage(t)
+    s := &historyStore{history: history, err: err}
+    store = s
+    return s
+}
+
+// Chart tab parts the tests look at
+func chartTabParts(object fyne.CanvasObject) (fieldSelect *widget.Select, chart *interactiveChart, errorBox *fyne.Container) {
+    var walk func(fyne.CanvasObject)
+    walk = func(object fyne.CanvasObject) {
+        switch o := object.(type) {
+        case *fyne.Container:
+            if len(o.Objects) == 2 {
+                if label, ok := o.Objects[0].(*widget.Label); ok && label.Importance == widget.DangerImportance {
+                    errorBox = o
+                }
+            }
+            for _, child := range o.Objects {
+                walk(child)
+            }
+        case *widget.Select:
+            if reflect.DeepEqual(o.Options, chartFields) {
+                fieldSelect = o
+            }
+        case *interactiveChart:
+            chart = o
+        }
+    }
+    walk(object)
+    return fieldSelect, chart, errorBox
+}
+
+// Waits for the background load to land on the UI
+func waitForChart(t *testing.T, done func() bool) {
+    t.Helper()
+    // The test driver runs fyne.Do on the load goroutine, the loads have to finish before the
+    // widgets are read
+    chartLoads.Wait()
+    if !done() {
+        t.Fatal("chart load didn't land")
+    }
+}
+
+func TestChartTabDefaultField(t *testing.T) {
+    useTestApp(t)
+    useHistoryStore(t, testHistory(), nil)
+    useConfig(t, func(c *Config) {
+        c.LastChartField = "removedField" // Not a field any more, falls back to the default
+        c.ChartShowSource = false
+    })
+    w := test.NewWindow(nil)
+    defer w.Close()
+
+    fieldSelect, chart, _ := chartTabParts(createChartTab(w))
+    if fieldSelect == nil || chart == nil {
+        t.Fatal("chart tab has no field select or chart")
+    }
+    if fieldSelect.Selected != defaultChartField {
+        t.Errorf("selected field %q, want %q", fieldSelect.Selected, defaultChartField)
+    }
+    want := chartSeries(testHistory(), defaultChartField)
+    waitForChart(t, func() bool { return reflect.DeepEqual(chart.points, want) })
+}
+
+func TestChartTabRetry(t *testing.T) {
+    useTestApp(t)
+    history := useHistoryStore(t, nil, errors.New("disk on fire"))
+    useConfig(t, func(c *Config) {
+        c.ChartShowSource = false
+    })
+    w := test.NewWindow(nil)
+    defer w.Close()
+
+    tab := createChartTab(w)
+    _, chart, errorBox := chartTabParts(tab)
+    if errorBox == nil {
+        t.Fatal("chart tab has no error box")
+    }
+    waitForChart(t, errorBox.Visible)
+    if label := errorBox.Objects[0].(*widget.Label); label.Text != "Chart could not be loaded: reading history failed" {
+        t.Errorf("error text %q", label.Text)
+    }
+    retry := findButton(tab, "Retry")
+    if retry == nil {
+        t.Fatal("error state has no Retry button")
+    }
+
+    history.set(testHistory(), nil)
+    test.Tap(retry)
+    waitForChart(t, func() bool { return !errorBox.Visible() && len(chart.points) == len(testHistory()) })
+}
+
+// The Charts tab is built once, a rebuild of the other tabs keeps the zoom unless what is
+// charted changed
+func TestChartTabKeepsZoomOnRebuild(t *testing.T) {
+    useTestApp(t)
+    history := useHistoryStore(t, testHistory(), nil)
+    useConfig(t, func(c *Config) {
+        c.ChartShowSource = false
+    })
+    w := test.NewWindow(nil)
+    defer w.Close()
+
+    _, chart, _ := chartTabParts(createChartTab(w))
+    if chart == nil {
+        t.Fatal("chart tab has no chart")
+    }
+    waitForChart(t, func() bool { return len(chart.points) == len(testHistory()) })
+    zoomed := chartViewport{From: 5, To: 10}
+    fyne.DoAndWait(func() { chart.view = zoomed })
+
+    reads := history.readCount()
+    tabsRebuilt.Notify()
+    time.Sleep(50 * time.Millisecond)
+    if history.readCount() != reads {
+        t.Fatal("a rebuild that didn't change the chart reloaded it")
+    }
+    if chart.view != zoomed {
+        t.Errorf("view %+v after a rebuild, want the zoom kept", chart.view)
+    }
+
+    updateConfig(func(c *Config) {
+        c.Chain = chainEthereum
+    })
+    tabsRebuilt.Notify()
+    history.waitForReads(t, reads)
+    waitForChart(t, func() bool { return chart.view != zoomed })
+}
+
+func TestChartCaption(t *testing.T) {
+    now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+    data := HEXJSON{{CurrentDay: 41}, {CurrentDay: 42}, {CurrentDay: 40}} // Not sorted
+    tests := []struct {
+        data     HEXJSON
+        source   string
+        lastSync time.Time
+        want     string
+    }{
+        {data, defaultAPIBaseURL, now.Add(-5 * time.Minute), "Source: " + defaultAPIBaseURL + " - newest day 42 - last synced 5 min ago"},
+        {data, "https://fallback.example", now.Add(-3 * time.Hour), "Source: https://fallback.example - newest day 42 - last synced 3 h ago"},
+        {data, "", time.Time{}, "Source: HEXDailyStats API - newest day 42 - not synced since launch"},
+        {nil, defaultAPIBaseURL, now, "Source: " + defaultAPIBaseURL + " - no history yet - last synced just now"},
+    }
+    for _, tt := range tests {
+        if got := chartCaption(tt.data, tt.source, tt.lastSync, now); got != tt.want {
+            t.Errorf("chartCaption = %q, want %q", got, tt.want)
+        }
+    }
+}
+
+// A chart configured larger than the window shrinks to fit instead of forcing its size
+func TestChartFitsSmallWindow(t *testing.T) {
+    useTestApp(t)
+    useHistoryStore(t, testHistory(), nil)
+    useConfig(t, func(c *Config) {
+        c.ChartWidth = 1200
+        c.ChartHeight = 800
+        c.ChartShowSource = false
+    })
+    w := test.NewWindow(nil)
+    defer w.Close()
+
+    tab := createChartTab(w)
+    _, chart, _ := chartTabParts(tab)
+    if chart == nil {
+        t.Fatal("chart tab has no chart")
+    }
+    if got, want := chart.MinSize(), fyne.NewSize(300, 200); got != want {
+        t.Errorf("chart min size = %v, want %v with the same aspect ratio", got, want)
+    }
+    if min := tab.MinSize(); min.Width >= 1200 || min.Height > 480 {
+        t.Errorf("chart tab asks for %v, the chart keeps it from fitting a 640x480 window", min)
+    }
+    w.SetContent(tab)
+    w.Resize(fyne.NewSize(640, 480))
+    if size := chart.Size(); size.Width > 640 || size.Height > 480 || size.Width < 300 {
+        t.Errorf("chart laid out at %v, want it between its min size and the window", size)
+    }
+}
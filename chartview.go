@@ -0,0 +1,388 @@
+//go:build !nocharts
+
+package main
+
+import (
+    "fmt"
+    "image/color"
+    "math"
+    "sort"
+    "strconv"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "fyne.io/fyne/v2/driver/desktop"
+    "fyne.io/fyne/v2/theme"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Interactive chart of the Charts tab, drawn with fyne so it can zoom (scroll wheel), pan
+// (drag) and show the value under the mouse. Exports still go through go-chart.
+
+var chartRangeDays = map[string]int{"7d": 7, "30d": 30, "90d": 90, "1y": 365}
+
+const (
+    minChartSpan     = 2    // Fewest days a zoom can show
+    chartZoomStep    = 0.85 // Span kept per scroll step when zooming in
+    chartMarkerLimit = 120  // Markers are only drawn up to this many visible points
+)
+
+type chartPoint struct {
+    Day   int
+    Value float64
+}
+
+// Points of field over the history, oldest first
+func chartSeries(data HEXJSON, field string) []chartPoint {
+    points := make([]chartPoint, 0, len(data))
+    for _, entry := range data {
+        point := chartPoint{Day: entry.CurrentDay}
+        switch field {
+        case "pricePulseX":
+            point.Value = entry.Price()
+        case "tshareRateHEX":
+            point.Value = entry.TshareRateHEX
+        case "dailyPayoutHEX":
+            point.Value = entry.DailyPayoutHEX
+        }
+        points = append(points, point)
+    }
+    sort.Slice(points, func(i, j int) bool {
+        return points[i].Day < points[j].Day
+    })
+    return points
+}
+
+// Days on screen, From and To included
+type chartViewport struct {
+    From float64
+    To   float64
+}
+
+func (v chartViewport) Span() float64 {
+    return v.To - v.From
+}
+
+// The last days of range (a chartRanges name) up to the newest point
+func rangeViewport(points []chartPoint, rangeName string) chartViewport {
+    if len(points) == 0 {
+        return chartViewport{}
+    }
+    first, last := float64(points[0].Day), float64(points[len(points)-1].Day)
+    view := chartViewport{From: first, To: last}
+    if days, ok := chartRangeDays[rangeName]; ok {
+        view.From = math.Max(first, last-float64(days-1))
+    }
+    return view
+}
+
+// Keeps view inside the history and at least minChartSpan days wide, moving it rather than shrinking it
+func clampViewport(view chartViewport, points []chartPoint) chartViewport {
+    if len(points) == 0 {
+        return chartViewport{}
+    }
+    first, last := float64(points[0].Day), float64(points[len(points)-1].Day)
+    span := math.Min(math.Max(view.Span(), minChartSpan), last-first)
+    if view.From < first {
+        view.From = first
+    }
+    view.To = view.From + span
+    if view.To > last {
+        view.To = last
+        view.From = last - span
+    }
+    return view
+}
+
+// Scales the span by factor keeping the day at anchor (0 left edge, 1 right edge) in place
+func zoomViewport(view chartViewport, factor, anchor float64) chartViewport {
+    pivot := view.From + view.Span()*anchor
+    return chartViewport{From: pivot - (pivot-view.From)*factor, To: pivot + (view.To-pivot)*factor}
+}
+
+func panViewport(view chartViewport, days float64) chartViewport {
+    return chartViewport{From: view.From + days, To: view.To + days}
+}
+
+// Points inside view, thinned to about maxPoints so wide ranges stay cheap to draw
+func visiblePoints(points []chartPoint, view chartViewport, maxPoints int) []chartPoint {
+    var visible []chartPoint
+    for _, point := range points {
+        if float64(point.Day) >= view.From && float64(point.Day) <= view.To {
+            visible = append(visible, point)
+        }
+    }
+    if maxPoints < 2 || len(visible) <= maxPoints {
+        return visible
+    }
+    stride := int(math.Ceil(float64(len(visible)) / float64(maxPoints)))
+    thinned := make([]chartPoint, 0, maxPoints+1)
+    for i := 0; i < len(visible); i += stride {
+        thinned = append(thinned, visible[i])
+    }
+    if thinned[len(thinned)-1] != visible[len(visible)-1] {
+        thinned = append(thinned, visible[len(visible)-1]) // Keep the newest day
+    }
+    return thinned
+}
+
+// Point closest to day, ok is false without points
+func nearestPoint(points []chartPoint, day float64) (chartPoint, bool) {
+    if len(points) == 0 {
+        return chartPoint{}, false
+    }
+    best := points[0]
+    for _, point := range points[1:] {
+        if math.Abs(float64(point.Day)-day) < math.Abs(float64(best.Day)-day) {
+            best = point
+        }
+    }
+    return best, true
+}
+
+func valueRange(points []chartPoint) (float64, float64) {
+    low, high := math.Inf(1), math.Inf(-1)
+    for _, point := range points {
+        low = math.Min(low, point.Value)
+        high = math.Max(high, point.Value)
+    }
+    if high == low { // A flat line still gets some height
+        pad := math.Max(math.Abs(high)*0.05, 1e-9)
+        return low - pad, high + pad
+    }
+    return low, high
+}
+
+// Axis and tooltip values, small prices need more digits than HEX amounts
+func formatChartValue(value float64) string {
+    if math.Abs(value) >= 1000 {
+        return formatWithCommas(int(math.Round(value)))
+    }
+    if math.Abs(value) >= 1 {
+        return strconv.FormatFloat(value, 'f', 2, 64)
+    }
+    return strconv.FormatFloat(value, 'g', 4, 64)
+}
+
+type interactiveChart struct {
+    widget.BaseWidget
+    points      []chartPoint
+    view        chartViewport
+    showMarkers bool
+    showToday   bool
+    minSize     fyne.Size
+    hoverX      float32
+    hovering    bool
+}
+
+func newInteractiveChart(minSize fyne.Size) *interactiveChart {
+    c := &interactiveChart{minSize: minSize}
+    c.ExtendBaseWidget(c)
+    return c
+}
+
+// Replaces the series and shows rangeName of it
+func (c *interactiveChart) SetData(points []chartPoint, rangeName string, opts chartOptions) {
+    c.points = points
+    c.showMarkers = opts.ShowMarkers
+    c.showToday = opts.ShowToday
+    c.view = rangeViewport(points, rangeName)
+    c.Refresh()
+}
+
+func (c *interactiveChart) ShowRange(rangeName string) {
+    c.view = rangeViewport(c.points, rangeName)
+    c.Refresh()
+}
+
+// Whole days on screen, for exporting what is shown
+func (c *interactiveChart) VisibleDays() (int, int) {
+    return int(math.Ceil(c.view.From)), int(math.Floor(c.view.To))
+}
+
+// Plot area inside the widget, room is left for the axis labels
+func (c *interactiveChart) plotArea(size fyne.Size) (fyne.Position, fyne.Size) {
+    left, bottom, top := float32(90), float32(30), float32(10)
+    return fyne.NewPos(left, top), fyne.NewSize(fyne.Max(size.Width-left-10, 1), fyne.Max(size.Height-top-bottom, 1))
+}
+
+func (c *interactiveChart) Scrolled(event *fyne.ScrollEvent) {
+    if len(c.points) < 2 {
+        return
+    }
+    origin, area := c.plotArea(c.Size())
+    anchor := float64((event.Position.X - origin.X) / area.Width)
+    anchor = math.Min(math.Max(anchor, 0), 1)
+    factor := chartZoomStep
+    if event.Scrolled.DY < 0 {
+        factor = 1 / chartZoomStep
+    }
+    c.view = clampViewport(zoomViewport(c.view, factor, anchor), c.points)
+    c.Refresh()
+}
+
+func (c *interactiveChart) Dragged(event *fyne.DragEvent) {
+    if len(c.points) < 2 {
+        return
+    }
+    _, area := c.plotArea(c.Size())
+    days := -float64(event.Dragged.DX/area.Width) * c.view.Span()
+    c.view = clampViewport(panViewport(c.view, days), c.points)
+    c.hoverX, c.hovering = event.Position.X, true
+    c.Refresh()
+}
+
+func (c *interactiveChart) DragEnd() {}
+
+func (c *interactiveChart) MouseIn(event *desktop.MouseEvent) {
+    c.MouseMoved(event)
+}
+
+func (c *interactiveChart) MouseMoved(event *desktop.MouseEvent) {
+    c.hoverX, c.hovering = event.Position.X, true
+    c.Refresh()
+}
+
+func (c *interactiveChart) MouseOut() {
+    c.hovering = false
+    c.Refresh()
+}
+
+func (c *interactiveChart) MinSize() fyne.Size {
+    return c.minSize
+}
+
+func (c *interactiveChart) CreateRenderer() fyne.WidgetRenderer {
+    return &interactiveChartRenderer{chart: c}
+}
+
+type interactiveChartRenderer struct {
+    chart   *interactiveChart
+    objects []fyne.CanvasObject
+}
+
+func (r *interactiveChartRenderer) Layout(size fyne.Size) {
+    r.build(size)
+}
+
+func (r *interactiveChartRenderer) MinSize() fyne.Size {
+    return r.chart.MinSize()
+}
+
+func (r *interactiveChartRenderer) Refresh() {
+    r.build(r.chart.Size())
+    canvas.Refresh(r.chart)
+}
+
+func (r *interactiveChartRenderer) Objects() []fyne.CanvasObject {
+    return r.objects
+}
+
+func (r *interactiveChartRenderer) Destroy() {}
+
+func chartText(text string, size float32) *canvas.Text {
+    label := canvas.NewText(text, theme.Color(theme.ColorNameForeground))
+    label.TextSize = size
+    return label
+}
+
+// Recreates the drawing for size, cheap enough for the few hundred lines on screen
+func (r *interactiveChartRenderer) build(size fyne.Size) {
+    c := r.chart
+    r.objects = r.objects[:0]
+    origin, area := c.plotArea(size)
+    if len(c.points) == 0 || size.Width <= 0 {
+        return
+    }
+    points := visiblePoints(c.points, c.view, int(area.Width/2))
+    if len(points) == 0 {
+        return
+    }
+    low, high := valueRange(points)
+    span := math.Max(c.view.Span(), 1)
+    toPos := func(day, value float64) fyne.Position {
+        x := origin.X + float32((day-c.view.From)/span)*area.Width
+        y := origin.Y + area.Height - float32((value-low)/(high-low))*area.Height
+        return fyne.NewPos(x, y)
+    }
+    axisColor := theme.Color(theme.ColorNameDisabled)
+    lineColor := theme.Color(theme.ColorNamePrimary)
+    textSize := theme.CaptionTextSize()
+
+    xAxis := canvas.NewLine(axisColor)
+    xAxis.Position1 = fyne.NewPos(origin.X, origin.Y+area.Height)
+    xAxis.Position2 = fyne.NewPos(origin.X+area.Width, origin.Y+area.Height)
+    yAxis := canvas.NewLine(axisColor)
+    yAxis.Position1 = origin
+    yAxis.Position2 = fyne.NewPos(origin.X, origin.Y+area.Height)
+    r.objects = append(r.objects, xAxis, yAxis)
+
+    // Value labels at the top and bottom, day labels at both ends
+    for _, value := range []float64{high, low} {
+        label := chartText(formatChartValue(value), textSize)
+        labelSize := label.MinSize()
+        label.Move(fyne.NewPos(origin.X-labelSize.Width-6, toPos(c.view.From, value).Y-labelSize.Height/2))
+        r.objects = append(r.objects, label)
+    }
+    fromLabel := chartText(fmt.Sprintf("Day %d", points[0].Day), textSize)
+    fromLabel.Move(fyne.NewPos(origin.X, origin.Y+area.Height+4))
+    toLabel := chartText(fmt.Sprintf("Day %d", points[len(points)-1].Day), textSize)
+    toLabel.Move(fyne.NewPos(origin.X+area.Width-toLabel.MinSize().Width, origin.Y+area.Height+4))
+    r.objects = append(r.objects, fromLabel, toLabel)
+
+    if c.showToday {
+        latest := c.points[len(c.points)-1]
+        if float64(latest.Day) <= c.view.To {
+            today := canvas.NewLine(color.NRGBA{R: 0xd0, G: 0x30, B: 0x30, A: 0xff})
+            x := toPos(float64(latest.Day), 0).X
+            today.Position1 = fyne.NewPos(x, origin.Y)
+            today.Position2 = fyne.NewPos(x, origin.Y+area.Height)
+            r.objects = append(r.objects, today)
+        }
+    }
+
+    for i := 1; i < len(points); i++ {
+        segment := canvas.NewLine(lineColor)
+        segment.StrokeWidth = 2
+        segment.Position1 = toPos(float64(points[i-1].Day), points[i-1].Value)
+        segment.Position2 = toPos(float64(points[i].Day), points[i].Value)
+        r.objects = append(r.objects, segment)
+    }
+    if c.showMarkers && len(points) <= chartMarkerLimit {
+        for _, point := range points {
+            marker := canvas.NewCircle(lineColor)
+            marker.Resize(fyne.NewSize(5, 5))
+            marker.Move(toPos(float64(point.Day), point.Value).SubtractXY(2.5, 2.5))
+            r.objects = append(r.objects, marker)
+        }
+    }
+
+    if !c.hovering || c.hoverX < origin.X || c.hoverX > origin.X+area.Width {
+        return
+    }
+    day := c.view.From + float64((c.hoverX-origin.X)/area.Width)*span
+    point, ok := nearestPoint(points, day)
+    if !ok {
+        return
+    }
+    position := toPos(float64(point.Day), point.Value)
+    guide := canvas.NewLine(axisColor)
+    guide.Position1 = fyne.NewPos(position.X, origin.Y)
+    guide.Position2 = fyne.NewPos(position.X, origin.Y+area.Height)
+    dot := canvas.NewCircle(lineColor)
+    dot.Resize(fyne.NewSize(8, 8))
+    dot.Move(position.SubtractXY(4, 4))
+    tooltip := chartText(fmt.Sprintf("Day %d: %s", point.Day, formatChartValue(point.Value)), theme.TextSize())
+    tooltipSize := tooltip.MinSize().AddWidthHeight(12, 6)
+    tooltipPos := fyne.NewPos(position.X+8, origin.Y+4)
+    if tooltipPos.X+tooltipSize.Width > origin.X+area.Width {
+        tooltipPos.X = position.X - 8 - tooltipSize.Width // Flip to the left near the right edge
+    }
+    background := canvas.NewRectangle(theme.Color(theme.ColorNameOverlayBackground))
+    background.StrokeColor = axisColor
+    background.StrokeWidth = 1
+    background.Resize(tooltipSize)
+    background.Move(tooltipPos)
+    tooltip.Move(tooltipPos.AddXY(6, 3))
+    r.objects = append(r.objects, guide, dot, background, tooltip)
+}
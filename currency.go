@@ -0,0 +1,148 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "strconv"
+    "sync"
+    "time"
+)
+
+// Display currencies, all values from the API are in USD
+var currencies = []string{"USD", "EUR", "GBP", "JPY", "CAD", "AUD", "CHF"}
+
+var currencySymbols = map[string]string{
+    "USD": "$",
+    "EUR": "€",
+    "GBP": "£",
+    "JPY": "¥",
+}
+
+// Conventional decimals of amounts in a currency, values are formatted as if in USD otherwise
+var currencyDecimalPlaces = map[string]int{
+    "JPY": 0,
+}
+
+const maxMoneyDecimals = 4
+
+func currencyDecimals(code string) int {
+    if decimals, ok := currencyDecimalPlaces[code]; ok {
+        return decimals
+    }
+    return 2
+}
+
+// Decimals for an amount shown with usdDecimals in USD, shifted by how many fewer (or more)
+// decimals the currency uses. override -1 follows the currency.
+func moneyDecimals(code string, usdDecimals, override int) int {
+    base := currencyDecimals(code)
+    if override >= 0 {
+        base = override
+    }
+    decimals := usdDecimals + base - currencyDecimals("USD")
+    if decimals < 0 {
+        return 0
+    }
+    return decimals
+}
+
+// Last good FX rate, kept when a refresh fails so values don't jump back to USD
+type fxRateCache struct {
+    mu        sync.Mutex
+    code      string
+    rate      float64
+    fetchedAt time.Time
+    stale     bool
+}
+
+var fxCache = &fxRateCache{}
+
+type fxResponse struct {
+    Result string             `json:"result"`
+    Rates  map[string]float64 `json:"rates"`
+}
+
+func fetchFXRate(code string) (float64, error) {
+    resp, err := httpGet("https://open.er-api.com/v6/latest/USD")
+    if err != nil {
+        return 0, err
+    }
+    defer resp.Body.Close()
+    var data fxResponse
+    err = json.NewDecoder(resp.Body).Decode(&data)
+    if err != nil {
+        return 0, err
+    }
+    rate, ok := data.Rates[code]
+    if !ok || rate <= 0 {
+        return 0, fmt.Errorf("no FX rate for %s", code)
+    }
+    return rate, nil
+}
+
+// Stores the outcome of a rate fetch, a failure keeps the previous rate marked stale
+func (c *fxRateCache) update(code string, rate float64, err error, now time.Time) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if err != nil {
+        if c.code == code && c.rate > 0 {
+            c.stale = true
+        }
+        return
+    }
+    c.code = code
+    c.rate = rate
+    c.fetchedAt = now
+    c.stale = false
+}
+
+// Rate to use for code, falls back to USD if no rate was ever fetched
+func (c *fxRateCache) lookup(code string) (string, float64, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    if code == "USD" || c.code != code || c.rate <= 0 {
+        return "USD", 1, false
+    }
+    return c.code, c.rate, c.stale
+}
+
+func refreshFXRate() {
+    code := configManager.GetConfig().Currency
+    if code == "USD" {
+        return
+    }
+    rate, err := fetchFXRate(code)
+    recordFetch("FX rate", err)
+    if err != nil {
+        log.Println("Error fetching FX rate:", err)
+    }
+    fxCache.update(code, rate, err, time.Now())
+}
+
+func isCurrency(code string) bool {
+    return contains(currencies, code)
+}
+
+// Converts a USD amount into the display currency
+func formatMoney(usd float64, decimals int) string {
+    config := configManager.GetConfig()
+    code, rate, _ := fxCache.lookup(config.Currency)
+    amount := strconv.FormatFloat(usd*rate, 'f', moneyDecimals(code, decimals, config.MoneyDecimals), 64)
+    if symbol, ok := currencySymbols[code]; ok {
+        return symbol + amount
+    }
+    return code + " " + amount
+}
+
+// Note shown next to converted values while the FX rate could not be refreshed
+func fxStaleNote() string {
+    _, _, stale := fxCache.lookup(configManager.GetConfig().Currency)
+    if !stale {
+        return ""
+    }
+    fxCache.mu.Lock()
+    fetchedAt := fxCache.fetchedAt
+    fxCache.mu.Unlock()
+    return fmt.Sprintf("FX rate stale (last updated %s)", fetchedAt.Format("02-01-2006 15:04"))
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+    "errors"
+    "testing"
+    "time"
+)
+
+func TestFXRateCacheFallback(t *testing.T) {
+    now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+    failed := errors.New("timeout")
+    c := &fxRateCache{}
+
+    // Nothing fetched yet, so values stay in USD
+    c.update("EUR", 0, failed, now)
+    if code, rate, stale := c.lookup("EUR"); code != "USD" || rate != 1 || stale {
+        t.Errorf("lookup before any rate = %s %v %v, want USD 1 false", code, rate, stale)
+    }
+
+    c.update("EUR", 0.9, nil, now)
+    if code, rate, stale := c.lookup("EUR"); code != "EUR" || rate != 0.9 || stale {
+        t.Errorf("lookup after a fetch = %s %v %v, want EUR 0.9 false", code, rate, stale)
+    }
+
+    // A failed refresh keeps the last good rate, marked stale
+    c.update("EUR", 0, failed, now.Add(time.Hour))
+    if code, rate, stale := c.lookup("EUR"); code != "EUR" || rate != 0.9 || !stale {
+        t.Errorf("lookup after a failed refresh = %s %v %v, want EUR 0.9 true", code, rate, stale)
+    }
+    if !c.fetchedAt.Equal(now) {
+        t.Errorf("fetchedAt = %v, want the time of the last good fetch", c.fetchedAt)
+    }
+
+    c.update("EUR", 0.95, nil, now.Add(2*time.Hour))
+    if _, rate, stale := c.lookup("EUR"); rate != 0.95 || stale {
+        t.Errorf("lookup after recovering = %v %v, want 0.95 false", rate, stale)
+    }
+
+    // The cached rate is for another currency, so it must not be used
+    if code, rate, _ := c.lookup("GBP"); code != "USD" || rate != 1 {
+        t.Errorf("lookup of another currency = %s %v, want USD 1", code, rate)
+    }
+    c.update("GBP", 0, failed, now)
+    if _, _, stale := c.lookup("EUR"); stale {
+        t.Error("a failed fetch of another currency marked the cached rate stale")
+    }
+}
+
+func TestCurrencyDecimals(t *testing.T) {
+    tests := []struct {
+        code string
+        want int
+    }{
+        {"USD", 2},
+        {"EUR", 2},
+        {"GBP", 2},
+        {"JPY", 0},
+        {"CHF", 2},
+        {"XYZ", 2}, // Unknown, formatted like USD
+    }
+    for _, tt := range tests {
+        if got := currencyDecimals(tt.code); got != tt.want {
+            t.Errorf("currencyDecimals(%s) = %d, want %d", tt.code, got, tt.want)
+        }
+    }
+}
+
+func TestMoneyDecimals(t *testing.T) {
+    tests := []struct {
+        code        string
+        usdDecimals int
+        override    int
+        want        int
+    }{
+        {"USD", 2, -1, 2},
+        {"EUR", 2, -1, 2},
+        {"JPY", 2, -1, 0},
+        {"JPY", 4, -1, 2}, // Small prices keep their extra digits
+        {"JPY", 2, 2, 2},  // The user's choice wins over the currency's
+        {"USD", 2, 0, 0},
+        {"USD", 4, 3, 5},
+    }
+    for _, tt := range tests {
+        if got := moneyDecimals(tt.code, tt.usdDecimals, tt.override); got != tt.want {
+            t.Errorf("moneyDecimals(%s, %d, %d) = %d, want %d", tt.code, tt.usdDecimals, tt.override, got, tt.want)
+        }
+    }
+}
+
+func TestFormatMoneyCurrencies(t *testing.T) {
+    previous := fxCache
+    t.Cleanup(func() {
+        fxCache = previous
+    })
+    now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+    tests := []struct {
+        code string
+        rate float64
+        want string
+    }{
+        {"USD", 1, "$1234.56"},
+        {"EUR", 0.9, "€1111.10"},
+        {"JPY", 150, "¥185184"},
+        {"CHF", 0.8, "CHF 987.65"},
+    }
+    for _, tt := range tests {
+        useConfig(t, func(c *Config) {
+            c.Currency = tt.code
+        })
+        fxCache = &fxRateCache{}
+        fxCache.update(tt.code, tt.rate, nil, now)
+        if got := formatMoney(1234.56, 2); got != tt.want {
+            t.Errorf("formatMoney in %s = %q, want %q", tt.code, got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,153 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "log"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/layout"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Dashboard: the key numbers of the other tabs on one screen
+
+// Countdown text to the next maturity, or why there is none
+func nextMaturityText(summary portfolioSummary, now time.Time) string {
+    if summary.NextMaturity.IsZero() {
+        if summary.MaturedCount > 0 {
+            return fmt.Sprintf("%d matured, waiting to be ended", summary.MaturedCount)
+        }
+        return "-"
+    }
+    days, err := daysLeftAt(summary.NextMaturity.Format(dateLayout), now)
+    if err != nil {
+        return "-"
+    }
+    if days == 0 {
+        return fmt.Sprintf("%s (today)", summary.NextMaturity.Format(dateLayout))
+    }
+    return fmt.Sprintf("%s (in %s)", summary.NextMaturity.Format(dateLayout), formatDuration(days))
+}
+
+func createDashboardTab(miners []Miner) fyne.CanvasObject {
+    valueLabel := newLiveDataValueLabel()
+    tSharesLabel := newLiveDataValueLabel()
+    minersLabel := newLiveDataValueLabel()
+    maturityLabel := newLiveDataValueLabel()
+    priceLabel := newLiveDataValueLabel()
+    tsharePriceLabel := newLiveDataValueLabel()
+
+    chartImage := canvas.NewImageFromFile("")
+    chartImage.FillMode = canvas.ImageFillContain
+    chartImage.SetMinSize(chartMinSize(configManager.GetConfig().ChartWidth, configManager.GetConfig().ChartHeight))
+
+    update := func() {
+        liveDataMutex.Lock()
+        data := latestLiveData
+        direction, hasTrend := priceTrend(priceSamples)
+        liveDataMutex.Unlock()
+        now := time.Now()
+        summary := currentPortfolioSummary(miners, data, now)
+        trend := ""
+        if hasTrend {
+            trend = fmt.Sprintf(" %s (1h)", priceTrendArrow(direction))
+        }
+        if chainPricesKnown(summary.ChainTShares, data, 0) {
+            valueLabel.SetText(maskPrivate(formatMoney(summary.TotalValue, 2)) + trend)
+        } else {
+            valueLabel.SetText(calculatingText)
+        }
+        tSharesLabel.SetText(formatTShares(summary.TotalTShares))
+        minersLabel.SetText(fmt.Sprintf("%d active, %d completed", summary.ActiveCount, summary.CompletedCount))
+        maturityLabel.SetText(nextMaturityText(summary, now))
+        chain := configManager.GetConfig().Chain
+        chainData := data.Chain(chain)
+        priceLabel.SetText(formatMoney(chainData.Price, priceDecimals))
+        if chain == chainPulsechain { // The trend is kept of the PulseChain T-Share price
+            tsharePriceLabel.SetText(formatMoney(chainData.TsharePrice, tsharePriceDecimals) + trend)
+        } else {
+            tsharePriceLabel.SetText(formatMoney(chainData.TsharePrice, tsharePriceDecimals))
+        }
+    }
+    update()
+
+    // Same rendering as the Charts tab, always the price over the history
+    renders := &renderGeneration{}
+    updateChart := func() {
+        gen := renders.Next()
+        opts := chartOptionsFromConfig(configManager.GetConfig())
+        chartLoads.Add(1)
+        go func() {
+            defer chartLoads.Done()
+            if !renders.IsLatest(gen) {
+                return
+            }
+            png, err := renderChartPNG(defaultChartField, opts)
+            if err != nil || png == nil {
+                return
+            }
+            fyne.Do(func() {
+                if !renders.IsLatest(gen) {
+                    return
+                }
+                chartImage.Resource = fyne.NewStaticResource("dashboard-chart", png)
+                chartImage.Refresh()
+            })
+        }()
+    }
+    updateChart()
+
+    ctx, cancel := context.WithCancel(context.Background())
+    go func() {
+        frequency := configManager.GetLiveDataFrequency()
+        ticker := time.NewTicker(time.Duration(frequency) * time.Minute)
+        changeCh := configManager.Subscribe()
+        liveCh := liveDataUpdated.Subscribe()
+        syncCh := historySynced.Subscribe()
+        defer configManager.Unsubscribe(changeCh)
+        defer liveDataUpdated.Unsubscribe(liveCh)
+        defer historySynced.Unsubscribe(syncCh)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-liveCh:
+                fyne.DoAndWait(update)
+            case <-ticker.C:
+                fyne.DoAndWait(update)
+                frequency = configManager.GetLiveDataFrequency()
+                ticker.Reset(time.Duration(frequency) * time.Minute)
+            case <-changeCh:
+                frequency = configManager.GetLiveDataFrequency()
+                ticker.Reset(time.Duration(frequency) * time.Minute)
+            case <-syncCh:
+                fyne.Do(updateChart)
+            case <-ctx.Done():
+                log.Println("Dashboard tab ticker stopped")
+                return
+            }
+        }
+    }()
+
+    portfolio := widget.NewCard("Portfolio", "", container.New(layout.NewFormLayout(),
+        widget.NewLabel("Total T-Shares Value"), valueLabel,
+        widget.NewLabel("Total T-Shares"), tSharesLabel,
+        widget.NewLabel("Miners"), minersLabel,
+        widget.NewLabel("Next Maturity"), maturityLabel,
+    ))
+    market := widget.NewCard("Live Data", "", container.New(layout.NewFormLayout(),
+        widget.NewLabel("Price"), priceLabel,
+        widget.NewLabel("T-Share Price"), tsharePriceLabel,
+    ))
+    historyCard := widget.NewCard("Price History", "", chartImage)
+    if !chartsAvailable {
+        historyCard.Hide()
+    }
+    return withTabWork(container.NewVScroll(container.NewVBox(
+        container.NewGridWithColumns(2, portfolio, market),
+        historyCard,
+    )), cancel)
+}
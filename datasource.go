@@ -0,0 +1,189 @@
+package main
+
+import (
+    "fmt"
+    "io"
+    "log"
+    "net/http"
+    "sort"
+    "strings"
+    "sync"
+    "time"
+)
+
+// HEXDailyStats API sources: the configured base URL first, then the fallbacks in order.
+// A fetch moves on to the next source when one can't be reached or answers with something
+// that doesn't decode. A source that just failed is tried last for a while so a site that
+// is down doesn't delay every fetch.
+
+const (
+    maxFallbackAPIURLs = 5
+    sourceRetryAfter   = 5 * time.Minute // How long a failed source is tried last
+)
+
+type sourceHealth struct {
+    LastOK       time.Time
+    LastFail     time.Time
+    FailingSince time.Time // First of the failures in a row
+    LastError    string
+    Failures     int // Failed fetches in a row
+}
+
+var sourceHealthMu sync.Mutex
+var sourceHealths = map[string]*sourceHealth{}
+var pathSources = map[string]string{} // Source that last answered each path
+
+// Notified when a source succeeds or fails, for the status on the Live Data tab
+var sourceHealthUpdated = &notifier{}
+
+func recordSourceResult(source string, err error, now time.Time) {
+    sourceHealthMu.Lock()
+    health := sourceHealths[source]
+    if health == nil {
+        health = &sourceHealth{}
+        sourceHealths[source] = health
+    }
+    if err != nil {
+        if health.Failures == 0 {
+            health.FailingSince = now
+        }
+        health.LastFail = now
+        health.LastError = err.Error()
+        health.Failures++
+    } else {
+        health.LastOK = now
+        health.Failures = 0
+    }
+    sourceHealthMu.Unlock()
+    sourceHealthUpdated.Notify()
+}
+
+// Source the last successful fetch of path came from, "" before there was one
+func answeredBy(path string) string {
+    sourceHealthMu.Lock()
+    defer sourceHealthMu.Unlock()
+    return pathSources[path]
+}
+
+func sourceHealthOf(source string) sourceHealth {
+    sourceHealthMu.Lock()
+    defer sourceHealthMu.Unlock()
+    if health := sourceHealths[source]; health != nil {
+        return *health
+    }
+    return sourceHealth{}
+}
+
+// Primary and fallback base URLs, without duplicates
+func apiSources(config Config) []string {
+    sources := []string{config.APIBaseURL}
+    for _, source := range config.FallbackAPIURLs {
+        if !contains(sources, source) {
+            sources = append(sources, source)
+        }
+    }
+    return sources
+}
+
+// Sources in the order to try them, those that failed within sourceRetryAfter go last
+func sourceOrder(sources []string, now time.Time) []string {
+    ordered := append([]string(nil), sources...)
+    recentlyFailed := func(source string) bool {
+        health := sourceHealthOf(source)
+        return health.Failures > 0 && now.Sub(health.LastFail) < sourceRetryAfter
+    }
+    sort.SliceStable(ordered, func(i, j int) bool {
+        return !recentlyFailed(ordered[i]) && recentlyFailed(ordered[j])
+    })
+    return ordered
+}
+
+// Body of GET source+path, an error unless the answer is 200 OK
+func fetchSource(source, path string) ([]byte, error) {
+    resp, err := httpGet(source + path)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("HTTP %d", resp.StatusCode)
+    }
+    return io.ReadAll(resp.Body)
+}
+
+// Fetches path from the first source whose answer decode accepts. The error lists what
+// went wrong with each source when none worked.
+func fetchWithFailover(path string, decode func(body []byte) error) error {
+    var errs []string
+    for _, source := range sourceOrder(apiSources(configManager.GetConfig()), time.Now()) {
+        body, err := fetchSource(source, path)
+        if err == nil {
+            err = decode(body)
+        }
+        recordSourceResult(source, err, time.Now())
+        if err == nil {
+            sourceHealthMu.Lock()
+            pathSources[path] = source
+            sourceHealthMu.Unlock()
+            if len(errs) > 0 {
+                log.Println("Fetched", path, "from fallback", source)
+            }
+            return nil
+        }
+        errs = append(errs, fmt.Sprintf("%s: %v", source, err))
+    }
+    return fmt.Errorf("%s", strings.Join(errs, "; "))
+}
+
+// Fallback URLs entered one per line in Settings. Invalid lines and those past
+// maxFallbackAPIURLs are left out, the error names them.
+func parseFallbackAPIURLs(text string) ([]string, error) {
+    var urls, errs []string
+    for _, line := range strings.Split(text, "\n") {
+        if strings.TrimSpace(line) == "" {
+            continue
+        }
+        url, err := validateAPIBaseURL(line)
+        if err != nil {
+            errs = append(errs, fmt.Sprintf("%s: %v", strings.TrimSpace(line), err))
+            continue
+        }
+        if !contains(urls, url) {
+            urls = append(urls, url)
+        }
+    }
+    if len(urls) > maxFallbackAPIURLs {
+        errs = append(errs, fmt.Sprintf("at most %d fallback URLs", maxFallbackAPIURLs))
+        urls = urls[:maxFallbackAPIURLs]
+    }
+    if len(errs) > 0 {
+        return urls, fmt.Errorf("%s", strings.Join(errs, "; "))
+    }
+    return urls, nil
+}
+
+// Status line of a source for the Live Data tab
+func sourceStatusText(source string, now time.Time) string {
+    health := sourceHealthOf(source)
+    switch {
+    case health.LastOK.IsZero() && health.LastFail.IsZero():
+        return "not used yet"
+    case health.Failures == 0:
+        return "OK, " + syncAgeText(now.Sub(health.LastOK))
+    case health.LastOK.IsZero():
+        return fmt.Sprintf("failing (%s)", health.LastError)
+    }
+    return fmt.Sprintf("failing (%s), first failure %s, last OK %s", health.LastError, syncAgeText(now.Sub(health.FailingSince)), syncAgeText(now.Sub(health.LastOK)))
+}
+
+func syncAgeText(age time.Duration) string {
+    switch {
+    case age < time.Minute:
+        return "just now"
+    case age < time.Hour:
+        return fmt.Sprintf("%d min ago", int(age.Minutes()))
+    case age < 48*time.Hour:
+        return fmt.Sprintf("%d h ago", int(age.Hours()))
+    }
+    return fmt.Sprintf("%d days ago", int(age.Hours()/24))
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+    "errors"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "testing"
+    "time"
+)
+
+// Runs the test with no source results recorded, restored afterwards
+func useSourceHealth(t *testing.T) {
+    t.Helper()
+    sourceHealthMu.Lock()
+    previous := sourceHealths
+    sourceHealths = map[string]*sourceHealth{}
+    sourceHealthMu.Unlock()
+    t.Cleanup(func() {
+        sourceHealthMu.Lock()
+        sourceHealths = previous
+        sourceHealthMu.Unlock()
+    })
+}
+
+func TestSourceOrder(t *testing.T) {
+    useSourceHealth(t)
+    now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+    sources := []string{"https://a.example", "https://b.example", "https://c.example"}
+    if got := sourceOrder(sources, now); !reflect.DeepEqual(got, sources) {
+        t.Errorf("order without failures = %q, want the configured order", got)
+    }
+
+    recordSourceResult("https://a.example", errors.New("HTTP 502"), now.Add(-time.Minute))
+    want := []string{"https://b.example", "https://c.example", "https://a.example"}
+    if got := sourceOrder(sources, now); !reflect.DeepEqual(got, want) {
+        t.Errorf("order after a failure = %q, want %q", got, want)
+    }
+    if got := sourceOrder(sources, now.Add(sourceRetryAfter)); !reflect.DeepEqual(got, sources) {
+        t.Errorf("order once the failure is old = %q, want the configured order again", got)
+    }
+
+    recordSourceResult("https://b.example", errors.New("timeout"), now.Add(-2*time.Minute))
+    want = []string{"https://c.example", "https://a.example", "https://b.example"} // Failed ones keep their order
+    if got := sourceOrder(sources, now); !reflect.DeepEqual(got, want) {
+        t.Errorf("order after two failures = %q, want %q", got, want)
+    }
+    recordSourceResult("https://a.example", nil, now)
+    want = []string{"https://a.example", "https://c.example", "https://b.example"}
+    if got := sourceOrder(sources, now); !reflect.DeepEqual(got, want) {
+        t.Errorf("order after a recovery = %q, want %q", got, want)
+    }
+    if !reflect.DeepEqual(sources, []string{"https://a.example", "https://b.example", "https://c.example"}) {
+        t.Error("sourceOrder changed the slice it was given")
+    }
+}
+
+// The fallback that answered is remembered for the chart caption
+func TestFetchWithFailoverAnsweredBy(t *testing.T) {
+    useSourceHealth(t)
+    down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        http.Error(w, "down", http.StatusBadGateway)
+    }))
+    defer down.Close()
+    up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        w.Write([]byte("ok"))
+    }))
+    defer up.Close()
+    useConfig(t, func(c *Config) {
+        c.APIBaseURL = down.URL
+        c.FallbackAPIURLs = []string{up.URL}
+    })
+
+    var body string
+    if err := fetchWithFailover("/test-path", func(b []byte) error {
+        body = string(b)
+        return nil
+    }); err != nil {
+        t.Fatal(err)
+    }
+    if body != "ok" {
+        t.Errorf("body = %q, want the fallback's answer", body)
+    }
+    if got := answeredBy("/test-path"); got != up.URL {
+        t.Errorf("answeredBy = %q, want the fallback %q", got, up.URL)
+    }
+    if got := answeredBy("/other"); got != "" {
+        t.Errorf("answeredBy of a path never fetched = %q", got)
+    }
+}
+
+// Bad lines are skipped and named in the error instead of dropping every fallback
+func TestParseFallbackAPIURLs(t *testing.T) {
+    tests := []struct {
+        text    string
+        want    []string
+        wantErr bool
+    }{
+        {"", nil, false},
+        {"https://a.example\n\n  https://b.example  \n", []string{"https://a.example", "https://b.example"}, false},
+        {"https://a.example\nhttps://a.example", []string{"https://a.example"}, false},
+        {"https://a.example\nhttp://plain.example\nhttps://b.example", []string{"https://a.example", "https://b.example"}, true},
+        {"not a url", nil, true},
+        {"https://1.example\nhttps://2.example\nhttps://3.example\nhttps://4.example\nhttps://5.example\nhttps://6.example",
+            []string{"https://1.example", "https://2.example", "https://3.example", "https://4.example", "https://5.example"}, true},
+    }
+    for _, tt := range tests {
+        got, err := parseFallbackAPIURLs(tt.text)
+        if !reflect.DeepEqual(got, tt.want) || (err != nil) != tt.wantErr {
+            t.Errorf("parseFallbackAPIURLs(%q) = %q, %v, want %q (error %v)", tt.text, got, err, tt.want, tt.wantErr)
+        }
+    }
+}
@@ -0,0 +1,191 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "reflect"
+    "strconv"
+    "strings"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/theme"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Editing a stored miner in place, keyed by its ID. The versions before and after the last
+// edit are kept so the edit can be undone until the app is closed, as long as the miner
+// wasn't changed or removed since.
+
+type minerEdit struct {
+    Portfolio string
+    Before    Miner
+    After     Miner
+}
+
+var lastMinerEdit *minerEdit
+
+// Replaces the miner with updated's ID, false if there is none
+func updateMiner(miners []Miner, updated Miner) bool {
+    for i, m := range miners {
+        if m.ID == updated.ID {
+            miners[i] = updated
+            return true
+        }
+    }
+    return false
+}
+
+// Saves updated in place of the stored miner and returns the version it replaced
+func saveEditedMiner(updated Miner) (Miner, error) {
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    current, err := loadMiners()
+    if err != nil {
+        return Miner{}, err
+    }
+    var before Miner
+    for _, m := range current {
+        if m.ID == updated.ID {
+            before = m
+        }
+    }
+    if !updateMiner(current, updated) {
+        return Miner{}, fmt.Errorf("miner no longer exists")
+    }
+    journalAppend(journalEntry{Op: "update", Miner: &updated})
+    return before, saveMiners(current)
+}
+
+// The last edit when it was made in the active portfolio, nil otherwise
+func undoableMinerEdit() *minerEdit {
+    if lastMinerEdit == nil || lastMinerEdit.Portfolio != configManager.GetConfig().ActivePortfolio {
+        return nil
+    }
+    return lastMinerEdit
+}
+
+func undoMinerEdit() error {
+    edit := undoableMinerEdit()
+    if edit == nil {
+        return fmt.Errorf("nothing to undo")
+    }
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    current, err := loadMiners()
+    if err != nil {
+        return err
+    }
+    var stored *Miner
+    for i := range current {
+        if current[i].ID == edit.Before.ID {
+            stored = &current[i]
+        }
+    }
+    // Undoing now would drop whatever changed it since, so the edit can't be undone any more
+    if stored == nil || !reflect.DeepEqual(*stored, edit.After) {
+        lastMinerEdit = nil
+        return fmt.Errorf("the miner was changed or removed since the edit")
+    }
+    *stored = edit.Before
+    journalAppend(journalEntry{Op: "update", Miner: &edit.Before})
+    if err := saveMiners(current); err != nil {
+        return err
+    }
+    lastMinerEdit = nil
+    return nil
+}
+
+// Button undoing the last edit, hidden while there is none
+func newUndoEditButton(w fyne.Window, refreshTabs func()) *widget.Button {
+    edit := undoableMinerEdit()
+    if edit == nil {
+        button := widget.NewButton("", nil)
+        button.Hide()
+        return button
+    }
+    text := fmt.Sprintf("Undo Edit of Miner %s - %s", edit.Before.StartDate, edit.Before.EndDate)
+    return widget.NewButtonWithIcon(text, theme.ContentUndoIcon(), func() {
+        if err := undoMinerEdit(); err != nil {
+            log.Println("Error undoing miner edit:", err)
+            showError(fmt.Errorf("Failed to undo edit: %v", err), w)
+        }
+        refreshTabs() // Also hides the button when the edit can't be undone any more
+    })
+}
+
+// Date entry with a button opening the same calendar as the add form
+func datePickerField(title string, field *widget.Entry, w fyne.Window) fyne.CanvasObject {
+    button := widget.NewButtonWithIcon("", theme.CalendarIcon(), func() {
+        showCalendarDialog(title, field, w)
+    })
+    return container.NewBorder(nil, nil, nil, button, field)
+}
+
+func showEditMinerDialog(miner Miner, w fyne.Window, refreshTabs func()) {
+    startEntry := widget.NewEntry()
+    startEntry.SetText(miner.StartDate)
+    endEntry := widget.NewEntry()
+    endEntry.SetText(miner.EndDate)
+    tSharesEntry := widget.NewEntry()
+    tSharesEntry.SetText(strconv.FormatFloat(miner.TShares, 'f', -1, 64))
+    principalEntry := widget.NewEntry()
+    if miner.PrincipalHEX > 0 {
+        principalEntry.SetText(strconv.FormatFloat(miner.PrincipalHEX, 'f', -1, 64))
+    }
+    tagsEntry := widget.NewEntry()
+    tagsEntry.SetText(strings.Join(miner.Tags, ", "))
+    chainSelect := widget.NewSelect(chainOptions(), nil)
+    chainSelect.SetSelected(chainName(minerChain(miner)))
+
+    items := []*widget.FormItem{
+        widget.NewFormItem("Start Date", datePickerField("Select Start Date", startEntry, w)),
+        widget.NewFormItem("End Date", datePickerField("Select End Date", endEntry, w)),
+        widget.NewFormItem("T-Shares", tSharesEntry),
+        widget.NewFormItem("Principal HEX", principalEntry),
+        widget.NewFormItem("Tags", tagsEntry),
+        widget.NewFormItem("Chain", chainSelect),
+    }
+    form := dialog.NewForm("Edit Miner", "Save", "Cancel", items, func(save bool) {
+        if !save {
+            return
+        }
+        updated := miner
+        updated.StartDate = strings.TrimSpace(startEntry.Text)
+        updated.EndDate = strings.TrimSpace(endEntry.Text)
+        if updated.EndDate != miner.EndDate { // Announce the maturity of the new end date
+            updated.Notified, updated.GraceNotified = false, false
+        }
+        updated.Tags = parseTags(tagsEntry.Text)
+        updated.Chain = storedChain(chainByName(chainSelect.Selected))
+        tShares, err := strconv.ParseFloat(sanitizeNumber(tSharesEntry.Text), 64)
+        if err != nil {
+            showError(fmt.Errorf("Invalid T-Shares: %v", err), w)
+            return
+        }
+        updated.TShares = tShares
+        updated.PrincipalHEX = 0
+        if text := sanitizeNumber(principalEntry.Text); text != "" {
+            updated.PrincipalHEX, err = strconv.ParseFloat(text, 64)
+            if err != nil {
+                showError(fmt.Errorf("Invalid principal HEX: %v", err), w)
+                return
+            }
+        }
+        if err := validateMiner(updated); err != nil {
+            showError(fmt.Errorf("Invalid miner: %v (dates are DD-MM-YYYY)", err), w)
+            return
+        }
+        before, err := saveEditedMiner(updated)
+        if err != nil {
+            log.Println("Error saving miners:", err)
+            showError(fmt.Errorf("Failed to save miner: %v", err), w)
+            return
+        }
+        lastMinerEdit = &minerEdit{Portfolio: configManager.GetConfig().ActivePortfolio, Before: before, After: updated}
+        refreshTabs()
+    }, w)
+    form.Resize(fyne.NewSize(450, form.MinSize().Height))
+    form.Show()
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+)
+
+// Undo restores the miner as it was before the edit while nothing changed it since, and is
+// dropped once something did
+func TestUndoMinerEdit(t *testing.T) {
+    useConfig(t, nil)
+    useTempStorage(t)
+    t.Cleanup(func() { lastMinerEdit = nil })
+    a := Miner{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 1}
+    b := Miner{ID: "b", StartDate: "01-02-2025", EndDate: "01-02-2026", TShares: 2}
+    edit := func(updated Miner) {
+        t.Helper()
+        before, err := saveEditedMiner(updated)
+        if err != nil {
+            t.Fatal(err)
+        }
+        lastMinerEdit = &minerEdit{Portfolio: configManager.GetConfig().ActivePortfolio, Before: before, After: updated}
+    }
+    if err := saveMiners([]Miner{a, b}); err != nil {
+        t.Fatal(err)
+    }
+
+    edited := a
+    edited.TShares = 10
+    edited.Tags = parseTags("")
+    edit(edited)
+    otherEdit := b
+    otherEdit.TShares = 20
+    if _, err := saveEditedMiner(otherEdit); err != nil { // Other miners don't block the undo
+        t.Fatal(err)
+    }
+    if err := undoMinerEdit(); err != nil {
+        t.Fatal(err)
+    }
+    if miners, _ := loadMiners(); !reflect.DeepEqual(miners, []Miner{a, otherEdit}) {
+        t.Errorf("miners after undo = %+v, want a restored and b's edit kept", miners)
+    }
+    if undoableMinerEdit() != nil {
+        t.Error("edit still undoable after undoing it")
+    }
+
+    // Changed again since, e.g. flagged by the maturity watcher
+    edit(edited)
+    flagged := edited
+    flagged.Notified = true
+    if _, err := saveEditedMiner(flagged); err != nil {
+        t.Fatal(err)
+    }
+    if err := undoMinerEdit(); err == nil {
+        t.Error("undid an edit of a miner changed since")
+    }
+    if miners, _ := loadMiners(); !reflect.DeepEqual(miners, []Miner{flagged, otherEdit}) {
+        t.Errorf("miners after a refused undo = %+v, want them unchanged", miners)
+    }
+    if undoableMinerEdit() != nil {
+        t.Error("refused edit still offered for undo")
+    }
+
+    // Removed since
+    edit(a)
+    if err := deleteStoredMiner(a.ID); err != nil {
+        t.Fatal(err)
+    }
+    if err := undoMinerEdit(); err == nil {
+        t.Error("undid an edit of a removed miner")
+    }
+    if undoableMinerEdit() != nil {
+        t.Error("edit of a removed miner still offered for undo")
+    }
+    if miners, _ := loadMiners(); !reflect.DeepEqual(miners, []Miner{otherEdit}) {
+        t.Errorf("miners after a refused undo = %+v, want the removed miner to stay removed", miners)
+    }
+}
@@ -0,0 +1,40 @@
+package main
+
+import (
+    "fmt"
+    "runtime"
+    "time"
+    "unicode"
+    "unicode/utf8"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/theme"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Error dialogs with a button copying the details for a bug report
+
+// Text copied by "Copy Details"
+func errorDetails(err error, version string, now time.Time) string {
+    if version == "" {
+        version = "(unversioned build)"
+    }
+    return fmt.Sprintf("hexfetch-ui %s (%s/%s)\nTime: %s\nError: %v\n", version, runtime.GOOS, runtime.GOARCH, now.Format("02-01-2006 15:04:05"), err)
+}
+
+// Replaces dialog.ShowError, the message is capitalized the same way
+func showError(err error, w fyne.Window) {
+    text := err.Error()
+    if r, size := utf8.DecodeRuneInString(text); r != utf8.RuneError {
+        text = string(unicode.ToUpper(r)) + text[size:]
+    }
+    d := dialog.NewCustomWithoutButtons("Error", widget.NewLabel(text), w)
+    copyButton := widget.NewButtonWithIcon("Copy Details", theme.ContentCopyIcon(), func() {
+        fyne.CurrentApp().Clipboard().SetContent(errorDetails(err, appVersion, time.Now()))
+    })
+    closeButton := widget.NewButton("OK", d.Hide)
+    closeButton.Importance = widget.HighImportance
+    d.SetButtons([]fyne.CanvasObject{copyButton, closeButton})
+    d.Show()
+}
@@ -0,0 +1,24 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "runtime"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestErrorDetails(t *testing.T) {
+    now := time.Date(2025, 6, 1, 14, 30, 5, 0, time.UTC)
+    err := fmt.Errorf("Failed to save miner: %w", errors.New("disk full"))
+    want := "hexfetch-ui 1.2.3 (" + runtime.GOOS + "/" + runtime.GOARCH + ")\n" +
+        "Time: 01-06-2025 14:30:05\n" +
+        "Error: Failed to save miner: disk full\n"
+    if got := errorDetails(err, "1.2.3", now); got != want {
+        t.Errorf("errorDetails:\n%s\nwant:\n%s", got, want)
+    }
+    if got := errorDetails(err, "", now); !strings.HasPrefix(got, "hexfetch-ui (unversioned build) (") {
+        t.Errorf("errorDetails without a version:\n%s", got)
+    }
+}
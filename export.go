@@ -0,0 +1,294 @@
+package main
+
+import (
+    "archive/zip"
+    "encoding/csv"
+    "encoding/xml"
+    "fmt"
+    "io"
+    "log"
+    "sort"
+    "strconv"
+    "strings"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/storage"
+)
+
+// Export menu: the miners and the local history as CSV or XLSX, picked by the file extension.
+// Numbers are written unformatted so spreadsheets can compute with them.
+
+type exportTable struct {
+    Name   string // Sheet name in XLSX files
+    Header []string
+    Rows   [][]interface{} // Cells are string, int or float64
+}
+
+// Miners with the figures the Profile shows, then the archived ones. Values are in the display
+// currency at the price the Profile values them at, left blank until that price is known, as
+// are projected yields until the chain's payout is (completed miners keep their recorded yield).
+func minersExportTable(miners, archived []Miner, data LiveData, now time.Time) exportTable {
+    config := configManager.GetConfig()
+    code, rate, _ := fxCache.lookup(config.Currency)
+    table := exportTable{
+        Name:   "Miners",
+        Header: []string{"Start Date", "End Date", "Chain", "Status", "T-Shares", "Principal HEX", "Days Left", "Yield HEX", "Current Value (" + code + ")", "Tags"},
+    }
+    views := buildMinerViewModels(append(copyMiners(miners), archived...), now, data)
+    for i, view := range views {
+        miner := view.Miner
+        status := "active"
+        var value interface{} = ""
+        switch {
+        case view.Err != nil:
+            status = "invalid"
+        case i >= len(miners):
+            status = "archived"
+        case view.State == stakeCompleted:
+            status = "completed"
+        case view.State.Matured():
+            status = "matured"
+        }
+        if status == "active" || status == "matured" {
+            if price := chainTsharePrice(minerChain(miner), data, config.PinnedTsharePrice); price > 0 {
+                value = miner.TShares * price * rate
+            }
+        } else if status != "invalid" {
+            value = 0.0
+        }
+        var yield interface{} = ""
+        if view.HasYield && !view.AwaitingData && (miner.Status == "completed" || data.Chain(minerChain(miner)).PayoutPerTshare > 0) {
+            yield = view.YieldHEX
+        }
+        table.Rows = append(table.Rows, []interface{}{
+            miner.StartDate, miner.EndDate, chainName(minerChain(miner)), status, miner.TShares,
+            miner.PrincipalHEX, view.DaysLeft, yield, value, strings.Join(miner.Tags, ", "),
+        })
+    }
+    return table
+}
+
+// History of a chain, oldest day first. Prices stay in USD as there are no past FX rates.
+func historyExportTable(chain string, history HEXJSON) exportTable {
+    entries := append(HEXJSON(nil), history...)
+    sort.Slice(entries, func(i, j int) bool {
+        return entries[i].CurrentDay < entries[j].CurrentDay
+    })
+    table := exportTable{
+        Name:   chainName(chain) + " History",
+        Header: []string{"Day", "T-Share Rate HEX", "Daily Payout HEX", "Price (USD)"},
+    }
+    for _, entry := range entries {
+        table.Rows = append(table.Rows, []interface{}{entry.CurrentDay, entry.TshareRateHEX, entry.DailyPayoutHEX, entry.Price()})
+    }
+    return table
+}
+
+func exportCellText(cell interface{}) string {
+    switch v := cell.(type) {
+    case float64:
+        return strconv.FormatFloat(v, 'f', -1, 64)
+    case int:
+        return strconv.Itoa(v)
+    }
+    return fmt.Sprint(cell)
+}
+
+func writeCSV(w io.Writer, table exportTable) error {
+    writer := csv.NewWriter(w)
+    if err := writer.Write(table.Header); err != nil {
+        return err
+    }
+    for _, row := range table.Rows {
+        record := make([]string, len(row))
+        for i, cell := range row {
+            record[i] = exportCellText(cell)
+        }
+        if err := writer.Write(record); err != nil {
+            return err
+        }
+    }
+    writer.Flush()
+    return writer.Error()
+}
+
+// Column letters of a zero-based index, A to Z then AA
+func xlsxColumn(i int) string {
+    name := ""
+    for i++; i > 0; i = (i - 1) / 26 {
+        name = string(rune('A'+(i-1)%26)) + name
+    }
+    return name
+}
+
+func xmlEscaped(s string) string {
+    var b strings.Builder
+    xml.EscapeText(&b, []byte(s))
+    return b.String()
+}
+
+func xlsxSheet(table exportTable) string {
+    var b strings.Builder
+    b.WriteString(`<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n")
+    b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+    header := make([]interface{}, len(table.Header))
+    for i, name := range table.Header {
+        header[i] = name
+    }
+    rows := append([][]interface{}{header}, table.Rows...)
+    for r, row := range rows {
+        fmt.Fprintf(&b, `<row r="%d">`, r+1)
+        for c, cell := range row {
+            ref := fmt.Sprintf("%s%d", xlsxColumn(c), r+1)
+            switch cell.(type) {
+            case float64, int:
+                fmt.Fprintf(&b, `<c r="%s"><v>%s</v></c>`, ref, exportCellText(cell))
+            default:
+                fmt.Fprintf(&b, `<c r="%s" t="inlineStr"><is><t>%s</t></is></c>`, ref, xmlEscaped(exportCellText(cell)))
+            }
+        }
+        b.WriteString(`</row>`)
+    }
+    b.WriteString(`</sheetData></worksheet>`)
+    return b.String()
+}
+
+// File inside the XLSX zip
+type xlsxPart struct {
+    Name    string
+    Content string
+}
+
+// Minimal workbook with one sheet per table, no styles
+func writeXLSX(w io.Writer, tables []exportTable) error {
+    var contentTypes, workbookSheets, workbookRels strings.Builder
+    for i, table := range tables {
+        n := i + 1
+        fmt.Fprintf(&contentTypes, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, n)
+        fmt.Fprintf(&workbookSheets, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscaped(table.Name), n, n)
+        fmt.Fprintf(&workbookRels, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, n, n)
+    }
+    const header = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+    files := []xlsxPart{
+        {"[Content_Types].xml", header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+            `<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+            `<Default Extension="xml" ContentType="application/xml"/>` +
+            `<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+            contentTypes.String() + `</Types>`},
+        {"_rels/.rels", header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+            `<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+            `</Relationships>`},
+        {"xl/workbook.xml", header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+            `<sheets>` + workbookSheets.String() + `</sheets></workbook>`},
+        {"xl/_rels/workbook.xml.rels", header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+            workbookRels.String() + `</Relationships>`},
+    }
+    for i, table := range tables {
+        files = append(files, xlsxPart{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), xlsxSheet(table)})
+    }
+    archive := zip.NewWriter(w)
+    for _, file := range files {
+        fw, err := archive.Create(file.Name)
+        if err != nil {
+            return err
+        }
+        if _, err := io.WriteString(fw, file.Content); err != nil {
+            return err
+        }
+    }
+    return archive.Close()
+}
+
+// Writes the tables as XLSX for a .xlsx file, else the first one as CSV
+func writeExport(w io.Writer, extension string, tables []exportTable) error {
+    if strings.EqualFold(extension, ".xlsx") {
+        return writeXLSX(w, tables)
+    }
+    return writeCSV(w, tables[0])
+}
+
+func currentMinersTable() (exportTable, error) {
+    miners, err := loadMiners()
+    if err != nil {
+        return exportTable{}, err
+    }
+    archived, err := loadArchivedMiners()
+    if err != nil {
+        return exportTable{}, err
+    }
+    liveDataMutex.Lock()
+    data := latestLiveData
+    liveDataMutex.Unlock()
+    return minersExportTable(miners, archived, data, time.Now()), nil
+}
+
+func historyTable(chain string) (exportTable, error) {
+    history, err := loadLocalHEXJSON(chain)
+    if err != nil {
+        return exportTable{}, err
+    }
+    return historyExportTable(chain, history), nil
+}
+
+// Save dialog writing what build returns, the format follows the extension picked
+func showExportDialog(w fyne.Window, fileName string, extensions []string, build func() ([]exportTable, error)) {
+    saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+        if err != nil {
+            showError(err, w)
+            return
+        }
+        if writer == nil {
+            return
+        }
+        defer writer.Close()
+        tables, err := build()
+        if err == nil {
+            err = writeExport(writer, writer.URI().Extension(), tables)
+        }
+        if err != nil {
+            log.Println("Error exporting:", err)
+            showError(fmt.Errorf("Failed to export: %v", err), w)
+            return
+        }
+        dialog.ShowInformation("Exported", fmt.Sprintf("Data written to %s", writer.URI().Name()), w)
+    }, w)
+    saveDialog.SetFileName(fileName)
+    saveDialog.SetFilter(storage.NewExtensionFileFilter(extensions))
+    saveDialog.Show()
+}
+
+func newExportMenu(w fyne.Window) *fyne.Menu {
+    minersItem := fyne.NewMenuItem("Miners...", func() {
+        showExportDialog(w, "hexfetch-miners.csv", []string{".csv", ".xlsx"}, func() ([]exportTable, error) {
+            table, err := currentMinersTable()
+            return []exportTable{table}, err
+        })
+    })
+    historyItem := fyne.NewMenuItem("History...", func() {
+        chain := configManager.GetConfig().Chain // The chain shown on the Live Data tab
+        showExportDialog(w, "hexfetch-history-"+chain+".csv", []string{".csv", ".xlsx"}, func() ([]exportTable, error) {
+            table, err := historyTable(chain)
+            return []exportTable{table}, err
+        })
+    })
+    allItem := fyne.NewMenuItem("Everything to Excel...", func() {
+        showExportDialog(w, "hexfetch.xlsx", []string{".xlsx"}, func() ([]exportTable, error) {
+            table, err := currentMinersTable()
+            if err != nil {
+                return nil, err
+            }
+            tables := []exportTable{table}
+            for _, chain := range chains {
+                table, err := historyTable(chain)
+                if err != nil {
+                    return nil, err
+                }
+                tables = append(tables, table)
+            }
+            return tables, nil
+        })
+    })
+    return fyne.NewMenu("Export", minersItem, historyItem, allItem)
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestXLSXColumn(t *testing.T) {
+    tests := []struct {
+        index int
+        want  string
+    }{
+        {0, "A"}, {1, "B"}, {25, "Z"}, {26, "AA"}, {27, "AB"}, {51, "AZ"}, {52, "BA"}, {701, "ZZ"}, {702, "AAA"},
+    }
+    for _, tt := range tests {
+        if got := xlsxColumn(tt.index); got != tt.want {
+            t.Errorf("xlsxColumn(%d) = %q, want %q", tt.index, got, tt.want)
+        }
+    }
+}
+
+func TestMinersExportTable(t *testing.T) {
+    useConfig(t, nil)
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {StartDate: "01-05-2025", EndDate: "11-06-2025", TShares: 2, PrincipalHEX: 1000, Tags: []string{"a", "b"}},
+        {StartDate: "01-05-2025", EndDate: "11-06-2025", TShares: 2, Chain: chainEthereum},
+        {StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 4, Status: "completed", RealizedHEX: 500},
+    }
+    archived := []Miner{{StartDate: "01-01-2023", EndDate: "01-01-2024", TShares: 1, Status: "completed", RealizedHEX: 100}}
+    data := LiveData{PayoutPerTsharePulsechain: 3, TsharePricePulsechain: 10, PayoutPerTshareEthereum: 5, TsharePriceEthereum: 4}
+
+    table := minersExportTable(miners, archived, data, now)
+    want := [][]interface{}{
+        {"01-05-2025", "11-06-2025", "PulseChain", "active", 2.0, 1000.0, 10, 246.0, 20.0, "a, b"},
+        {"01-05-2025", "11-06-2025", "Ethereum", "active", 2.0, 0.0, 10, 410.0, 8.0, ""},
+        {"01-01-2024", "01-01-2025", "PulseChain", "completed", 4.0, 0.0, 0, 500.0, 0.0, ""},
+        {"01-01-2023", "01-01-2024", "PulseChain", "archived", 1.0, 0.0, 0, 100.0, 0.0, ""},
+    }
+    if !reflect.DeepEqual(table.Rows, want) {
+        t.Errorf("rows = %v,\nwant %v", table.Rows, want)
+    }
+
+    // A pinned price stands in for the PulseChain one
+    useConfig(t, func(c *Config) {
+        c.PinnedTsharePrice = 50
+    })
+    table = minersExportTable(miners, nil, data, now)
+    if got := table.Rows[0][8]; got != 100.0 {
+        t.Errorf("value at the pinned price = %v, want 100", got)
+    }
+    if got := table.Rows[1][8]; got != 8.0 {
+        t.Errorf("Ethereum value = %v, want the live price, not the pinned one", got)
+    }
+
+    // Before the first fetch there is no price to value the miners at
+    useConfig(t, nil)
+    table = minersExportTable(miners, nil, LiveData{}, now)
+    if got := table.Rows[0][8]; got != "" {
+        t.Errorf("value before live data = %v, want it blank", got)
+    }
+    if got := table.Rows[0][7]; got != "" {
+        t.Errorf("yield before live data = %v, want it blank", got)
+    }
+    if got := table.Rows[2][7]; got != 500.0 {
+        t.Errorf("completed yield before live data = %v, want the recorded 500", got)
+    }
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+    "sync"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Recent fetch outcomes for diagnosing flaky connections, only the newest are kept
+
+const defaultFetchHistorySize = 100
+const maxFetchHistorySize = 1000
+const fetchStatsWindow = time.Hour
+
+type fetchOutcome struct {
+    At     time.Time
+    Source string // live data, history or FX rate
+    Err    string // Empty for a successful fetch
+}
+
+type fetchHistory struct {
+    mu       sync.Mutex
+    outcomes []fetchOutcome // Oldest first
+}
+
+var fetchLog = &fetchHistory{}
+
+// Appends an outcome and drops the oldest beyond limit
+func (h *fetchHistory) Record(source string, err error, now time.Time, limit int) {
+    outcome := fetchOutcome{At: now, Source: source}
+    if err != nil {
+        outcome.Err = err.Error()
+    }
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    h.outcomes = append(h.outcomes, outcome)
+    if limit > 0 && len(h.outcomes) > limit {
+        h.outcomes = append([]fetchOutcome(nil), h.outcomes[len(h.outcomes)-limit:]...)
+    }
+}
+
+// Successful and failed fetches within fetchStatsWindow before now
+func (h *fetchHistory) Stats(now time.Time) (int, int) {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    succeeded, failed := 0, 0
+    for _, outcome := range h.outcomes {
+        if now.Sub(outcome.At) > fetchStatsWindow {
+            continue
+        }
+        if outcome.Err == "" {
+            succeeded++
+        } else {
+            failed++
+        }
+    }
+    return succeeded, failed
+}
+
+// Failed fetches, newest first
+func (h *fetchHistory) Errors() []fetchOutcome {
+    h.mu.Lock()
+    defer h.mu.Unlock()
+    var errs []fetchOutcome
+    for i := len(h.outcomes) - 1; i >= 0; i-- {
+        if h.outcomes[i].Err != "" {
+            errs = append(errs, h.outcomes[i])
+        }
+    }
+    return errs
+}
+
+func recordFetch(source string, err error) {
+    fetchLog.Record(source, err, time.Now(), configManager.GetConfig().FetchHistorySize)
+}
+
+// Share of successful fetches as text, "-" without any fetch
+func successRateText(succeeded, failed int) string {
+    if succeeded+failed == 0 {
+        return "-"
+    }
+    return fmt.Sprintf("%.0f%%", float64(succeeded)*100/float64(succeeded+failed))
+}
+
+func showConnectionWindow() {
+    connectionWindow := fyne.CurrentApp().NewWindow("Connection")
+    connectionWindow.Resize(fyne.NewSize(600, 400))
+    statsLabel := widget.NewLabel("")
+    errorsLabel := widget.NewLabel("")
+    errorsLabel.Wrapping = fyne.TextWrapWord
+    refresh := func() {
+        now := time.Now()
+        succeeded, failed := fetchLog.Stats(now)
+        statsLabel.SetText(fmt.Sprintf("Last hour: %d succeeded, %d failed (success rate %s)", succeeded, failed, successRateText(succeeded, failed)))
+        errs := fetchLog.Errors()
+        if len(errs) == 0 {
+            errorsLabel.SetText("No fetch errors recorded")
+            return
+        }
+        var b strings.Builder
+        for _, outcome := range errs {
+            fmt.Fprintf(&b, "%s  %s: %s\n", outcome.At.Format("02-01-2006 15:04:05"), outcome.Source, outcome.Err)
+        }
+        errorsLabel.SetText(b.String())
+    }
+    refresh()
+    connectionWindow.SetContent(container.NewBorder(
+        container.NewVBox(statsLabel, widget.NewButton("Refresh", refresh), widget.NewLabel("Recent errors")),
+        nil, nil, nil, container.NewVScroll(errorsLabel)))
+    connectionWindow.Show()
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+    "fmt"
+    "testing"
+    "time"
+)
+
+func TestFetchHistoryBounded(t *testing.T) {
+    history := &fetchHistory{}
+    start := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+    for i := 0; i < 5; i++ {
+        var err error
+        if i%2 == 1 {
+            err = fmt.Errorf("failure %d", i)
+        }
+        history.Record("live data", err, start.Add(time.Duration(i)*time.Minute), 3)
+    }
+    if len(history.outcomes) != 3 || history.outcomes[0].At != start.Add(2*time.Minute) {
+        t.Fatalf("kept %+v, want the newest 3", history.outcomes)
+    }
+    errs := history.Errors()
+    if len(errs) != 1 || errs[0].Err != "failure 3" || errs[0].Source != "live data" {
+        t.Errorf("Errors() = %+v, want only the failure still kept", errs)
+    }
+    // A limit of 0 keeps everything
+    for i := 0; i < 5; i++ {
+        history.Record("history", nil, start, 0)
+    }
+    if len(history.outcomes) != 8 {
+        t.Errorf("kept %d outcomes without a limit, want 8", len(history.outcomes))
+    }
+}
+
+func TestFetchHistoryStats(t *testing.T) {
+    history := &fetchHistory{}
+    now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+    history.Record("live data", fmt.Errorf("old"), now.Add(-2*time.Hour), 0)
+    history.Record("live data", nil, now.Add(-fetchStatsWindow), 0) // Just inside the window
+    history.Record("live data", nil, now.Add(-time.Minute), 0)
+    history.Record("history", nil, now.Add(-time.Minute), 0)
+    history.Record("live data", fmt.Errorf("timeout"), now, 0)
+    succeeded, failed := history.Stats(now)
+    if succeeded != 3 || failed != 1 {
+        t.Errorf("Stats = %d succeeded, %d failed, want 3 and 1", succeeded, failed)
+    }
+    tests := []struct {
+        succeeded, failed int
+        want              string
+    }{
+        {0, 0, "-"},
+        {3, 1, "75%"},
+        {0, 4, "0%"},
+        {2, 1, "67%"},
+    }
+    for _, tt := range tests {
+        if got := successRateText(tt.succeeded, tt.failed); got != tt.want {
+            t.Errorf("successRateText(%d, %d) = %q, want %q", tt.succeeded, tt.failed, got, tt.want)
+        }
+    }
+}
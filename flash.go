@@ -0,0 +1,79 @@
+package main
+
+import (
+    "image/color"
+    "strconv"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/theme"
+)
+
+// Brief background highlight on live data values that changed
+
+const flashDuration = 800 * time.Millisecond
+
+// Decimals the Live Data tab shows, changes below them don't count
+const (
+    priceDecimals       = 4
+    tsharePriceDecimals = 2
+)
+
+// Whether a and b look the same when shown with decimals
+func roundedEqual(a, b float64, decimals int) bool {
+    return strconv.FormatFloat(a, 'f', decimals, 64) == strconv.FormatFloat(b, 'f', decimals, 64)
+}
+
+// Names of the live data values of a chain whose shown digits differ between old and new
+func changedLiveFields(old, new chainLiveData, oldBeat, newBeat int64) []string {
+    var changed []string
+    if !roundedEqual(old.Price, new.Price, priceDecimals) {
+        changed = append(changed, "price")
+    }
+    if !roundedEqual(old.TsharePrice, new.TsharePrice, tsharePriceDecimals) {
+        changed = append(changed, "tsharePrice")
+    }
+    // HEX figures and the payout follow their settings, so compare them as shown
+    if formatHEX(old.TshareRateHEX) != formatHEX(new.TshareRateHEX) {
+        changed = append(changed, "tshareRate")
+    }
+    if formatPayout(old.PayoutPerTshare) != formatPayout(new.PayoutPerTshare) {
+        changed = append(changed, "payout")
+    }
+    if formatHEX(old.PenaltiesHEX) != formatHEX(new.PenaltiesHEX) {
+        changed = append(changed, "penalties")
+    }
+    if oldBeat != newBeat {
+        changed = append(changed, "beat")
+    }
+    return changed
+}
+
+// Value with a background that can flash, a new flash replaces a running one
+type flashValue struct {
+    background *canvas.Rectangle
+    animation  *fyne.Animation
+    Content    fyne.CanvasObject
+}
+
+func newFlashValue(value fyne.CanvasObject) *flashValue {
+    background := canvas.NewRectangle(color.Transparent)
+    background.CornerRadius = 4
+    return &flashValue{background: background, Content: container.NewStack(background, value)}
+}
+
+func (f *flashValue) Flash() {
+    if f.animation != nil {
+        f.animation.Stop()
+    }
+    r, g, b, _ := theme.Color(theme.ColorNamePrimary).RGBA()
+    start := color.NRGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0x60}
+    end := color.NRGBA{R: start.R, G: start.G, B: start.B, A: 0}
+    f.animation = canvas.NewColorRGBAAnimation(start, end, flashDuration, func(c color.Color) {
+        f.background.FillColor = c
+        f.background.Refresh()
+    })
+    f.animation.Start()
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestChangedLiveFields(t *testing.T) {
+    useConfig(t, nil)
+    old := chainLiveData{Price: 0.0123, TsharePrice: 250, TshareRateHEX: 20000, PenaltiesHEX: 1_000_000, PayoutPerTshare: 1.5}
+    tests := []struct {
+        name    string
+        change  func(*chainLiveData)
+        newBeat int64
+        want    []string
+    }{
+        {"nothing", func(*chainLiveData) {}, 7, nil},
+        {"price", func(d *chainLiveData) { d.Price = 0.0124 }, 7, []string{"price"}},
+        {"T-Share price and rate", func(d *chainLiveData) { d.TsharePrice, d.TshareRateHEX = 251, 20100 }, 7, []string{"tsharePrice", "tshareRate"}},
+        {"penalties and beat", func(d *chainLiveData) { d.PenaltiesHEX = 1_000_001 }, 8, []string{"penalties", "beat"}},
+        {"payout", func(d *chainLiveData) { d.PayoutPerTshare = 1.6 }, 7, []string{"payout"}},
+    }
+    for _, tt := range tests {
+        changed := old
+        tt.change(&changed)
+        if got := changedLiveFields(old, changed, 7, tt.newBeat); !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("%s: changedLiveFields = %q, want %q", tt.name, got, tt.want)
+        }
+    }
+}
+
+func TestRoundedEqual(t *testing.T) {
+    useConfig(t, nil)
+    tests := []struct {
+        a, b     float64
+        decimals int
+        want     bool
+    }{
+        {0.012341, 0.012344, 4, true},
+        {0.01234, 0.01236, 4, false},
+        {250.004, 249.996, 2, true}, // Both show 250.00
+        {250.004, 250.006, 2, false},
+        {1.4, 1.6, 0, false},
+        {1.6, 2.4, 0, true},
+    }
+    for _, tt := range tests {
+        if got := roundedEqual(tt.a, tt.b, tt.decimals); got != tt.want {
+            t.Errorf("roundedEqual(%v, %v, %d) = %v, want %v", tt.a, tt.b, tt.decimals, got, tt.want)
+        }
+    }
+    // Fluctuations below the shown precision don't count as a change
+    old := chainLiveData{Price: 0.012341, TsharePrice: 250.001}
+    if changed := changedLiveFields(old, chainLiveData{Price: 0.012344, TsharePrice: 250.004}, 1, 1); len(changed) != 0 {
+        t.Errorf("changedLiveFields flagged %q for changes below the shown digits", changed)
+    }
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+// Gain of a stake: value at the end (principal + yield) minus the principal. The yield is
+// estimated from the current payout per T-Share over the full stake length.
+
+func stakeDays(startDate, endDate string) (int, error) {
+    start, err := time.Parse(dateLayout, startDate)
+    if err != nil {
+        return 0, err
+    }
+    end, err := time.Parse(dateLayout, endDate)
+    if err != nil {
+        return 0, err
+    }
+    if end.Before(start) {
+        return 0, fmt.Errorf("end date before start date")
+    }
+    return int(end.Sub(start).Hours() / 24), nil
+}
+
+// Yield of a stake in HEX. Completed miners use the yield recorded when they were ended,
+// others are estimated from payoutPerTShare. ok is false for completed miners ended before
+// yields were recorded and for invalid dates.
+func stakeYield(miner Miner, payoutPerTShare float64) (float64, bool) {
+    if miner.Status == "completed" {
+        return miner.RealizedHEX, miner.RealizedHEX > 0
+    }
+    days, err := stakeDays(miner.StartDate, miner.EndDate)
+    if err != nil {
+        return 0, false
+    }
+    return miner.TShares * payoutPerTShare * float64(days), true
+}
+
+// Estimated gain in HEX and as a fraction of the principal, ok is false for
+// miners without a principal (added before it was tracked) or with invalid dates
+func minerGain(miner Miner, payoutPerTShare float64) (float64, float64, bool) {
+    if miner.PrincipalHEX <= 0 {
+        return 0, 0, false
+    }
+    if miner.Status == "completed" && miner.RealizedHEX > 0 {
+        return miner.RealizedHEX, miner.RealizedHEX / miner.PrincipalHEX, true
+    }
+    days, err := stakeDays(miner.StartDate, miner.EndDate)
+    if err != nil {
+        return 0, 0, false
+    }
+    gain := miner.TShares * payoutPerTShare * float64(days)
+    return gain, gain / miner.PrincipalHEX, true
+}
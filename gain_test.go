@@ -0,0 +1,119 @@
+package main
+
+import (
+    "strings"
+    "testing"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/test"
+    "fyne.io/fyne/v2/widget"
+)
+
+func TestMinerGain(t *testing.T) {
+    tests := []struct {
+        name     string
+        miner    Miner
+        gain     float64
+        fraction float64
+        ok       bool
+    }{
+        {"active", Miner{StartDate: "01-01-2025", EndDate: "11-01-2025", TShares: 2, PrincipalHEX: 1000}, 40, 0.04, true},
+        {"completed with recorded yield", Miner{StartDate: "01-01-2025", EndDate: "11-01-2025", TShares: 2, PrincipalHEX: 1000, Status: "completed", RealizedHEX: 50}, 50, 0.05, true},
+        {"completed before yields were recorded", Miner{StartDate: "01-01-2025", EndDate: "11-01-2025", TShares: 2, PrincipalHEX: 1000, Status: "completed"}, 40, 0.04, true},
+        {"legacy miner without principal", Miner{StartDate: "01-01-2025", EndDate: "11-01-2025", TShares: 2}, 0, 0, false},
+        {"invalid dates", Miner{StartDate: "11-01-2025", EndDate: "01-01-2025", TShares: 2, PrincipalHEX: 1000}, 0, 0, false},
+    }
+    for _, tt := range tests {
+        gain, fraction, ok := minerGain(tt.miner, 2)
+        if ok != tt.ok || !near(float32(gain), float32(tt.gain)) || !near(float32(fraction), float32(tt.fraction)) {
+            t.Errorf("%s: minerGain = %v, %v, %v, want %v, %v, %v", tt.name, gain, fraction, ok, tt.gain, tt.fraction, tt.ok)
+        }
+    }
+}
+
+func TestPortfolioGainSkipsLegacyMiners(t *testing.T) {
+    miners := []Miner{
+        {StartDate: "01-01-2025", EndDate: "11-01-2025", TShares: 2, PrincipalHEX: 1000},
+        {StartDate: "01-01-2025", EndDate: "11-01-2025", TShares: 5}, // No principal
+    }
+    summary := computePortfolioSummary(miners, LiveData{PayoutPerTsharePulsechain: 2}, testDay(t, "05-01-2025"), false)
+    if !summary.HasGain || !near(float32(summary.Gain), 40) {
+        t.Errorf("gain = %v, %v, want 40 from the miner with a principal", summary.Gain, summary.HasGain)
+    }
+    if summary := computePortfolioSummary(miners[1:], LiveData{PayoutPerTsharePulsechain: 2}, testDay(t, "05-01-2025"), false); summary.HasGain {
+        t.Error("portfolio of legacy miners has a gain")
+    }
+}
+
+// First label whose text starts with prefix, nil when there is none
+func findLabel(object fyne.CanvasObject, prefix string) *widget.Label {
+    switch o := object.(type) {
+    case *fyne.Container:
+        for _, child := range o.Objects {
+            if label := findLabel(child, prefix); label != nil {
+                return label
+            }
+        }
+    case *widget.Label:
+        if strings.HasPrefix(o.Text, prefix) {
+            return o
+        }
+    }
+    return nil
+}
+
+// The gain uses the fetched payout, so it has to follow fetches like the value does
+func TestProfileGainFollowsLiveData(t *testing.T) {
+    useTestApp(t)
+    useConfig(t, nil)
+    useTempStorage(t)
+    useLiveData(t, LiveData{})
+    miners := []Miner{{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2040", TShares: 2, PrincipalHEX: 1000}}
+    w := test.NewWindow(nil)
+    defer w.Close()
+
+    tab := createProfileTab(miners, w, func() {}, false)
+    gain := findLabel(tab, "Estimated Gain")
+    if gain == nil {
+        t.Fatal("profile has no gain label")
+    }
+    if !strings.Contains(gain.Text, calculatingText) {
+        t.Errorf("gain before the first fetch = %q, want it to wait for the payout", gain.Text)
+    }
+
+    useLiveData(t, LiveData{TsharePricePulsechain: 250, PayoutPerTsharePulsechain: 1})
+    test.Tap(findButton(tab, "Recompute"))
+    days, _ := stakeDays("01-01-2025", "01-01-2040")
+    if want := "+" + formatWithCommas(2*days) + " HEX"; !strings.HasSuffix(gain.Text, want) {
+        t.Errorf("gain after a fetch = %q, want it to end in %q", gain.Text, want)
+    }
+}
+
+// The total follows a pinned price and says so, unpinning goes back to the live price
+func TestProfilePinnedValue(t *testing.T) {
+    useTestApp(t)
+    useTempStorage(t)
+    useLiveData(t, LiveData{TsharePricePulsechain: 250})
+    miners := []Miner{{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2040", TShares: 2}}
+    w := test.NewWindow(nil)
+    defer w.Close()
+
+    useConfig(t, func(c *Config) {
+        c.PinnedTsharePrice = 100
+    })
+    totalValue := func() string {
+        t.Helper()
+        label := findLabel(createProfileTab(miners, w, func() {}, false), "Total T-Shares Value")
+        if label == nil {
+            t.Fatal("profile has no total value")
+        }
+        return label.Text
+    }
+    if got := totalValue(); !strings.HasPrefix(got, "Total T-Shares Value: $200.00 (valued at pinned $100.00)") {
+        t.Errorf("pinned total = %q, want it valued at the pinned price", got)
+    }
+    useConfig(t, nil)
+    if got := totalValue(); !strings.HasPrefix(got, "Total T-Shares Value: $500.00") || strings.Contains(got, "pinned") {
+        t.Errorf("live total = %q, want it valued at the live price", got)
+    }
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+    "errors"
+    "io"
+    "net/http"
+    "sync"
+    "time"
+)
+
+// All outbound requests go through httpGet so they share one concurrency limit
+const defaultMaxConcurrentRequests = 4
+
+type requestLimiter struct {
+    mu     sync.Mutex
+    cond   *sync.Cond
+    active int
+    limit  func() int // Read on every acquire so a changed setting applies immediately
+}
+
+func newRequestLimiter(limit func() int) *requestLimiter {
+    l := &requestLimiter{limit: limit}
+    l.cond = sync.NewCond(&l.mu)
+    return l
+}
+
+func (l *requestLimiter) Acquire() {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    for l.active >= max(l.limit(), 1) {
+        l.cond.Wait()
+    }
+    l.active++
+}
+
+func (l *requestLimiter) Release() {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+    l.active--
+    l.cond.Signal()
+}
+
+var httpLimiter = newRequestLimiter(func() int {
+    return configManager.GetConfig().MaxConcurrentRequests
+})
+
+// Body that gives its limiter slot back when closed
+type limitedBody struct {
+    io.ReadCloser
+    once    sync.Once
+    release func()
+}
+
+func (b *limitedBody) Close() error {
+    err := b.ReadCloser.Close()
+    b.once.Do(b.release)
+    return err
+}
+
+// Shared by httpGet and httpPost, without a timeout a stalled server would hold a slot forever
+var httpClient = &http.Client{Timeout: 30 * time.Second}
+
+// Returned instead of making a request while offline mode is on
+var errOffline = errors.New("offline mode is on")
+
+// Like http.Get, the slot is held until the response body is closed
+func httpGet(url string) (*http.Response, error) {
+    if configManager.GetConfig().OfflineMode {
+        return nil, errOffline
+    }
+    httpLimiter.Acquire()
+    resp, err := httpClient.Get(url)
+    if err != nil {
+        httpLimiter.Release()
+        return nil, err
+    }
+    resp.Body = &limitedBody{ReadCloser: resp.Body, release: httpLimiter.Release}
+    return resp, nil
+}
+
+// Like http.Post, limited the same way as httpGet
+func httpPost(url, contentType string, body io.Reader) (*http.Response, error) {
+    if configManager.GetConfig().OfflineMode {
+        return nil, errOffline
+    }
+    httpLimiter.Acquire()
+    resp, err := httpClient.Post(url, contentType, body)
+    if err != nil {
+        httpLimiter.Release()
+        return nil, err
+    }
+    resp.Body = &limitedBody{ReadCloser: resp.Body, release: httpLimiter.Release}
+    return resp, nil
+}
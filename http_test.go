@@ -0,0 +1,120 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "sync"
+    "sync/atomic"
+    "testing"
+    "time"
+)
+
+func TestRequestLimiterBurst(t *testing.T) {
+    limiter := newRequestLimiter(func() int { return 3 })
+    var active, peak atomic.Int32
+    var wg sync.WaitGroup
+    for i := 0; i < 20; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            limiter.Acquire()
+            defer limiter.Release()
+            n := active.Add(1)
+            for {
+                p := peak.Load()
+                if n <= p || peak.CompareAndSwap(p, n) {
+                    break
+                }
+            }
+            time.Sleep(5 * time.Millisecond)
+            active.Add(-1)
+        }()
+    }
+    wg.Wait()
+    if got := peak.Load(); got != 3 {
+        t.Errorf("peak of %d concurrent holders, want the limit of 3", got)
+    }
+    // A limit below one would never let anything through
+    zero := newRequestLimiter(func() int { return 0 })
+    zero.Acquire()
+    zero.Release()
+}
+
+// The slot is held until the body is closed, so open responses count against the limit
+func TestHTTPGetLimit(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.MaxConcurrentRequests = 2
+    })
+    var served atomic.Int32
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        served.Add(1)
+        w.Write([]byte("ok"))
+    }))
+    defer server.Close()
+
+    var open []*http.Response
+    for i := 0; i < 2; i++ {
+        resp, err := httpGet(server.URL)
+        if err != nil {
+            t.Fatal(err)
+        }
+        open = append(open, resp)
+    }
+    third := make(chan error, 1)
+    go func() {
+        resp, err := httpGet(server.URL)
+        if err == nil {
+            resp.Body.Close()
+        }
+        third <- err
+    }()
+    select {
+    case <-third:
+        t.Fatal("a third request ran while two responses were open")
+    case <-time.After(50 * time.Millisecond):
+    }
+    open[0].Body.Close()
+    open[0].Body.Close() // Closing twice gives the slot back once
+    select {
+    case err := <-third:
+        if err != nil {
+            t.Fatal(err)
+        }
+    case <-time.After(5 * time.Second):
+        t.Fatal("closing a body didn't free its slot")
+    }
+    open[1].Body.Close()
+    if served.Load() != 3 {
+        t.Errorf("served %d requests, want 3", served.Load())
+    }
+}
+
+// A server that never answers fails the request and frees its slot instead of holding it
+func TestHTTPGetTimeout(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.MaxConcurrentRequests = 1
+    })
+    stalled := make(chan struct{})
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        select {
+        case <-stalled:
+        case <-r.Context().Done():
+        }
+    }))
+    defer server.Close()
+    defer close(stalled)
+    previous := httpClient.Timeout
+    httpClient.Timeout = 50 * time.Millisecond
+    t.Cleanup(func() {
+        httpClient.Timeout = previous
+    })
+
+    for i := 0; i < 2; i++ { // The second only gets a slot if the first gave it back
+        if _, err := httpGet(server.URL); err == nil {
+            t.Fatal("request to a stalled server succeeded")
+        }
+    }
+    if _, err := httpPost(server.URL, "application/json", nil); err == nil {
+        t.Error("post to a stalled server succeeded")
+    }
+}
@@ -0,0 +1,471 @@
+package main
+
+import (
+    "encoding/csv"
+    "fmt"
+    "io"
+    "log"
+    "strconv"
+    "strings"
+    "time"
+    "unicode"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/layout"
+    "fyne.io/fyne/v2/storage"
+    "fyne.io/fyne/v2/widget"
+)
+
+// CSV and JSON import of miners with a preview where each row can be kept or skipped.
+// CSV columns default to start date, end date, T-Shares, then optionally principal HEX and
+// tags. A first row naming the columns, or with no date or number in it, is taken as a
+// header and columns with known names are mapped from it. Both can be changed in the preview.
+
+// Date layouts accepted in imported files, stored as dateLayout
+var importDateLayouts = []string{dateLayout, "2006-01-02", time.RFC3339}
+
+func parseImportDate(text string) (string, error) {
+    for _, layout := range importDateLayouts {
+        if date, err := time.Parse(layout, strings.TrimSpace(text)); err == nil {
+            return date.Format(dateLayout), nil
+        }
+    }
+    return "", fmt.Errorf("invalid date %q", text)
+}
+
+type importRow struct {
+    Line    int
+    Label   string // Shown instead of the line number, for rows not read from a file
+    Fields  []string
+    Miner   Miner
+    Err     error  // Why the row can't be imported, nil when valid
+    Warning string // Set for duplicates, which can be kept but start unchecked
+    Include bool   // Whether the user keeps the row, only valid rows can be kept
+}
+
+func (r importRow) Includable() bool {
+    return r.Err == nil
+}
+
+// Column index of each miner field, -1 for optional fields that aren't in the file
+type columnMapping struct {
+    Start     int
+    End       int
+    TShares   int
+    Principal int
+    Tags      int
+}
+
+var defaultColumnMapping = columnMapping{Start: 0, End: 1, TShares: 2, Principal: 3, Tags: 4}
+
+// Header names recognized for each field, compared without case, spaces, - and _
+var importColumnNames = map[string][]string{
+    "start":     {"start", "startdate", "begin", "stakestart"},
+    "end":       {"end", "enddate", "maturity", "maturitydate", "stakeend"},
+    "tShares":   {"tshares", "shares", "tshare"},
+    "principal": {"principal", "principalhex", "stakedhex", "hex", "amount"},
+    "tags":      {"tags", "tag", "label", "labels"},
+}
+
+// Also drops the byte order mark Excel puts before the first header
+func normalizeColumnName(name string) string {
+    return strings.Map(func(r rune) rune {
+        if r == ' ' || r == '-' || r == '_' || r == '\ufeff' {
+            return -1
+        }
+        return unicode.ToLower(r)
+    }, strings.TrimSpace(name))
+}
+
+// Mapping from the header names, ok is false unless dates and T-Shares were all found
+func detectColumns(header []string) (columnMapping, bool) {
+    find := func(field string) int {
+        for i, name := range header {
+            if contains(importColumnNames[field], normalizeColumnName(name)) {
+                return i
+            }
+        }
+        return -1
+    }
+    mapping := columnMapping{Start: find("start"), End: find("end"), TShares: find("tShares"), Principal: find("principal"), Tags: find("tags")}
+    return mapping, mapping.Start >= 0 && mapping.End >= 0 && mapping.TShares >= 0
+}
+
+func parseImportRow(fields []string, mapping columnMapping) (Miner, error) {
+    field := func(i int) string {
+        if i < 0 || i >= len(fields) {
+            return ""
+        }
+        return strings.TrimSpace(fields[i])
+    }
+    if field(mapping.Start) == "" || field(mapping.End) == "" || field(mapping.TShares) == "" {
+        return Miner{}, fmt.Errorf("expected at least start date, end date and T-Shares")
+    }
+    var miner Miner
+    var err error
+    if miner.StartDate, err = parseImportDate(field(mapping.Start)); err != nil {
+        return Miner{}, err
+    }
+    if miner.EndDate, err = parseImportDate(field(mapping.End)); err != nil {
+        return Miner{}, err
+    }
+    tShares, err := strconv.ParseFloat(sanitizeNumber(field(mapping.TShares)), 64)
+    if err != nil {
+        return Miner{}, fmt.Errorf("invalid T-Shares %q", field(mapping.TShares))
+    }
+    miner.TShares = tShares
+    if text := field(mapping.Principal); text != "" {
+        principal, err := strconv.ParseFloat(sanitizeNumber(text), 64)
+        if err != nil {
+            return Miner{}, fmt.Errorf("invalid principal HEX %q", text)
+        }
+        miner.PrincipalHEX = principal
+    }
+    miner.Tags = parseTags(field(mapping.Tags))
+    if err := validateMiner(miner); err != nil {
+        return Miner{}, err
+    }
+    return miner, nil
+}
+
+// Records of a CSV file, the header (nil when the file has none) and the line of the first record
+type csvImport struct {
+    Header    []string
+    Records   [][]string
+    FirstLine int
+}
+
+func readMinersCSV(r io.Reader) (csvImport, error) {
+    reader := csv.NewReader(r)
+    reader.FieldsPerRecord = -1
+    reader.TrimLeadingSpace = true
+    records, err := reader.ReadAll()
+    if err != nil {
+        return csvImport{}, err
+    }
+    if len(records) > 0 && len(records[0]) > 0 {
+        records[0][0] = strings.TrimPrefix(records[0][0], "\ufeff") // Excel's byte order mark
+    }
+    file := csvImport{Records: records, FirstLine: 1}
+    if len(records) > 0 && looksLikeHeader(records[0]) {
+        file = file.WithHeader(true)
+    }
+    return file, nil
+}
+
+// Whether a row names the columns: it has the dates and T-Shares headers, or none of its
+// cells reads as a date or number
+func looksLikeHeader(row []string) bool {
+    if _, ok := detectColumns(row); ok {
+        return true
+    }
+    for _, cell := range row {
+        if _, err := parseImportDate(cell); err == nil {
+            return false
+        }
+        if _, err := strconv.ParseFloat(sanitizeNumber(strings.TrimSpace(cell)), 64); err == nil {
+            return false
+        }
+    }
+    return true
+}
+
+// The same file with or without its first row taken as the header
+func (c csvImport) WithHeader(header bool) csvImport {
+    records := c.Records
+    if c.Header != nil {
+        records = append([][]string{c.Header}, c.Records...)
+    }
+    if header && len(records) > 0 {
+        return csvImport{Header: records[0], Records: records[1:], FirstLine: 2}
+    }
+    return csvImport{Records: records, FirstLine: 1}
+}
+
+// Mapping to start the preview with, from the header when it names the columns
+func (c csvImport) Mapping() columnMapping {
+    if mapping, ok := detectColumns(c.Header); ok {
+        return mapping
+    }
+    return defaultColumnMapping
+}
+
+// Names of the columns for the mapping selects
+func (c csvImport) Columns() []string {
+    count := len(c.Header)
+    for _, record := range c.Records {
+        count = max(count, len(record))
+    }
+    columns := make([]string, count)
+    for i := range columns {
+        columns[i] = fmt.Sprintf("Column %d", i+1)
+        if i < len(c.Header) && strings.TrimSpace(c.Header[i]) != "" {
+            columns[i] = fmt.Sprintf("%s (%d)", strings.TrimSpace(c.Header[i]), i+1)
+        }
+    }
+    return columns
+}
+
+// Every record as a row, invalid rows are kept with their error so the preview can show them
+func (c csvImport) Rows(mapping columnMapping) []importRow {
+    rows := make([]importRow, 0, len(c.Records))
+    for i, fields := range c.Records {
+        miner, err := parseImportRow(fields, mapping)
+        rows = append(rows, importRow{Line: c.FirstLine + i, Fields: fields, Miner: miner, Err: err, Include: err == nil})
+    }
+    return rows
+}
+
+// Reads every row of a CSV with the default or header mapping
+func parseMinersCSV(r io.Reader) ([]importRow, error) {
+    file, err := readMinersCSV(r)
+    if err != nil {
+        return nil, err
+    }
+    return file.Rows(file.Mapping()), nil
+}
+
+// Flags valid rows matching a saved miner or an earlier row (same dates and T-Shares) and
+// unchecks them. Stakes with a contract stake ID already saved can't be imported again.
+func markDuplicates(rows []importRow, existing []Miner) []importRow {
+    type key struct {
+        start, end string
+        tShares    float64
+    }
+    saved := map[key]bool{}
+    stakeIDs := map[uint64]bool{}
+    for _, miner := range existing {
+        saved[key{miner.StartDate, miner.EndDate, miner.TShares}] = true
+        if miner.StakeID != 0 {
+            stakeIDs[miner.StakeID] = true
+        }
+    }
+    seen := map[key]string{}
+    for i, row := range rows {
+        if !row.Includable() {
+            continue
+        }
+        k := key{row.Miner.StartDate, row.Miner.EndDate, row.Miner.TShares}
+        label := row.Label
+        if label == "" {
+            label = fmt.Sprintf("line %d", row.Line)
+        }
+        switch {
+        case row.Miner.StakeID != 0 && stakeIDs[row.Miner.StakeID]:
+            rows[i].Err = fmt.Errorf("already imported")
+        case saved[k]:
+            rows[i].Warning = "matches a saved miner"
+        case seen[k] != "":
+            rows[i].Warning = "same as " + seen[k]
+        default:
+            seen[k] = label
+            continue
+        }
+        rows[i].Include = false
+    }
+    return rows
+}
+
+// Miners of the rows kept by the user
+func selectedImports(rows []importRow) []Miner {
+    var miners []Miner
+    for _, row := range rows {
+        if row.Include && row.Includable() {
+            miners = append(miners, row.Miner)
+        }
+    }
+    return miners
+}
+
+func showImportMinersDialog(w fyne.Window, refreshTabs func()) {
+    openDialog := dialog.NewFileOpen(func(reader fyne.URIReadCloser, err error) {
+        if err != nil {
+            showError(err, w)
+            return
+        }
+        if reader == nil {
+            return
+        }
+        defer reader.Close()
+        if strings.EqualFold(reader.URI().Extension(), ".json") {
+            data, err := io.ReadAll(reader)
+            if err == nil {
+                var rows []importRow
+                if rows, err = parseStakesJSON(data); err == nil {
+                    if len(rows) == 0 {
+                        dialog.ShowInformation("Import Miners", "The file has no miners", w)
+                        return
+                    }
+                    showImportPreview(rows, w, refreshTabs)
+                    return
+                }
+            }
+            log.Println("Error reading JSON:", err)
+            showError(fmt.Errorf("Failed to read JSON: %v", err), w)
+            return
+        }
+        file, err := readMinersCSV(reader)
+        if err != nil {
+            log.Println("Error reading CSV:", err)
+            showError(fmt.Errorf("Failed to read CSV: %v", err), w)
+            return
+        }
+        if len(file.Records) == 0 {
+            dialog.ShowInformation("Import Miners", "The file has no miners", w)
+            return
+        }
+        showCSVImportPreview(file, w, refreshTabs)
+    }, w)
+    openDialog.SetFilter(storage.NewExtensionFileFilter([]string{".csv", ".json"}))
+    openDialog.Show()
+}
+
+// Preview with a select per miner field picking its column and a header toggle, changing
+// either re-reads the rows
+func showCSVImportPreview(file csvImport, w fyne.Window, refreshTabs func()) {
+    mapping := file.Mapping()
+    columns := file.Columns()
+    const noColumn = "(none)"
+    controls := func(rebuild func()) fyne.CanvasObject {
+        form := container.New(layout.NewFormLayout())
+        fillForm := func() {}
+        headerCheck := widget.NewCheck("First row is a header", func(checked bool) {
+            file = file.WithHeader(checked)
+            mapping = file.Mapping()
+            columns = file.Columns()
+            fillForm()
+            rebuild()
+        })
+        headerCheck.Checked = file.Header != nil
+        addSelect := func(name string, column *int, optional bool) {
+            options := append([]string(nil), columns...)
+            if optional {
+                options = append([]string{noColumn}, options...)
+            }
+            columnSelect := widget.NewSelect(options, nil)
+            if *column >= 0 && *column < len(columns) {
+                columnSelect.SetSelected(columns[*column])
+            } else if optional {
+                columnSelect.SetSelected(noColumn)
+            }
+            columnSelect.OnChanged = func(option string) {
+                *column = -1
+                for i, c := range columns {
+                    if c == option {
+                        *column = i
+                    }
+                }
+                rebuild()
+            }
+            form.Add(widget.NewLabel(name))
+            form.Add(columnSelect)
+        }
+        fillForm = func() {
+            form.Objects = nil
+            addSelect("Start Date", &mapping.Start, false)
+            addSelect("End Date", &mapping.End, false)
+            addSelect("T-Shares", &mapping.TShares, false)
+            addSelect("Principal HEX", &mapping.Principal, true)
+            addSelect("Tags", &mapping.Tags, true)
+            form.Refresh()
+        }
+        fillForm()
+        return container.NewVBox(headerCheck, form)
+    }
+    showImportPreviewWindow(w, refreshTabs, controls, func() []importRow {
+        return file.Rows(mapping)
+    })
+}
+
+func showImportPreview(rows []importRow, w fyne.Window, refreshTabs func()) {
+    showImportPreviewWindow(w, refreshTabs, nil, func() []importRow {
+        return rows
+    })
+}
+
+// Preview of the rows build returns, controls (optional) are shown above them and may call
+// rebuild after changing what build returns
+func showImportPreviewWindow(w fyne.Window, refreshTabs func(), controls func(rebuild func()) fyne.CanvasObject, build func() []importRow) {
+    previewWindow := fyne.CurrentApp().NewWindow("Import Miners")
+    previewWindow.Resize(fyne.NewSize(700, 500))
+
+    existing, err := loadMiners()
+    if err != nil {
+        log.Println("Error loading miners:", err)
+    }
+    var rows []importRow
+
+    importButton := widget.NewButton("", nil)
+    importButton.Importance = widget.HighImportance
+    updateImportButton := func() {
+        count := len(selectedImports(rows))
+        importButton.SetText(fmt.Sprintf("Import Selected (%d)", count))
+        setButtonEnabled(importButton, count > 0)
+    }
+
+    summary := widget.NewLabel("")
+    rowsBox := container.NewVBox()
+    rebuild := func() {
+        rows = markDuplicates(build(), existing)
+        rowsBox.Objects = nil
+        invalid, duplicates := 0, 0
+        for i := range rows {
+            i := i
+            row := rows[i]
+            label := row.Label
+            if label == "" {
+                label = fmt.Sprintf("Line %d", row.Line)
+            }
+            text := fmt.Sprintf("%s: %s", label, strings.Join(row.Fields, ", "))
+            check := widget.NewCheck(text, func(checked bool) {
+                rows[i].Include = checked
+                updateImportButton()
+            })
+            check.Checked = row.Include
+            switch {
+            case !row.Includable():
+                invalid++
+                check.Disable()
+                errorLabel := widget.NewLabel(row.Err.Error())
+                errorLabel.Importance = widget.DangerImportance
+                rowsBox.Add(container.NewHBox(check, errorLabel))
+            case row.Warning != "":
+                duplicates++
+                warningLabel := widget.NewLabel(row.Warning)
+                warningLabel.Importance = widget.WarningImportance
+                rowsBox.Add(container.NewHBox(check, warningLabel))
+            default:
+                rowsBox.Add(check)
+            }
+        }
+        rowsBox.Refresh()
+        summary.SetText(fmt.Sprintf("%d rows, %d can't be imported, %d look like duplicates. Uncheck rows to skip them.", len(rows), invalid, duplicates))
+        updateImportButton()
+    }
+    rebuild()
+
+    importButton.OnTapped = func() {
+        imported := selectedImports(rows)
+        for i := range imported {
+            imported[i].ID = newMinerID()
+        }
+        if err := addStoredMiners(imported); err != nil {
+            log.Println("Error saving miners:", err)
+            showError(fmt.Errorf("Failed to save miners"), previewWindow)
+            return
+        }
+        previewWindow.Close()
+        refreshTabs()
+        dialog.ShowInformation("Import Miners", fmt.Sprintf("Imported %d miners", len(imported)), w)
+    }
+    cancelButton := widget.NewButton("Cancel", previewWindow.Close)
+
+    top := container.NewVBox(summary)
+    if controls != nil {
+        top.Add(controls(rebuild))
+    }
+    previewWindow.SetContent(container.NewBorder(top, container.NewHBox(importButton, cancelButton), nil, nil, container.NewVScroll(rowsBox)))
+    previewWindow.Show()
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+    "fmt"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestReadMinersCSVHeader(t *testing.T) {
+    tests := []struct {
+        name   string
+        in     string
+        header bool
+    }{
+        {"dates first", "01-01-2025,01-01-2026,2.5\n", false},
+        {"known names", "Start Date,End Date,T-Shares\n01-01-2025,01-01-2026,2.5\n", true},
+        {"names in another order", "tags,tshares,start,end\nx,2.5,01-01-2025,01-01-2026\n", true},
+        {"unknown names", "From,To,Size\n01-01-2025,01-01-2026,2.5\n", true},
+        {"tags first", "long-term,01-01-2025,01-01-2026,2.5\n", false},
+        {"ISO dates", "2025-01-01,2026-01-01,2.5\n", false},
+        {"number first", "2.5,01-01-2025,01-01-2026\n", false},
+    }
+    for _, tt := range tests {
+        file, err := readMinersCSV(strings.NewReader(tt.in))
+        if err != nil {
+            t.Fatalf("%s: %v", tt.name, err)
+        }
+        if got := file.Header != nil; got != tt.header {
+            t.Errorf("%s: header = %v, want %v", tt.name, got, tt.header)
+        }
+        if len(file.Records) != 1 {
+            t.Errorf("%s: %d records, want 1", tt.name, len(file.Records))
+        }
+    }
+}
+
+func TestCSVImportWithHeader(t *testing.T) {
+    file, err := readMinersCSV(strings.NewReader("Start,End,T-Shares\n01-01-2025,01-01-2026,2.5\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := file.Mapping(); got != (columnMapping{Start: 0, End: 1, TShares: 2, Principal: -1, Tags: -1}) {
+        t.Errorf("mapping from the header = %+v", got)
+    }
+    if want := []string{"Start (1)", "End (2)", "T-Shares (3)"}; !reflect.DeepEqual(file.Columns(), want) {
+        t.Errorf("columns = %q, want %q", file.Columns(), want)
+    }
+
+    // Turning the header off makes its row a record again, on line 1
+    plain := file.WithHeader(false)
+    if plain.Header != nil || len(plain.Records) != 2 || plain.FirstLine != 1 {
+        t.Fatalf("without the header: %+v", plain)
+    }
+    if plain.Mapping() != defaultColumnMapping {
+        t.Errorf("mapping without a header = %+v, want the default", plain.Mapping())
+    }
+    if rows := plain.Rows(plain.Mapping()); rows[0].Includable() || rows[0].Line != 1 || !rows[1].Includable() || rows[1].Line != 2 {
+        t.Errorf("rows without the header = %+v", rows)
+    }
+    if again := plain.WithHeader(true); !reflect.DeepEqual(again, file) {
+        t.Errorf("header back on = %+v, want %+v", again, file)
+    }
+}
+
+// Only valid rows can be included, and they start checked
+func TestCSVImportRows(t *testing.T) {
+    in := "01-01-2025,01-01-2026,2.5,1000,long-term\n" +
+        "01-01-2025,01-01-2026,lots\n" +
+        "01-01-2026,01-01-2025,1\n" +
+        "01-01-2025\n" +
+        "01-02-2025,01-02-2026,\"1,500\"\n"
+    rows, err := parseMinersCSV(strings.NewReader(in))
+    if err != nil {
+        t.Fatal(err)
+    }
+    want := []struct {
+        includable bool
+        err        string
+    }{
+        {true, ""},
+        {false, "invalid T-Shares"},
+        {false, "end date is before start date"},
+        {false, "expected at least"},
+        {true, ""},
+    }
+    if len(rows) != len(want) {
+        t.Fatalf("got %d rows, want %d", len(rows), len(want))
+    }
+    for i, w := range want {
+        row := rows[i]
+        if row.Includable() != w.includable || row.Include != w.includable || row.Line != i+1 {
+            t.Errorf("row %d = %+v, want includable %v", i+1, row, w.includable)
+        }
+        if w.err != "" && (row.Err == nil || !strings.Contains(row.Err.Error(), w.err)) {
+            t.Errorf("row %d error = %v, want %q", i+1, row.Err, w.err)
+        }
+    }
+    if rows[0].Miner.PrincipalHEX != 1000 || !reflect.DeepEqual(rows[0].Miner.Tags, []string{"long-term"}) || rows[4].Miner.TShares != 1500 {
+        t.Errorf("parsed miners %+v and %+v", rows[0].Miner, rows[4].Miner)
+    }
+
+    // Unchecking a valid row skips it, checking an invalid one doesn't import it
+    rows[0].Include, rows[1].Include = false, true
+    if got := selectedImports(rows); len(got) != 1 || got[0].TShares != 1500 {
+        t.Errorf("selectedImports = %+v, want only the last row", got)
+    }
+}
+
+func TestMarkDuplicates(t *testing.T) {
+    miner := func(start string, tShares float64, stakeID uint64) Miner {
+        return Miner{StartDate: start, EndDate: "01-01-2027", TShares: tShares, StakeID: stakeID}
+    }
+    existing := []Miner{miner("01-01-2025", 1, 0), miner("01-02-2025", 2, 42)}
+    rows := []importRow{
+        {Line: 1, Miner: miner("01-01-2025", 1, 0)},   // Same as a saved miner
+        {Line: 2, Miner: miner("01-03-2025", 3, 0)},   // New
+        {Line: 3, Miner: miner("01-03-2025", 3, 0)},   // Same as line 2
+        {Line: 4, Miner: miner("01-04-2025", 4, 42)},  // Stake ID already saved
+        {Line: 5, Miner: miner("01-01-2025", 1.5, 0)}, // Other T-Shares on a saved date
+        {Line: 6, Err: fmt.Errorf("bad row")},
+    }
+    for i := range rows {
+        rows[i].Include = rows[i].Err == nil
+    }
+    rows = markDuplicates(rows, existing)
+    want := []struct {
+        include bool
+        warning string
+        err     bool
+    }{
+        {false, "matches a saved miner", false},
+        {true, "", false},
+        {false, "same as line 2", false},
+        {false, "", true},
+        {true, "", false},
+        {false, "", true},
+    }
+    for i, w := range want {
+        row := rows[i]
+        if row.Include != w.include || row.Warning != w.warning || (row.Err != nil) != w.err {
+            t.Errorf("line %d: include %v, warning %q, error %v", row.Line, row.Include, row.Warning, row.Err)
+        }
+    }
+    // Duplicates can still be kept, stakes already imported can't
+    rows[0].Include, rows[3].Include = true, true
+    if got := selectedImports(rows); len(got) != 3 {
+        t.Errorf("selectedImports = %+v, want the two new rows and the kept duplicate", got)
+    }
+}
+
+// Excel writes a byte order mark before the first cell, and ISO dates are stored as dateLayout
+func TestCSVImportExcelFile(t *testing.T) {
+    if got := normalizeColumnName("\ufeffStart Date"); got != "startdate" {
+        t.Errorf("normalizeColumnName with a byte order mark = %q", got)
+    }
+    file, err := readMinersCSV(strings.NewReader("\ufeffEnd,Start,Shares\n2026-01-01,2025-01-01,2.5\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if got := file.Mapping(); got != (columnMapping{Start: 1, End: 0, TShares: 2, Principal: -1, Tags: -1}) {
+        t.Errorf("mapping from a header with a byte order mark = %+v", got)
+    }
+    rows := file.Rows(file.Mapping())
+    if len(rows) != 1 || !rows[0].Includable() || rows[0].Miner.StartDate != "01-01-2025" || rows[0].Miner.EndDate != "01-01-2026" {
+        t.Errorf("rows = %+v, want the ISO dates as DD-MM-YYYY", rows)
+    }
+
+    // Without a header the first date keeps working too
+    plain, err := readMinersCSV(strings.NewReader("\ufeff01-01-2025,2026-01-01T00:00:00Z,2.5\n"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if rows := plain.Rows(plain.Mapping()); plain.Header != nil || len(rows) != 1 || !rows[0].Includable() || rows[0].Miner.EndDate != "01-01-2026" {
+        t.Errorf("rows without a header = %+v", rows)
+    }
+}
+
+func TestParseImportDate(t *testing.T) {
+    for _, text := range []string{"01-06-2025", " 2025-06-01 ", "2025-06-01T10:00:00Z"} {
+        if got, err := parseImportDate(text); err != nil || got != "01-06-2025" {
+            t.Errorf("parseImportDate(%q) = %q, %v, want 01-06-2025", text, got, err)
+        }
+    }
+    for _, text := range []string{"", "06/01/2025", "31-02-2025", "soon"} {
+        if got, err := parseImportDate(text); err == nil {
+            t.Errorf("parseImportDate(%q) = %q, want an error", text, got)
+        }
+    }
+}
+
+func TestParseStakesJSON(t *testing.T) {
+    contract := `{"stakeId": 7, "lockedDay": 1000, "stakedDays": "365", "stakeShares": {"_hex": "0x02540be400", "_isBigNumber": true}, "stakedHearts": "150000000000", "chain": "369"}`
+    dated := `{"startDate": "2025-01-01", "endDate": "01-01-2026", "tShares": "2.5", "principal": 1000, "chain": "ethereum"}`
+    tests := []struct {
+        name string
+        in   string
+        want []Miner // nil where the entry is an error
+    }{
+        {"list", "[" + contract + "," + dated + "]", []Miner{
+            {StartDate: "29-08-2022", EndDate: "29-08-2023", TShares: 0.01, PrincipalHEX: 1500, StakeID: 7},
+            {StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 2.5, PrincipalHEX: 1000, Chain: chainEthereum},
+        }},
+        {"under stakes", `{"stakes": [` + dated + `]}`, []Miner{
+            {StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 2.5, PrincipalHEX: 1000, Chain: chainEthereum},
+        }},
+        {"bad entries", `{"result": [42, {"startDate": "2025-01-01"}, {"lockedDay": 1, "stakedDays": 2, "stakeShares": "x", "stakedHearts": 1}, {"startDate": "2025-01-01", "endDate": "2026-01-01", "tShares": 1, "chain": "bsc"}]}`, []Miner{{}, {}, {}, {}}},
+    }
+    for _, tt := range tests {
+        rows, err := parseStakesJSON([]byte(tt.in))
+        if err != nil {
+            t.Fatalf("%s: %v", tt.name, err)
+        }
+        if len(rows) != len(tt.want) {
+            t.Fatalf("%s: %d rows, want %d", tt.name, len(rows), len(tt.want))
+        }
+        for i, row := range rows {
+            valid := !reflect.DeepEqual(tt.want[i], Miner{})
+            if row.Includable() != valid || row.Include != valid || row.Line != i+1 {
+                t.Errorf("%s: row %d = %+v, want includable %v", tt.name, i+1, row, valid)
+            }
+            if valid && !reflect.DeepEqual(row.Miner, tt.want[i]) {
+                t.Errorf("%s: row %d miner = %+v, want %+v", tt.name, i+1, row.Miner, tt.want[i])
+            }
+        }
+    }
+    if rows, _ := parseStakesJSON([]byte("[" + contract + "]")); rows[0].Label != "Stake #7" {
+        t.Errorf("label = %q, want the stake ID", rows[0].Label)
+    }
+    for _, in := range []string{`{"stakes": 1}`, `"text"`, `not json`} {
+        if _, err := parseStakesJSON([]byte(in)); err == nil {
+            t.Errorf("parseStakesJSON(%s) accepted", in)
+        }
+    }
+}
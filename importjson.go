@@ -0,0 +1,210 @@
+package main
+
+import (
+    "bytes"
+    "encoding/json"
+    "fmt"
+    "math/big"
+    "strconv"
+    "strings"
+)
+
+// JSON stake lists in two generic shapes. Each stake either has the contract's fields
+// (stakeId, lockedDay, stakedDays, stakeShares, stakedHearts) or dates with T-Shares
+// (startDate, endDate, tShares, principal). The list is the whole file or under "stakes",
+// "data" or "result". Exports of particular tools weren't checked against, they import when
+// they use one of these shapes.
+
+func parseStakesJSON(data []byte) ([]importRow, error) {
+    decoder := json.NewDecoder(bytes.NewReader(data))
+    decoder.UseNumber()
+    var doc interface{}
+    if err := decoder.Decode(&doc); err != nil {
+        return nil, err
+    }
+    list, ok := doc.([]interface{})
+    if object, isObject := doc.(map[string]interface{}); isObject {
+        for _, key := range []string{"stakes", "data", "result"} {
+            if list, ok = object[key].([]interface{}); ok {
+                break
+            }
+        }
+    }
+    if !ok {
+        return nil, fmt.Errorf("no list of stakes found")
+    }
+    rows := make([]importRow, 0, len(list))
+    for i, item := range list {
+        row := importRow{Line: i + 1, Label: fmt.Sprintf("Entry %d", i+1)}
+        stake, isObject := item.(map[string]interface{})
+        if !isObject {
+            row.Err = fmt.Errorf("not a stake object")
+            rows = append(rows, row)
+            continue
+        }
+        row.Miner, row.Err = jsonStakeToMiner(stake)
+        if row.Err == nil {
+            if row.Miner.StakeID != 0 {
+                row.Label = fmt.Sprintf("Stake #%d", row.Miner.StakeID)
+            }
+            row.Fields = []string{row.Miner.StartDate, row.Miner.EndDate, formatTShares(row.Miner.TShares) + " T-Shares"}
+            if row.Miner.PrincipalHEX > 0 {
+                row.Fields = append(row.Fields, formatWithCommas(int(row.Miner.PrincipalHEX))+" HEX")
+            }
+            row.Err = validateMiner(row.Miner)
+        }
+        row.Include = row.Err == nil
+        rows = append(rows, row)
+    }
+    return rows, nil
+}
+
+// Value of the first of keys the stake has, matched without case
+func jsonField(stake map[string]interface{}, keys ...string) (interface{}, bool) {
+    for _, key := range keys {
+        for name, value := range stake {
+            if strings.EqualFold(name, key) && value != nil {
+                return value, true
+            }
+        }
+    }
+    return nil, false
+}
+
+// Integer from a JSON number, a decimal or 0x string, or an ethers BigNumber object
+func jsonBigInt(value interface{}) (*big.Int, error) {
+    switch v := value.(type) {
+    case json.Number:
+        n, ok := new(big.Int).SetString(v.String(), 10)
+        if !ok {
+            return nil, fmt.Errorf("%s is not a whole number", v)
+        }
+        return n, nil
+    case string:
+        text := strings.TrimSpace(v)
+        base := 10
+        if strings.HasPrefix(text, "0x") || strings.HasPrefix(text, "0X") {
+            text, base = text[2:], 16
+        }
+        n, ok := new(big.Int).SetString(text, base)
+        if !ok {
+            return nil, fmt.Errorf("%q is not a whole number", v)
+        }
+        return n, nil
+    case map[string]interface{}:
+        if hex, ok := jsonField(v, "hex", "_hex"); ok {
+            return jsonBigInt(hex)
+        }
+    }
+    return nil, fmt.Errorf("unexpected value %v", value)
+}
+
+func jsonFloat(value interface{}) (float64, error) {
+    switch v := value.(type) {
+    case json.Number:
+        return v.Float64()
+    case string:
+        return strconv.ParseFloat(sanitizeNumber(v), 64)
+    }
+    return 0, fmt.Errorf("unexpected value %v", value)
+}
+
+func jsonDate(value interface{}) (string, error) {
+    text, ok := value.(string)
+    if !ok {
+        return "", fmt.Errorf("unexpected date %v", value)
+    }
+    return parseImportDate(text)
+}
+
+// Chain from a name or chain ID, "" when the stake doesn't say
+func jsonChain(stake map[string]interface{}) (string, error) {
+    value, ok := jsonField(stake, "chain", "network", "chainId")
+    if !ok {
+        return "", nil
+    }
+    switch strings.ToLower(strings.TrimSpace(fmt.Sprint(value))) {
+    case "pulsechain", "pls", "369":
+        return chainPulsechain, nil
+    case "ethereum", "eth", "mainnet", "1":
+        return chainEthereum, nil
+    }
+    return "", fmt.Errorf("unknown chain %v", value)
+}
+
+func jsonStakeToMiner(stake map[string]interface{}) (Miner, error) {
+    chain, err := jsonChain(stake)
+    if err != nil {
+        return Miner{}, err
+    }
+    var miner Miner
+    if _, ok := jsonField(stake, "lockedDay"); ok {
+        miner, err = jsonContractStake(stake)
+    } else {
+        miner, err = jsonDatedStake(stake)
+    }
+    if err != nil {
+        return Miner{}, err
+    }
+    miner.Chain = storedChain(chain)
+    return miner, nil
+}
+
+// Stake with the fields of the contract's stakeLists, shares and hearts unscaled
+func jsonContractStake(stake map[string]interface{}) (Miner, error) {
+    fields := map[string]*big.Int{}
+    for _, key := range []string{"lockedDay", "stakedDays", "stakeShares", "stakedHearts"} {
+        value, ok := jsonField(stake, key)
+        if !ok {
+            return Miner{}, fmt.Errorf("missing %s", key)
+        }
+        n, err := jsonBigInt(value)
+        if err != nil {
+            return Miner{}, fmt.Errorf("%s: %v", key, err)
+        }
+        fields[key] = n
+    }
+    if !fields["lockedDay"].IsInt64() || !fields["stakedDays"].IsInt64() || fields["lockedDay"].Int64() > 1e5 || fields["stakedDays"].Int64() > 1e5 {
+        return Miner{}, fmt.Errorf("lockedDay or stakedDays out of range")
+    }
+    walletStake := walletStake{
+        StakedHearts: fields["stakedHearts"],
+        StakeShares:  fields["stakeShares"],
+        LockedDay:    int(fields["lockedDay"].Int64()),
+        StakedDays:   int(fields["stakedDays"].Int64()),
+    }
+    if value, ok := jsonField(stake, "stakeId"); ok {
+        id, err := jsonBigInt(value)
+        if err != nil || !id.IsUint64() {
+            return Miner{}, fmt.Errorf("invalid stakeId %v", value)
+        }
+        walletStake.StakeID = id.Uint64()
+    }
+    return walletStakeToMiner(walletStake), nil
+}
+
+func jsonDatedStake(stake map[string]interface{}) (Miner, error) {
+    var miner Miner
+    var err error
+    start, ok := jsonField(stake, "startDate", "start")
+    end, hasEnd := jsonField(stake, "endDate", "end")
+    tShares, hasTShares := jsonField(stake, "tShares", "shares")
+    if !ok || !hasEnd || !hasTShares {
+        return Miner{}, fmt.Errorf("expected lockedDay and stakedDays, or startDate, endDate and tShares")
+    }
+    if miner.StartDate, err = jsonDate(start); err != nil {
+        return Miner{}, err
+    }
+    if miner.EndDate, err = jsonDate(end); err != nil {
+        return Miner{}, err
+    }
+    if miner.TShares, err = jsonFloat(tShares); err != nil {
+        return Miner{}, fmt.Errorf("invalid T-Shares: %v", err)
+    }
+    if principal, ok := jsonField(stake, "principal", "principalHEX", "stakedHex"); ok {
+        if miner.PrincipalHEX, err = jsonFloat(principal); err != nil {
+            return Miner{}, fmt.Errorf("invalid principal HEX: %v", err)
+        }
+    }
+    return miner, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/app"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Single-instance lock: an OS lock on a file, so two instances don't clobber each other's
+// miners.json and config.json. The OS drops the lock when the process dies, so a crash or a
+// PID reused after a reboot can't block the next launch. The file holds the PID for display only.
+const instanceLockFile = "settings/hexfetch.lock"
+
+var errAlreadyRunning = errors.New("another instance is already running")
+
+// Locks the file and writes this process's PID into it.
+// The returned func unlocks it on exit.
+func acquireInstanceLock(path string) (func(), error) {
+    file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+    if err != nil {
+        return nil, err
+    }
+    if err := lockFile(file); err != nil {
+        file.Close()
+        if errors.Is(err, errLockHeld) {
+            return nil, errAlreadyRunning
+        }
+        return nil, err
+    }
+    if err := writeLockPID(file); err != nil {
+        unlockFile(file)
+        file.Close()
+        return nil, err
+    }
+    // The file is kept, removing it would let a launch waiting on the old file and one
+    // creating a new file both get a lock
+    return func() {
+        file.Truncate(0)
+        unlockFile(file)
+        file.Close()
+    }, nil
+}
+
+func writeLockPID(file *os.File) error {
+    if err := file.Truncate(0); err != nil {
+        return err
+    }
+    _, err := file.WriteAt([]byte(fmt.Sprintf("%d\n", os.Getpid())), 0)
+    return err
+}
+
+// The PID written by the instance holding the lock, 0 when it can't be read
+func lockHolderPID(path string) int {
+    file, err := os.Open(path)
+    if err != nil {
+        return 0
+    }
+    defer file.Close()
+    contents, err := io.ReadAll(io.LimitReader(file, 32))
+    if err != nil {
+        return 0
+    }
+    pid, err := strconv.Atoi(strings.TrimSpace(string(contents)))
+    if err != nil || pid <= 0 {
+        return 0
+    }
+    return pid
+}
+
+func showAlreadyRunning() {
+    a := app.New()
+    w := a.NewWindow("HEX Stats")
+    w.SetContent(container.NewVBox(
+        widget.NewLabel("HEX Stats is already running. Close the other window before starting it again."),
+        widget.NewButton("OK", a.Quit),
+    ))
+    w.Resize(fyne.NewSize(400, 100))
+    w.ShowAndRun()
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "strconv"
+    "testing"
+)
+
+func TestAcquireInstanceLock(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "hexfetch.lock")
+    release, err := acquireInstanceLock(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if pid := lockHolderPID(path); pid != os.Getpid() {
+        t.Errorf("lock holds PID %d, want this process", pid)
+    }
+    if _, err := acquireInstanceLock(path); err != errAlreadyRunning {
+        t.Errorf("second lock: err = %v, want errAlreadyRunning", err)
+    }
+    release()
+    if pid := lockHolderPID(path); pid != 0 {
+        t.Errorf("released lock still shows PID %d", pid)
+    }
+
+    // A file left by a crashed process doesn't hold a lock and is taken over
+    if err := os.WriteFile(path, []byte("not a pid\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    release, err = acquireInstanceLock(path)
+    if err != nil {
+        t.Fatalf("stale lock not replaced: %v", err)
+    }
+    defer release()
+    contents, _ := os.ReadFile(path)
+    if string(contents) != strconv.Itoa(os.Getpid())+"\n" {
+        t.Errorf("lock holds %q, want this process", contents)
+    }
+}
+
+// After a reboot the PID in a leftover file can belong to a running process, only the OS lock counts
+func TestAcquireInstanceLockReusedPID(t *testing.T) {
+    path := filepath.Join(t.TempDir(), "hexfetch.lock")
+    if err := os.WriteFile(path, []byte(strconv.Itoa(os.Getppid())+"\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    release, err := acquireInstanceLock(path)
+    if err != nil {
+        t.Fatalf("lock with a live but unrelated PID: %v", err)
+    }
+    release()
+}
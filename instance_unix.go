@@ -0,0 +1,24 @@
+//go:build !windows
+
+package main
+
+import (
+    "errors"
+    "os"
+    "syscall"
+)
+
+var errLockHeld = syscall.EWOULDBLOCK
+
+// flock belongs to the open file, so it's released when the process exits in any way
+func lockFile(file *os.File) error {
+    err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX|syscall.LOCK_NB)
+    if errors.Is(err, syscall.EAGAIN) {
+        return errLockHeld
+    }
+    return err
+}
+
+func unlockFile(file *os.File) error {
+    return syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+}
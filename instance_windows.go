@@ -0,0 +1,44 @@
+package main
+
+import (
+    "os"
+    "syscall"
+    "unsafe"
+)
+
+var (
+    lockFileEx   = syscall.NewLazyDLL("kernel32.dll").NewProc("LockFileEx")
+    unlockFileEx = syscall.NewLazyDLL("kernel32.dll").NewProc("UnlockFileEx")
+)
+
+const (
+    lockfileFailImmediately = 0x00000001
+    lockfileExclusiveLock   = 0x00000002
+    errorLockViolation      = syscall.Errno(33)
+)
+
+var errLockHeld = errorLockViolation
+
+// One byte far past the PID is locked, so other instances can still read the PID.
+// Windows releases the lock when the handle is closed, the process exiting included.
+const lockOffsetHigh = 0x7fffffff
+
+func lockFile(file *os.File) error {
+    var overlapped syscall.Overlapped
+    overlapped.OffsetHigh = lockOffsetHigh
+    r, _, err := lockFileEx.Call(file.Fd(), lockfileExclusiveLock|lockfileFailImmediately, 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+    if r == 0 {
+        return err
+    }
+    return nil
+}
+
+func unlockFile(file *os.File) error {
+    var overlapped syscall.Overlapped
+    overlapped.OffsetHigh = lockOffsetHigh
+    r, _, err := unlockFileEx.Call(file.Fd(), 0, 1, 0, uintptr(unsafe.Pointer(&overlapped)))
+    if r == 0 {
+        return err
+    }
+    return nil
+}
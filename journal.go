@@ -0,0 +1,177 @@
+package main
+
+import (
+    "bufio"
+    "encoding/json"
+    "log"
+    "os"
+    "reflect"
+    "sync"
+)
+
+// Optional append-only journal of miner mutations. It always starts with a snapshot of
+// the miners, followed by the add/update/delete/complete entries recorded before each save.
+// Replaying it gives the latest state even if miners.json was left half-written.
+// Entries are keyed by miner ID so replaying one that already reached miners.json is harmless.
+// Each portfolio has its own journal next to its miners.json, see minersJournalPath.
+
+type journalEntry struct {
+    Op          string  `json:"op"`                    // snapshot, add, update, delete or complete
+    Miners      []Miner `json:"miners,omitempty"`
+    Miner       *Miner  `json:"miner,omitempty"`
+    ID          string  `json:"id,omitempty"`
+    RealizedHEX float64 `json:"realizedHEX,omitempty"` // Yield recorded by complete
+}
+
+var journalMutex sync.Mutex
+
+func journalEnabled() bool {
+    return configManager.GetConfig().MinerJournal
+}
+
+// Records a mutation before it is saved, call before saveMiners
+func journalAppend(entry journalEntry) {
+    if !journalEnabled() {
+        return
+    }
+    journalMutex.Lock()
+    defer journalMutex.Unlock()
+    file, err := os.OpenFile(minersJournalPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+    if err != nil {
+        log.Println("Error opening miners journal:", err)
+        return
+    }
+    defer file.Close()
+    line, err := json.Marshal(entry)
+    if err == nil {
+        _, err = file.Write(append(line, '\n'))
+    }
+    if err == nil {
+        err = file.Sync()
+    }
+    if err != nil {
+        log.Println("Error writing miners journal:", err)
+    }
+}
+
+// Replaces the journal with a snapshot of miners once they are safely saved
+func journalCheckpoint(miners []Miner) {
+    if !journalEnabled() {
+        return
+    }
+    journalMutex.Lock()
+    defer journalMutex.Unlock()
+    line, err := json.Marshal(journalEntry{Op: "snapshot", Miners: miners})
+    if err != nil {
+        log.Println("Error writing miners journal:", err)
+        return
+    }
+    if err := writeFileSynced(minersJournalPath(), append(line, '\n')); err != nil {
+        log.Println("Error writing miners journal:", err)
+    }
+}
+
+// Writes to a temporary file and renames it over path so readers never see a partial file
+func writeFileSynced(path string, data []byte) error {
+    tmp := path + ".tmp"
+    file, err := os.Create(tmp)
+    if err != nil {
+        return err
+    }
+    if _, err := file.Write(data); err != nil {
+        file.Close()
+        return err
+    }
+    if err := file.Sync(); err != nil {
+        file.Close()
+        return err
+    }
+    if err := file.Close(); err != nil {
+        return err
+    }
+    return os.Rename(tmp, path)
+}
+
+func readJournal(path string) ([]journalEntry, error) {
+    file, err := os.Open(path)
+    if err != nil {
+        return nil, err
+    }
+    defer file.Close()
+    var entries []journalEntry
+    scanner := bufio.NewScanner(file)
+    scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+    for scanner.Scan() {
+        var entry journalEntry
+        if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+            // A crash mid-append leaves a torn last line, everything before it still counts
+            log.Println("Ignoring unreadable miners journal entry:", err)
+            break
+        }
+        entries = append(entries, entry)
+    }
+    return entries, scanner.Err()
+}
+
+// Rebuilds the miners from journal entries
+func replayJournal(entries []journalEntry) []Miner {
+    miners := []Miner{}
+    for _, entry := range entries {
+        switch entry.Op {
+        case "snapshot":
+            miners = append([]Miner{}, entry.Miners...)
+        case "add":
+            if entry.Miner == nil {
+                continue
+            }
+            exists := false
+            for _, m := range miners {
+                if m.ID == entry.Miner.ID {
+                    exists = true
+                    break
+                }
+            }
+            if !exists {
+                miners = append(miners, *entry.Miner)
+            }
+        case "update":
+            if entry.Miner != nil {
+                updateMiner(miners, *entry.Miner)
+            }
+        case "delete":
+            miners = deleteMiner(miners, entry.ID)
+        case "complete":
+            completeMiner(miners, entry.ID, entry.RealizedHEX)
+        }
+    }
+    return miners
+}
+
+// On startup, restores miners.json from the journal if it is missing the latest mutations
+// or can't be read. Without journaling any old journal is removed so it can't go stale.
+func recoverMinersFromJournal() {
+    if !journalEnabled() {
+        os.Remove(minersJournalPath())
+        return
+    }
+    entries, err := readJournal(minersJournalPath())
+    if err != nil {
+        if !os.IsNotExist(err) {
+            log.Println("Error reading miners journal:", err)
+        }
+        return
+    }
+    if len(entries) == 0 || entries[0].Op != "snapshot" {
+        log.Println("Miners journal has no snapshot, ignoring it")
+        return
+    }
+    journaled := replayJournal(entries)
+    current, err := loadMiners()
+    if err == nil && reflect.DeepEqual(current, journaled) {
+        return
+    }
+    log.Println("Restoring miners from journal")
+    if err := saveMiners(journaled); err != nil {
+        log.Println("Error saving miners:", err)
+    }
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+func TestReplayJournal(t *testing.T) {
+    a := Miner{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 1}
+    b := Miner{ID: "b", StartDate: "01-02-2025", EndDate: "01-02-2026", TShares: 2}
+    c := Miner{ID: "c", StartDate: "01-03-2025", EndDate: "01-03-2026", TShares: 3}
+    edited := b
+    edited.TShares = 2.5
+    entries := []journalEntry{
+        {Op: "snapshot", Miners: []Miner{a, b}},
+        {Op: "add", Miner: &c},
+        {Op: "add", Miner: &c}, // Replayed twice, still added once
+        {Op: "update", Miner: &edited},
+        {Op: "complete", ID: "a", RealizedHEX: 1500},
+        {Op: "delete", ID: "c"},
+        {Op: "add"}, // Malformed, skipped
+    }
+    got := replayJournal(entries)
+    completed := a
+    completed.Status, completed.RealizedHEX = "completed", 1500
+    if want := []Miner{completed, edited}; !reflect.DeepEqual(got, want) {
+        t.Errorf("replayJournal = %+v, want %+v", got, want)
+    }
+    // A later snapshot replaces everything before it
+    entries = append(entries, journalEntry{Op: "snapshot", Miners: []Miner{c}})
+    if got := replayJournal(entries); !reflect.DeepEqual(got, []Miner{c}) {
+        t.Errorf("replayJournal after a snapshot = %+v, want only the snapshot", got)
+    }
+}
+
+// A crash after the journal entry but before miners.json was fully written loses nothing
+func TestRecoverMinersAfterCrash(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.MinerJournal = true
+    })
+    useTempStorage(t)
+    a := Miner{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 1}
+    b := Miner{ID: "b", StartDate: "01-02-2025", EndDate: "01-02-2026", TShares: 2}
+    if err := saveMiners([]Miner{a}); err != nil {
+        t.Fatal(err)
+    }
+
+    // The add is journaled, then the app dies halfway through writing miners.json
+    journalAppend(journalEntry{Op: "add", Miner: &b})
+    minersPath := filepath.Join("settings", "miners.json")
+    if err := os.WriteFile(minersPath, []byte(`[{"id": "a", "startDa`), 0644); err != nil {
+        t.Fatal(err)
+    }
+    // and the next entry was torn mid-append
+    file, err := os.OpenFile(minersJournalPath(), os.O_WRONLY|os.O_APPEND, 0644)
+    if err != nil {
+        t.Fatal(err)
+    }
+    file.WriteString(`{"op": "delete", "id": "a`)
+    file.Close()
+
+    recoverMinersFromJournal()
+    miners, err := loadMiners()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if want := []Miner{a, b}; !reflect.DeepEqual(miners, want) {
+        t.Errorf("recovered %+v, want %+v", miners, want)
+    }
+    entries, err := readJournal(minersJournalPath())
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(entries) != 1 || entries[0].Op != "snapshot" {
+        t.Errorf("journal after recovery = %+v, want a single snapshot", entries)
+    }
+}
+
+func TestRecoverMinersJournalDisabled(t *testing.T) {
+    useConfig(t, nil)
+    useTempStorage(t)
+    a := Miner{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 1}
+    if err := os.WriteFile(minersJournalPath(), []byte(`{"op": "snapshot", "miners": [{"id": "stale"}]}`+"\n"), 0644); err != nil {
+        t.Fatal(err)
+    }
+    if err := saveMiners([]Miner{a}); err != nil {
+        t.Fatal(err)
+    }
+    recoverMinersFromJournal()
+    if miners, _ := loadMiners(); !reflect.DeepEqual(miners, []Miner{a}) {
+        t.Errorf("miners = %+v, an old journal was replayed with journaling off", miners)
+    }
+    if _, err := os.Stat(minersJournalPath()); !os.IsNotExist(err) {
+        t.Error("old journal wasn't removed with journaling off")
+    }
+}
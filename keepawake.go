@@ -0,0 +1,63 @@
+package main
+
+import (
+    "log"
+    "sync"
+)
+
+// Keeps the display awake while the window is focused and the setting is on.
+// acquireDisplayWake/releaseDisplayWake live in the platform files, they are
+// no-ops where the platform has no supported mechanism.
+type screenWaker struct {
+    mu      sync.Mutex
+    focused bool
+    held    bool
+}
+
+var screenWake = &screenWaker{focused: true}
+
+const keepScreenOnHelp = "Supported on Windows, macOS (caffeinate) and Linux (systemd-inhibit), ignored elsewhere"
+
+func (s *screenWaker) Focus(enabled bool) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.focused = true
+    s.setLocked(enabled)
+}
+
+func (s *screenWaker) Blur() {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.focused = false
+    s.setLocked(false)
+}
+
+// Applies a changed setting, only takes effect while focused
+func (s *screenWaker) SetEnabled(enabled bool) {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.setLocked(s.focused && enabled)
+}
+
+func (s *screenWaker) Release() {
+    s.mu.Lock()
+    defer s.mu.Unlock()
+    s.setLocked(false)
+}
+
+func (s *screenWaker) setLocked(on bool) {
+    if on == s.held {
+        return
+    }
+    var err error
+    if on {
+        err = acquireDisplayWake()
+    } else {
+        err = releaseDisplayWake()
+    }
+    if err != nil {
+        log.Println("Error changing keep screen on:", err)
+        return
+    }
+    s.held = on
+}
@@ -0,0 +1,12 @@
+package main
+
+import (
+    "os"
+    "os/exec"
+    "strconv"
+)
+
+// caffeinate -w exits on its own if the app dies without releasing
+func wakeCommand() *exec.Cmd {
+    return exec.Command("caffeinate", "-d", "-w", strconv.Itoa(os.Getpid()))
+}
@@ -0,0 +1,27 @@
+//go:build darwin || linux
+
+package main
+
+import "os/exec"
+
+// Helper process holding the wake lock, killing it releases the lock
+var wakeCmd *exec.Cmd
+
+func acquireDisplayWake() error {
+    cmd := wakeCommand()
+    if err := cmd.Start(); err != nil {
+        return err
+    }
+    wakeCmd = cmd
+    return nil
+}
+
+func releaseDisplayWake() error {
+    if wakeCmd == nil {
+        return nil
+    }
+    err := wakeCmd.Process.Kill()
+    wakeCmd.Wait()
+    wakeCmd = nil
+    return err
+}
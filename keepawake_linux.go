@@ -0,0 +1,13 @@
+package main
+
+import (
+    "os/exec"
+    "syscall"
+)
+
+// Pdeathsig stops the inhibitor if the app dies without releasing
+func wakeCommand() *exec.Cmd {
+    cmd := exec.Command("systemd-inhibit", "--what=idle", "--who=HEX Stats", "--why=Watching live data", "sleep", "infinity")
+    cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGTERM}
+    return cmd
+}
@@ -0,0 +1,11 @@
+//go:build !darwin && !linux && !windows
+
+package main
+
+func acquireDisplayWake() error {
+    return nil
+}
+
+func releaseDisplayWake() error {
+    return nil
+}
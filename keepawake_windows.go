@@ -0,0 +1,47 @@
+package main
+
+import (
+    "runtime"
+    "syscall"
+)
+
+var setThreadExecutionState = syscall.NewLazyDLL("kernel32.dll").NewProc("SetThreadExecutionState")
+
+const (
+    esSystemRequired  = 0x00000001
+    esDisplayRequired = 0x00000002
+    esContinuous      = 0x80000000
+)
+
+// The execution state belongs to a thread, so it is held by a goroutine locked to one
+var wakeRelease chan struct{}
+
+func acquireDisplayWake() error {
+    result := make(chan error)
+    release := make(chan struct{})
+    go func() {
+        runtime.LockOSThread()
+        defer runtime.UnlockOSThread()
+        r, _, err := setThreadExecutionState.Call(esContinuous | esDisplayRequired | esSystemRequired)
+        if r == 0 {
+            result <- err
+            return
+        }
+        result <- nil
+        <-release
+        setThreadExecutionState.Call(esContinuous)
+    }()
+    if err := <-result; err != nil {
+        return err
+    }
+    wakeRelease = release
+    return nil
+}
+
+func releaseDisplayWake() error {
+    if wakeRelease != nil {
+        close(wakeRelease)
+        wakeRelease = nil
+    }
+    return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+    "fmt"
+    "image/color"
+    "sort"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/theme"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Stake ladder: each stake drawn as a bar from StartDate to EndDate
+type ladderState int
+
+const (
+    ladderPending ladderState = iota // Starts in the future
+    ladderActive
+    ladderMatured
+)
+
+const ladderRowHeight = 22
+const ladderRowGap = 6
+
+// Bar position as fractions (0-1) of the timeline width
+type ladderBar struct {
+    Miner Miner
+    X     float32
+    Width float32
+    Row   int
+    State ladderState
+}
+
+type ladderTimeline struct {
+    Bars   []ladderBar
+    Rows   int
+    TodayX float32
+    From   time.Time
+    To     time.Time
+}
+
+// Lays out active stakes on a timeline, overlapping stakes are stacked on separate rows
+func computeLadder(miners []Miner, now time.Time) ladderTimeline {
+    type span struct {
+        miner      Miner
+        start, end time.Time
+    }
+    var spans []span
+    for _, miner := range miners {
+        if miner.Status == "completed" {
+            continue
+        }
+        start, err := time.Parse(dateLayout, miner.StartDate)
+        if err != nil {
+            continue
+        }
+        end, err := time.Parse(dateLayout, miner.EndDate)
+        if err != nil || end.Before(start) {
+            continue
+        }
+        spans = append(spans, span{miner, start, end})
+    }
+    today := calendarDay(now)
+    timeline := ladderTimeline{From: today, To: today}
+    if len(spans) == 0 {
+        return timeline
+    }
+    sort.SliceStable(spans, func(i, j int) bool {
+        return spans[i].start.Before(spans[j].start)
+    })
+    for _, sp := range spans {
+        if sp.start.Before(timeline.From) {
+            timeline.From = sp.start
+        }
+        if sp.end.After(timeline.To) {
+            timeline.To = sp.end
+        }
+    }
+    total := float32(timeline.To.Sub(timeline.From))
+    if total <= 0 {
+        total = 1
+    }
+    position := func(t time.Time) float32 {
+        return float32(t.Sub(timeline.From)) / total
+    }
+
+    var rowEnds []time.Time
+    for _, sp := range spans {
+        row := -1
+        for r, end := range rowEnds {
+            if end.Before(sp.start) {
+                row = r
+                break
+            }
+        }
+        if row == -1 {
+            row = len(rowEnds)
+            rowEnds = append(rowEnds, sp.end)
+        } else {
+            rowEnds[row] = sp.end
+        }
+
+        state := ladderActive
+        if today.Before(sp.start) {
+            state = ladderPending
+        } else if !today.Before(sp.end) {
+            state = ladderMatured
+        }
+        timeline.Bars = append(timeline.Bars, ladderBar{
+            Miner: sp.miner,
+            X:     position(sp.start),
+            Width: position(sp.end) - position(sp.start),
+            Row:   row,
+            State: state,
+        })
+    }
+    timeline.Rows = len(rowEnds)
+    timeline.TodayX = position(today)
+    return timeline
+}
+
+func ladderColor(state ladderState) color.Color {
+    switch state {
+    case ladderPending:
+        return theme.Color(theme.ColorNameDisabled)
+    case ladderMatured:
+        return theme.Color(theme.ColorNameSuccess)
+    default:
+        return theme.Color(theme.ColorNamePrimary)
+    }
+}
+
+// Positions bars according to their fractional coordinates on every resize
+type ladderLayout struct {
+    timeline ladderTimeline
+}
+
+func (l *ladderLayout) Layout(objects []fyne.CanvasObject, size fyne.Size) {
+    for i, bar := range l.timeline.Bars {
+        objects[i].Move(fyne.NewPos(bar.X*size.Width, float32(bar.Row*(ladderRowHeight+ladderRowGap))))
+        objects[i].Resize(fyne.NewSize(fyne.Max(bar.Width*size.Width, 2), ladderRowHeight))
+    }
+    todayLine := objects[len(l.timeline.Bars)].(*canvas.Line)
+    todayLine.Position1 = fyne.NewPos(l.timeline.TodayX*size.Width, 0)
+    todayLine.Position2 = fyne.NewPos(l.timeline.TodayX*size.Width, size.Height)
+}
+
+func (l *ladderLayout) MinSize(_ []fyne.CanvasObject) fyne.Size {
+    return fyne.NewSize(400, float32(l.timeline.Rows*(ladderRowHeight+ladderRowGap)))
+}
+
+func showLadderWindow(miners []Miner) {
+    ladderWindow := fyne.CurrentApp().NewWindow("Stake Ladder")
+    ladderWindow.Resize(fyne.NewSize(800, 400))
+
+    timeline := computeLadder(miners, time.Now())
+    if len(timeline.Bars) == 0 {
+        ladderWindow.SetContent(widget.NewLabel("No active miners."))
+        ladderWindow.Show()
+        return
+    }
+
+    var objects []fyne.CanvasObject
+    for _, bar := range timeline.Bars {
+        rect := canvas.NewRectangle(ladderColor(bar.State))
+        rect.CornerRadius = 4
+        label := canvas.NewText(fmt.Sprintf(" %s", formatTShares(bar.Miner.TShares)), theme.Color(theme.ColorNameForeground))
+        label.TextSize = theme.CaptionTextSize()
+        objects = append(objects, container.NewStack(rect, label))
+    }
+    todayLine := canvas.NewLine(theme.Color(theme.ColorNameError))
+    todayLine.StrokeWidth = 2
+    objects = append(objects, todayLine)
+
+    chartArea := container.New(&ladderLayout{timeline: timeline}, objects...)
+
+    legend := container.NewHBox(
+        ladderLegendItem("Pending", ladderPending),
+        ladderLegendItem("Active", ladderActive),
+        ladderLegendItem("Matured", ladderMatured),
+        widget.NewLabel("| Red line: today"),
+    )
+    axis := container.NewBorder(nil, nil,
+        widget.NewLabel(timeline.From.Format(dateLayout)),
+        widget.NewLabel(timeline.To.Format(dateLayout)),
+    )
+
+    ladderWindow.SetContent(container.NewBorder(legend, axis, nil, nil, container.NewVScroll(container.NewPadded(chartArea))))
+    ladderWindow.Show()
+}
+
+func ladderLegendItem(name string, state ladderState) fyne.CanvasObject {
+    swatch := canvas.NewRectangle(ladderColor(state))
+    swatch.SetMinSize(fyne.NewSize(14, 14))
+    return container.NewHBox(container.NewCenter(swatch), widget.NewLabel(name))
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "math"
+    "testing"
+)
+
+func TestComputeLadder(t *testing.T) {
+    now := testDay(t, "01-02-2025")
+    miners := []Miner{
+        {ID: "b", StartDate: "11-01-2025", EndDate: "11-04-2025"},
+        {ID: "a", StartDate: "01-01-2025", EndDate: "21-01-2025"},
+        {ID: "c", StartDate: "31-01-2025", EndDate: "20-03-2025"},
+        {ID: "d", StartDate: "10-02-2025", EndDate: "20-02-2025"},
+        {ID: "done", StartDate: "01-01-2020", EndDate: "01-01-2021", Status: "completed"},
+        {ID: "bad", StartDate: "2025-01-01", EndDate: "21-01-2025"},
+    }
+    timeline := computeLadder(miners, now)
+
+    // From 01-01-2025 to 11-04-2025 is 100 days, so a day is 0.01 of the width
+    want := []struct {
+        id       string
+        x, width float32
+        row      int
+        state    ladderState
+    }{
+        {"a", 0, 0.2, 0, ladderMatured},
+        {"b", 0.1, 0.9, 1, ladderActive},
+        {"c", 0.3, 0.48, 0, ladderActive},
+        {"d", 0.4, 0.1, 2, ladderPending},
+    }
+    if len(timeline.Bars) != len(want) {
+        t.Fatalf("got %d bars, want %d", len(timeline.Bars), len(want))
+    }
+    for i, w := range want {
+        bar := timeline.Bars[i]
+        if bar.Miner.ID != w.id {
+            t.Errorf("bar %d is miner %s, want %s", i, bar.Miner.ID, w.id)
+            continue
+        }
+        if !near(bar.X, w.x) || !near(bar.Width, w.width) {
+            t.Errorf("bar %s at %v width %v, want %v width %v", w.id, bar.X, bar.Width, w.x, w.width)
+        }
+        if bar.Row != w.row {
+            t.Errorf("bar %s on row %d, want %d", w.id, bar.Row, w.row)
+        }
+        if bar.State != w.state {
+            t.Errorf("bar %s state %d, want %d", w.id, bar.State, w.state)
+        }
+    }
+    if timeline.Rows != 3 {
+        t.Errorf("Rows = %d, want 3", timeline.Rows)
+    }
+    if !near(timeline.TodayX, 0.31) {
+        t.Errorf("TodayX = %v, want 0.31", timeline.TodayX)
+    }
+}
+
+func TestComputeLadderEmpty(t *testing.T) {
+    timeline := computeLadder([]Miner{{Status: "completed", StartDate: "01-01-2020", EndDate: "01-01-2021"}}, testDay(t, "01-02-2025"))
+    if len(timeline.Bars) != 0 || timeline.Rows != 0 {
+        t.Errorf("timeline = %+v, want no bars", timeline)
+    }
+}
+
+func near(got, want float32) bool {
+    return math.Abs(float64(got-want)) < 1e-4
+}
@@ -3,14 +3,23 @@ package main
 import (
     "bytes"
     "context"
+    "crypto/rand"
+    "encoding/hex"
     "encoding/json"
+    "errors"
     "fmt"
     "log"
-    "net/http"
+    mathrand "math/rand"
+    "net/url"
     "os"
+    "regexp"
+    "runtime"
     "strconv"
+    "strings"
     "sync"
+    "sync/atomic"
     "time"
+    "unicode"
      _ "embed"
 
     "fyne.io/fyne/v2"
@@ -18,9 +27,11 @@ import (
     "fyne.io/fyne/v2/canvas"
     "fyne.io/fyne/v2/container"
     "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/driver/desktop"
+    "fyne.io/fyne/v2/layout"
+    "fyne.io/fyne/v2/storage"
+    "fyne.io/fyne/v2/theme"
     "fyne.io/fyne/v2/widget"
-
-    "github.com/wcharczuk/go-chart"
 )
 
 //go:embed icon.png
@@ -29,9 +40,27 @@ var appIcon []byte
 // Global variables for cached live data
 var (
     latestLiveData LiveData
+    priceSamples   []priceSample // Recent T-Share prices for the trend
     liveDataMutex  sync.Mutex
 )
 
+// T-Share price at the time of a fetch
+type priceSample struct {
+    At    time.Time
+    Price float64
+}
+
+const priceTrendWindow = time.Hour
+const priceTrendFlat = 0.001 // Relative change below this counts as flat
+
+// Serializes reads and writes of the stored miners
+var minersMutex sync.RWMutex
+
+// Held from loading the stored miners to saving the changed ones, so a save in another
+// goroutine (a watcher, a portfolio switch) can't land in between and be lost. loadMiners
+// and saveMiners don't take it.
+var minersTxMutex sync.Mutex
+
 // ConfigManager for thread-safe configuration
 type ConfigManager struct {
     mu          sync.RWMutex
@@ -40,7 +69,24 @@ type ConfigManager struct {
 }
 
 var configManager = &ConfigManager{
-    config: Config{LiveDataFrequency: defaultLiveDataFrequency},
+    config: defaultConfig(),
+}
+
+func (cm *ConfigManager) GetConfig() Config {
+    cm.mu.RLock()
+    defer cm.mu.RUnlock()
+    return cm.config
+}
+
+func (cm *ConfigManager) SetConfig(config Config) {
+    cm.mu.Lock()
+    defer cm.mu.Unlock()
+    frequencyChanged := cm.config.LiveDataFrequency != config.LiveDataFrequency
+    cm.config = config
+    if frequencyChanged {
+        log.Println("Set LiveDataFrequency to", config.LiveDataFrequency)
+        cm.notifySubscribers()
+    }
 }
 
 func (cm *ConfigManager) GetLiveDataFrequency() int {
@@ -54,7 +100,11 @@ func (cm *ConfigManager) SetLiveDataFrequency(frequency int) {
     defer cm.mu.Unlock()
     cm.config.LiveDataFrequency = frequency
     log.Println("Set LiveDataFrequency to", frequency)
-    // Notify all subscribers
+    cm.notifySubscribers()
+}
+
+// Notify all subscribers, caller must hold cm.mu
+func (cm *ConfigManager) notifySubscribers() {
     for i, ch := range cm.changeChans {
         select {
         case ch <- struct{}{}:
@@ -65,15 +115,205 @@ func (cm *ConfigManager) SetLiveDataFrequency(frequency int) {
     }
 }
 
+// The channel starts with one pending signal, so a subscriber that read the frequency before
+// subscribing re-reads it and can't miss a change made in between
 func (cm *ConfigManager) Subscribe() chan struct{} {
     cm.mu.Lock()
     defer cm.mu.Unlock()
     ch := make(chan struct{}, 1) // Buffered to avoid blocking
+    ch <- struct{}{}
     cm.changeChans = append(cm.changeChans, ch)
     // log.Println("New subscriber added, total subscribers:", len(cm.changeChans))
     return ch
 }
 
+// Stops the signals to ch, for subscribers that go away before the app does
+func (cm *ConfigManager) Unsubscribe(ch chan struct{}) {
+    cm.mu.Lock()
+    defer cm.mu.Unlock()
+    cm.changeChans = withoutChan(cm.changeChans, ch)
+}
+
+// Broadcasts events (e.g. history sync completion) to subscribers without blocking
+type notifier struct {
+    mu    sync.Mutex
+    chans []chan struct{}
+}
+
+var historySynced = &notifier{}
+
+// Notified whenever new live data has been stored
+var liveDataUpdated = &notifier{}
+
+// Notified after the tabs were rebuilt, for the Charts tab which is only built once
+var tabsRebuilt = &notifier{}
+
+// Tracks window focus so background fetching can pause while the app is idle
+type fetchPauser struct {
+    mu        sync.Mutex
+    focused   bool
+    blurredAt time.Time
+    resumeCh  chan struct{} // Signalled when focus returns after a pause
+}
+
+var fetchPause = &fetchPauser{focused: true, resumeCh: make(chan struct{}, 1)}
+
+func (p *fetchPauser) Blur(now time.Time) {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    if p.focused {
+        p.focused = false
+        p.blurredAt = now
+    }
+}
+
+// Marks the window focused, reports whether fetching had been paused
+func (p *fetchPauser) Focus(now time.Time, enabled bool, after time.Duration) bool {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    resumed := p.pausedLocked(now, enabled, after)
+    p.focused = true
+    if resumed {
+        select {
+        case p.resumeCh <- struct{}{}:
+        default:
+        }
+    }
+    return resumed
+}
+
+func (p *fetchPauser) Paused(now time.Time, enabled bool, after time.Duration) bool {
+    p.mu.Lock()
+    defer p.mu.Unlock()
+    return p.pausedLocked(now, enabled, after)
+}
+
+func (p *fetchPauser) pausedLocked(now time.Time, enabled bool, after time.Duration) bool {
+    return enabled && !p.focused && now.Sub(p.blurredAt) >= after
+}
+
+// Spreads base by up to +/- percent, r is a random value in [0, 1)
+func jitteredInterval(base time.Duration, percent int, r float64) time.Duration {
+    spread := float64(base) * float64(percent) / 100
+    return base + time.Duration((2*r-1)*spread)
+}
+
+// Live data fetch interval with jitter so clients don't all poll at the same moment
+func liveFetchInterval() time.Duration {
+    config := configManager.GetConfig()
+    return jitteredInterval(time.Duration(config.LiveDataFrequency)*time.Minute, config.PollJitterPercent, mathrand.Float64())
+}
+
+// Shown in place of values while no live data has been fetched yet
+const calculatingText = "calculating…"
+
+// Whether live data has been fetched for any chain, before the first fetch everything is zero
+func hasLiveData(data LiveData) bool {
+    for _, chain := range chains {
+        if data.Chain(chain).TsharePrice > 0 {
+            return true
+        }
+    }
+    return false
+}
+
+// Stores fetched live data and records its price, samples older than the trend window are dropped
+func setLiveData(data LiveData, now time.Time) {
+    liveDataMutex.Lock()
+    latestLiveData = data
+    captureSessionBaseline(data)
+    priceSamples = append(priceSamples, priceSample{At: now, Price: data.TsharePricePulsechain})
+    for len(priceSamples) > 0 && now.Sub(priceSamples[0].At) > priceTrendWindow {
+        priceSamples = priceSamples[1:]
+    }
+    liveDataMutex.Unlock()
+    liveDataUpdated.Notify()
+}
+
+// Direction of the price over samples (oldest first): 1 rising, -1 falling, 0 flat.
+// ok is false until there are two samples to compare
+func priceTrend(samples []priceSample) (int, bool) {
+    if len(samples) < 2 || samples[0].Price <= 0 {
+        return 0, false
+    }
+    change := (samples[len(samples)-1].Price - samples[0].Price) / samples[0].Price
+    switch {
+    case change > priceTrendFlat:
+        return 1, true
+    case change < -priceTrendFlat:
+        return -1, true
+    default:
+        return 0, true
+    }
+}
+
+func priceTrendArrow(direction int) string {
+    switch direction {
+    case 1:
+        return "▲"
+    case -1:
+        return "▼"
+    default:
+        return "▶"
+    }
+}
+
+// Whether the machine slept between two checks expected period apart. Wall clock
+// readings are compared because the monotonic clock doesn't advance during sleep
+func sleptBetween(last, now time.Time, period time.Duration) bool {
+    return now.Round(0).Sub(last.Round(0)) > 2*period
+}
+
+// Lets the window settle before the first fetch on slow machines, after is time.After
+// outside tests
+func waitStartupDelay(after func(time.Duration) <-chan time.Time) {
+    if delay := time.Duration(configManager.GetConfig().StartupDelaySeconds) * time.Second; delay > 0 {
+        log.Println("Delaying first live data fetch by", delay)
+        <-after(delay)
+    }
+}
+
+// Never paused while running in the tray, the hidden window would always count as inactive
+// and leave the tray prices stale
+func pauseSettings() (bool, time.Duration) {
+    config := configManager.GetConfig()
+    return config.PauseWhenInactive && !runningInTray.Load(), time.Duration(config.PauseAfterMinutes) * time.Minute
+}
+
+func (n *notifier) Subscribe() chan struct{} {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    ch := make(chan struct{}, 1) // Buffered so a pending signal is never lost
+    n.chans = append(n.chans, ch)
+    return ch
+}
+
+func (n *notifier) Unsubscribe(ch chan struct{}) {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    n.chans = withoutChan(n.chans, ch)
+}
+
+func withoutChan(chans []chan struct{}, ch chan struct{}) []chan struct{} {
+    for i, c := range chans {
+        if c == ch {
+            return append(chans[:i:i], chans[i+1:]...)
+        }
+    }
+    return chans
+}
+
+func (n *notifier) Notify() {
+    n.mu.Lock()
+    defer n.mu.Unlock()
+    for _, ch := range n.chans {
+        select {
+        case ch <- struct{}{}:
+        default:
+        }
+    }
+}
+
 
 // Data Structures
 type HEXJSONEntry struct {
@@ -81,6 +321,15 @@ type HEXJSONEntry struct {
     TshareRateHEX  float64 `json:"tshareRateHEX"`
     DailyPayoutHEX float64 `json:"dailyPayoutHEX"`
     PricePulseX    float64 `json:"pricePulseX"`
+    PriceUV2UV3    float64 `json:"priceUV2UV3"` // Ethereum history, priced on Uniswap instead of PulseX
+}
+
+// Price of the day on the chain the history is from
+func (e HEXJSONEntry) Price() float64 {
+    if e.PricePulseX == 0 {
+        return e.PriceUV2UV3
+    }
+    return e.PricePulseX
 }
 
 type HEXJSON []HEXJSONEntry
@@ -91,22 +340,205 @@ type LiveData struct {
     TshareRateHEXPulsechain   float64 `json:"tshareRateHEX_Pulsechain"`
     PenaltiesHEXPulsechain    float64 `json:"penaltiesHEX_Pulsechain"`
     PayoutPerTsharePulsechain float64 `json:"payoutPerTshare_Pulsechain"`
+    PriceEthereum             float64 `json:"price"`
+    TsharePriceEthereum       float64 `json:"tsharePrice"`
+    TshareRateHEXEthereum     float64 `json:"tshareRateHEX"`
+    PenaltiesHEXEthereum      float64 `json:"penaltiesHEX"`
+    PayoutPerTshareEthereum   float64 `json:"payoutPerTshare"`
     Beat                      int64   `json:"beat"`
 }
 
 type Miner struct {
-    StartDate string  `json:"startDate"`
-    EndDate   string  `json:"endDate"`
-    TShares   float64 `json:"tShares"`
-    Status    string  `json:"status,omitempty"`
+    ID            string   `json:"id,omitempty"`
+    StartDate     string   `json:"startDate"`
+    EndDate       string   `json:"endDate"`
+    TShares       float64  `json:"tShares"`
+    Status        string   `json:"status,omitempty"`
+    Notified      bool     `json:"notified,omitempty"`      // Maturity already announced to the user, by a prompt or a notification
+    GraceNotified bool     `json:"graceNotified,omitempty"` // Grace period reminder sent
+    Tags          []string `json:"tags,omitempty"`
+    PrincipalHEX  float64  `json:"principalHEX,omitempty"`  // HEX staked, 0 for miners added before it was tracked
+    RealizedHEX   float64  `json:"realizedHEX,omitempty"`   // Estimated yield recorded when ended, 0 for miners ended before it was
+    Chain         string   `json:"chain,omitempty"`         // Empty for PulseChain, see minerChain
+    StakeID       uint64   `json:"stakeId,omitempty"`       // HEX contract stake ID of miners imported from a wallet
 }
 
 type Config struct {
-    LiveDataFrequency int `json:"liveDataFrequency"`
+    LiveDataFrequency        int       `json:"liveDataFrequency"`
+    AutoEndPrompt            bool      `json:"autoEndPrompt"`
+    AutoEndMatured           bool      `json:"autoEndMatured"`            // Mark matured stakes as ended without asking
+    MaturityNotifications    bool      `json:"maturityNotifications"`     // Notify when stakes mature and before their grace period ends
+    TrayAlerts               bool      `json:"trayAlerts"`                // Matured stakes in the system tray menu, applied at startup
+    TrayMode                 bool      `json:"trayMode"`                  // Tray menu with prices, closing the window keeps the app running, applied at startup
+    TSharesDecimals          int       `json:"tSharesDecimals"`
+    PayoutDecimals           int       `json:"payoutDecimals"`
+    ShowLifetimeTShares      bool      `json:"showLifetimeTShares"`
+    ShowLifetimeYield        bool      `json:"showLifetimeYield"`
+    LastChartField           string    `json:"lastChartField"`
+    ChartRange               string    `json:"chartRange"`                // Days the Charts tab opens with, see chartRanges
+    PenaltyWarnThreshold     float64   `json:"penaltyWarnThreshold"`      // 0 disables the warning color
+    PauseWhenInactive        bool      `json:"pauseWhenInactive"`
+    PauseAfterMinutes        int       `json:"pauseAfterMinutes"`
+    StartupDelaySeconds      int       `json:"startupDelaySeconds"`       // Wait before the first live data fetch, 0 fetches at once
+    HistorySyncEveryNFetches int       `json:"historySyncEveryNFetches"`  // Also sync history every Nth live data fetch, 0 only at startup
+    Currency                 string    `json:"currency"`
+    Chain                    string    `json:"chain"`                     // Chain whose live data and history the Live Data, Dashboard and Charts tabs show
+    MoneyDecimals            int       `json:"moneyDecimals"`             // Decimals of money values, -1 follows the currency
+    PrivacyMode              bool      `json:"privacyMode"`
+    ChartLineStyle           string    `json:"chartLineStyle"`            // solid, dashed or dotted
+    ChartShowMarkers         bool      `json:"chartShowMarkers"`
+    ChartShowToday           bool      `json:"chartShowToday"`
+    ChartWidth               int       `json:"chartWidth"`                // Rendered size in pixels, the shown image scales with the window
+    ChartHeight              int       `json:"chartHeight"`
+    ChartShowSource          bool      `json:"chartShowSource"`           // Caption with the newest day and the last sync
+    PollJitterPercent        int       `json:"pollJitterPercent"`         // Random +/- spread applied to the fetch interval
+    SharedPortfolioURL       string    `json:"sharedPortfolioURL"`
+    WalletAddress            string    `json:"walletAddress"`             // Last address stakes were imported from
+    RPCURL                   string    `json:"rpcURL"`                    // PulseChain JSON-RPC endpoint for wallet imports
+    DefaultStakeDays         int       `json:"defaultStakeDays"`          // End date filled in from the start date, 0 = off
+    KeepScreenOn             bool      `json:"keepScreenOn"`
+    UpdateCheck              bool      `json:"updateCheck"`
+    UpdateCheckURL           string    `json:"updateCheckURL"`
+    OfflineMode              bool      `json:"offlineMode"`               // No network requests, the stored history and miners are still shown
+    DurationFormat           string    `json:"durationFormat"`            // days, weeks-days or months-days
+    RefreshOnResume          bool      `json:"refreshOnResume"`
+    MaxConcurrentRequests    int       `json:"maxConcurrentRequests"`
+    FetchHistorySize         int       `json:"fetchHistorySize"`          // Fetch outcomes kept for the Connection window
+    LiveDataStream           bool      `json:"liveDataStream"`
+    LiveDataStreamURL        string    `json:"liveDataStreamURL"`         // Server-Sent Events endpoint sending LiveData JSON
+    MinerJournal             bool      `json:"minerJournal"`              // Journal miner changes to survive crashes mid-save
+    ActivePortfolio          string    `json:"activePortfolio"`           // Named portfolio, empty for the default one
+    CompactNumbers           bool      `json:"compactNumbers"`            // 1.23B HEX instead of 1,234,567,890 HEX
+    CompactDecimals          int       `json:"compactDecimals"`
+    StrictDecode             bool      `json:"strictDecode"`              // Warn about unknown fields in API responses
+    HighlightChanges         bool      `json:"highlightChanges"`
+    InAppAlerts              bool      `json:"inAppAlerts"`               // Alerts as dialogs even where system notifications seem available
+    QuietMode                bool      `json:"quietMode"`                 // Alerts held back
+    QuietUntil               time.Time `json:"quietUntil"`                // End of quiet mode, zero until turned off
+    QuietAlerts              []string  `json:"quietAlerts,omitempty"`     // Alerts held back for the summary
+    ShowNetValue             bool      `json:"showNetValue"`              // Matured stake values net of estimated late penalties
+    IncludeCompletedInTotals bool      `json:"includeCompletedInTotals"`
+    IncludeArchivedInTotals  bool      `json:"includeArchivedInTotals"`
+    ArchiveAfterDays         int       `json:"archiveAfterDays"`          // Archive completed miners ended this many days ago, 0 = off
+    PinnedTsharePrice        float64   `json:"pinnedTsharePrice"`         // USD, 0 values at the live price
+    ShowDashboard            bool      `json:"showDashboard"`
+    ShowSinceLaunch          bool      `json:"showSinceLaunch"`
+    RememberLastTab          bool      `json:"rememberLastTab"`
+    LastTab                  string    `json:"lastTab"`                   // Title of the tab selected last
+    TitleMetric              string    `json:"titleMetric"`               // none, price, value or next-maturity
+    AddMinerShortcut         string    `json:"addMinerShortcut"`          // Ctrl+N, Ctrl+Shift+N or Off
+    CompactMinerList         bool      `json:"compactMinerList"`          // Existing Miners in Settings as a dense table
+    ValueAlertAbove          float64   `json:"valueAlertAbove"`           // Display currency, 0 disables
+    ValueAlertBelow          float64   `json:"valueAlertBelow"`           // Display currency, 0 disables
+    ValueAlertState          string    `json:"valueAlertState,omitempty"` // Threshold last alerted, until the value moves back
+    APIBaseURL               string    `json:"apiBaseURL"`
+    FallbackAPIURLs          []string  `json:"fallbackAPIURLs"`           // Tried in order when the API base URL fails
 }
 
 const dateLayout = "02-01-2006" // DD-MM-YYYY for storage and display
 const defaultLiveDataFrequency = 15 // Default frequency in minutes
+const maturityCheckInterval = time.Hour
+const defaultTSharesDecimals = 2
+const maxTSharesDecimals = 6
+const defaultPayoutDecimals = 1
+const maxPayoutDecimals = 6
+const defaultChartField = "pricePulseX"
+
+const (
+    defaultChartWidth  = 1024
+    defaultChartHeight = 400
+    minChartDimension  = 200
+    maxChartDimension  = 4000
+)
+const defaultPauseAfterMinutes = 10
+
+const maxStartupDelaySeconds = 300
+const privacyMask = "••••"
+const defaultPollJitterPercent = 10
+const maxPollJitterPercent = 50
+const maxStakeDays = 5555 // Longest HEX stake
+const resumeCheckInterval = 30 * time.Second
+const defaultCompactDecimals = 2
+const defaultAPIBaseURL = "https://hexdailystats.com"
+const maxCompactDecimals = 4
+
+var chartFields = []string{"pricePulseX", "tshareRateHEX", "dailyPayoutHEX"}
+
+// Days the Charts tab can show, the newest ones of the history
+var chartRanges = []string{"7d", "30d", "90d", "1y", "all"}
+var durationFormats = []string{"days", "weeks-days", "months-days"}
+
+var chartLineStyles = []string{"solid", "dashed", "dotted"}
+
+// Default values for every config field
+func defaultConfig() Config {
+    return Config{
+        LiveDataFrequency:        defaultLiveDataFrequency,
+        AutoEndPrompt:            false,
+        MaturityNotifications:    true,
+        TrayAlerts:               false,
+        TrayMode:                 false,
+        AutoEndMatured:           false,
+        TSharesDecimals:          defaultTSharesDecimals,
+        PayoutDecimals:           defaultPayoutDecimals,
+        ShowLifetimeTShares:      false,
+        ShowLifetimeYield:        false,
+        LastChartField:           defaultChartField,
+        ChartRange:               "all",
+        PenaltyWarnThreshold:     0,
+        PauseWhenInactive:        false,
+        PauseAfterMinutes:        defaultPauseAfterMinutes,
+        StartupDelaySeconds:      0,
+        HistorySyncEveryNFetches: 0,
+        Currency:                 "USD",
+        Chain:                    chainPulsechain,
+        MoneyDecimals:            -1,
+        PrivacyMode:              false,
+        ChartLineStyle:           "solid",
+        ChartShowMarkers:         false,
+        ChartShowToday:           true,
+        ChartWidth:               defaultChartWidth,
+        ChartHeight:              defaultChartHeight,
+        ChartShowSource:          true,
+        PollJitterPercent:        defaultPollJitterPercent,
+        SharedPortfolioURL:       "",
+        DefaultStakeDays:         maxStakeDays,
+        KeepScreenOn:             false,
+        UpdateCheck:              false,
+        UpdateCheckURL:           defaultUpdateCheckURL,
+        OfflineMode:              false,
+        DurationFormat:           "days",
+        RefreshOnResume:          true,
+        MaxConcurrentRequests:    defaultMaxConcurrentRequests,
+        FetchHistorySize:         defaultFetchHistorySize,
+        LiveDataStream:           false,
+        LiveDataStreamURL:        "",
+        MinerJournal:             false,
+        ActivePortfolio:          "",
+        CompactNumbers:           false,
+        CompactDecimals:          defaultCompactDecimals,
+        StrictDecode:             false,
+        HighlightChanges:         false,
+        InAppAlerts:              false,
+        QuietMode:                false,
+        ShowNetValue:             false,
+        IncludeCompletedInTotals: false,
+        IncludeArchivedInTotals:  false,
+        ArchiveAfterDays:         0,
+        PinnedTsharePrice:        0,
+        ShowDashboard:            false,
+        ShowSinceLaunch:          false,
+        RememberLastTab:          true,
+        LastTab:                  "",
+        TitleMetric:              "none",
+        AddMinerShortcut:         "Ctrl+N",
+        CompactMinerList:         false,
+        ValueAlertAbove:          0,
+        ValueAlertBelow:          0,
+        APIBaseURL:               defaultAPIBaseURL,
+        RPCURL:                   defaultRPCURL,
+    }
+}
 
 // Custom CanvasObject for triggering updates
 type updateTrigger struct {
@@ -151,14 +583,34 @@ func (t *updateTrigger) Tapped(e *fyne.PointEvent) {
 func (t *updateTrigger) TappedSecondary(_ *fyne.PointEvent) {}
 
 // Data Fetching and Management Functions
-func fetchHEXJSON() (HEXJSON, error) {
-    resp, err := http.Get("https://hexdailystats.com/fulldatapulsechain")
+
+// Checks an API base URL entered in Settings and returns it without a trailing slash.
+// Only https is accepted since the numbers shown come from it.
+func validateAPIBaseURL(raw string) (string, error) {
+    raw = strings.TrimRight(strings.TrimSpace(raw), "/")
+    parsed, err := url.Parse(raw)
     if err != nil {
-        return HEXJSON{}, err
+        return "", fmt.Errorf("not a valid URL")
+    }
+    if parsed.Scheme != "https" {
+        return "", fmt.Errorf("URL must start with https://")
+    }
+    if parsed.Host == "" || parsed.User != nil || parsed.RawQuery != "" || parsed.Fragment != "" {
+        return "", fmt.Errorf("URL must be a plain https://host address")
     }
-    defer resp.Body.Close()
+    return raw, nil
+}
+
+func fetchHEXJSON(chain string) (HEXJSON, error) {
     var data HEXJSON
-    err = json.NewDecoder(resp.Body).Decode(&data)
+    err := fetchWithFailover(historyEndpoint(chain), func(body []byte) error {
+        data = HEXJSON{}
+        if err := json.Unmarshal(body, &data); err != nil {
+            return err
+        }
+        reportSchemaDrift("history", body, &HEXJSON{})
+        return nil
+    })
     if err != nil {
         return HEXJSON{}, err
     }
@@ -166,58 +618,45 @@ func fetchHEXJSON() (HEXJSON, error) {
 }
 
 func fetchLiveData() (LiveData, error) {
-    resp, err := http.Get("https://hexdailystats.com/livedata")
-    if err != nil {
-        return LiveData{}, err
-    }
-    defer resp.Body.Close()
     var data LiveData
-    err = json.NewDecoder(resp.Body).Decode(&data)
+    err := fetchWithFailover("/livedata", func(body []byte) error {
+        data = LiveData{}
+        if err := json.Unmarshal(body, &data); err != nil {
+            return err
+        }
+        reportSchemaDrift("live data", body, &LiveData{})
+        return nil
+    })
     if err != nil {
         return LiveData{}, err
     }
     return data, nil
 }
 
-func loadLocalHEXJSON() (HEXJSON, error) {
-    file, err := os.Open("data/hexjson.json")
-    if err != nil {
-        if os.IsNotExist(err) {
-            return HEXJSON{}, nil
-        }
-        return HEXJSON{}, err
-    }
-    defer file.Close()
-    var data HEXJSON
-    err = json.NewDecoder(file).Decode(&data)
-    if err != nil {
-        return HEXJSON{}, err
-    }
-    return data, nil
+func loadLocalHEXJSON(chain string) (HEXJSON, error) {
+    return store.ReadHistory(chain)
 }
 
-func saveLocalHEXJSON(data HEXJSON) error {
-    file, err := os.Create("data/hexjson.json")
+func updateLocalHEXJSON(chain string) error {
+    localData, err := loadLocalHEXJSON(chain)
     if err != nil {
         return err
     }
-    defer file.Close()
-    encoder := json.NewEncoder(file)
-    encoder.SetIndent("", "  ")
-    return encoder.Encode(data)
-}
-
-func updateLocalHEXJSON() error {
-    localData, err := loadLocalHEXJSON()
+    remoteData, err := fetchHEXJSON(chain)
     if err != nil {
         return err
     }
-    remoteData, err := fetchHEXJSON()
-    if err != nil {
-        return err
+    // An API hiccup can answer with no entries, that must not end up as the saved history.
+    // With a local history there are just no new days.
+    if len(remoteData) == 0 {
+        if len(localData) > 0 {
+            log.Printf("Warning: API returned no %s history, keeping the local history", chainName(chain))
+            return nil
+        }
+        return errEmptyHistory
     }
     if len(localData) == 0 {
-        return saveLocalHEXJSON(remoteData)
+        return store.AddHistory(chain, remoteData)
     }
     localMaxDay := localData[0].CurrentDay // Newest first
     var newEntries []HEXJSONEntry
@@ -229,154 +668,997 @@ func updateLocalHEXJSON() error {
         }
     }
     if len(newEntries) > 0 {
-        updatedData := append(newEntries, localData...)
-        return saveLocalHEXJSON(updatedData)
+        return store.AddHistory(chain, newEntries)
     }
     return nil
 }
 
+var errEmptyHistory = errors.New("API returned no history")
+
+var (
+    historySyncRunning atomic.Bool
+    // Last sync of a chain got no entries and none are saved, shown by the chart placeholder
+    historyEmpty = map[string]*atomic.Bool{chainPulsechain: {}, chainEthereum: {}}
+    // Kept out of Config, saving it on every sync would rewrite the file and restart the fetch ticker
+    lastHistorySync atomic.Pointer[time.Time]
+)
+
+// Syncs the history of both chains and notifies the charts, a sync still running isn't started twice
+func syncHistory() {
+    if configManager.GetConfig().OfflineMode {
+        return
+    }
+    if !historySyncRunning.CompareAndSwap(false, true) {
+        return
+    }
+    defer historySyncRunning.Store(false)
+    synced := false
+    for _, chain := range chains {
+        err := updateLocalHEXJSON(chain)
+        source := "history"
+        if chain != chainPulsechain {
+            source = chainName(chain) + " history"
+        }
+        recordFetch(source, err)
+        historyEmpty[chain].Store(errors.Is(err, errEmptyHistory))
+        if errors.Is(err, errEmptyHistory) {
+            log.Printf("Warning: API returned no %s history and there is none saved yet", chainName(chain))
+            continue
+        }
+        if err != nil {
+            log.Printf("Error updating local %s HEXJSON: %v", chainName(chain), err)
+            continue
+        }
+        synced = true
+    }
+    if !synced {
+        historySynced.Notify() // Lets the chart explain why it's empty
+        return
+    }
+    now := time.Now()
+    lastHistorySync.Store(&now)
+    historySynced.Notify()
+}
+
+// When the history was synced last in this session, zero before the first sync
+func lastHistorySyncTime() time.Time {
+    if synced := lastHistorySync.Load(); synced != nil {
+        return *synced
+    }
+    return time.Time{}
+}
+
+// Whether the fetchCount-th live data fetch also syncs history, every 0 syncs only at startup
+func historySyncDue(fetchCount, every int) bool {
+    return every > 0 && fetchCount > 0 && fetchCount%every == 0
+}
+
 func loadMiners() ([]Miner, error) {
-    file, err := os.Open("settings/miners.json")
+    minersMutex.RLock()
+    data, err := store.ReadMiners(configManager.GetConfig().ActivePortfolio)
+    minersMutex.RUnlock()
     if err != nil {
-        if os.IsNotExist(err) {
-            return []Miner{}, nil
-        }
         return nil, err
     }
-    defer file.Close()
+    if data == nil {
+        return []Miner{}, nil
+    }
     var miners []Miner
-    err = json.NewDecoder(file).Decode(&miners)
-    if err != nil {
+    if err := json.Unmarshal(data, &miners); err != nil {
         return nil, err
     }
+    // Older files have no IDs, persist them so they stay stable
+    if assignMinerIDs(miners) {
+        if err := saveMiners(miners); err != nil {
+            log.Println("Error saving miner IDs:", err)
+        }
+    }
     return miners, nil
 }
 
 func saveMiners(miners []Miner) error {
-    file, err := os.Create("settings/miners.json")
+    minersMutex.Lock()
+    defer minersMutex.Unlock()
+    data, err := json.MarshalIndent(miners, "", "  ")
     if err != nil {
         return err
     }
-    defer file.Close()
-    encoder := json.NewEncoder(file)
-    encoder.SetIndent("", "  ")
-    return encoder.Encode(miners)
+    if err := store.WriteMiners(configManager.GetConfig().ActivePortfolio, append(data, '\n')); err != nil {
+        return err
+    }
+    journalCheckpoint(miners)
+    return nil
 }
 
 func loadConfig() (Config, error) {
-    file, err := os.Open("settings/config.json")
+    data, err := store.ReadConfig()
     if err != nil {
-        if os.IsNotExist(err) {
-            return Config{LiveDataFrequency: defaultLiveDataFrequency}, nil
-        }
         return Config{}, err
     }
-    defer file.Close()
-    var config Config
-    err = json.NewDecoder(file).Decode(&config)
+    if data == nil {
+        return defaultConfig(), nil
+    }
+    // An empty file (e.g. left by a crash while saving) is treated like a missing one
+    if len(bytes.TrimSpace(data)) == 0 {
+        log.Println("Warning: the stored config is empty, using default settings")
+        return defaultConfig(), nil
+    }
+    config := defaultConfig() // Fields missing from older files keep their defaults
+    err = json.Unmarshal(data, &config)
     if err != nil {
         return Config{}, err
     }
     if config.LiveDataFrequency <= 0 {
         config.LiveDataFrequency = defaultLiveDataFrequency
     }
+    if config.TSharesDecimals < 0 || config.TSharesDecimals > maxTSharesDecimals {
+        config.TSharesDecimals = defaultTSharesDecimals
+    }
+    if config.PayoutDecimals < 0 || config.PayoutDecimals > maxPayoutDecimals {
+        config.PayoutDecimals = defaultPayoutDecimals
+    }
+    if !isChartField(config.LastChartField) {
+        config.LastChartField = defaultChartField
+    }
+    if !contains(chartRanges, config.ChartRange) {
+        config.ChartRange = "all"
+    }
+    if config.ChartWidth < minChartDimension || config.ChartWidth > maxChartDimension {
+        config.ChartWidth = defaultChartWidth
+    }
+    if config.ChartHeight < minChartDimension || config.ChartHeight > maxChartDimension {
+        config.ChartHeight = defaultChartHeight
+    }
+    if !contains(chartLineStyles, config.ChartLineStyle) {
+        config.ChartLineStyle = "solid"
+    }
+    if config.MoneyDecimals < -1 || config.MoneyDecimals > maxMoneyDecimals {
+        config.MoneyDecimals = -1
+    }
+    if config.PenaltyWarnThreshold < 0 {
+        config.PenaltyWarnThreshold = 0
+    }
+    if !portfolioExists(config.ActivePortfolio) {
+        // Removed or mistyped by hand, back to the default. The name is used as a path.
+        log.Println("Warning: ignoring active portfolio:", config.ActivePortfolio)
+        config.ActivePortfolio = ""
+    }
+    if config.ArchiveAfterDays < 0 || config.ArchiveAfterDays > maxArchiveAfterDays {
+        config.ArchiveAfterDays = 0
+    }
+    if config.PinnedTsharePrice < 0 {
+        config.PinnedTsharePrice = 0
+    }
+    if config.ValueAlertAbove < 0 {
+        config.ValueAlertAbove = 0
+    }
+    if config.ValueAlertBelow < 0 {
+        config.ValueAlertBelow = 0
+    }
+    if config.PollJitterPercent < 0 || config.PollJitterPercent > maxPollJitterPercent {
+        config.PollJitterPercent = defaultPollJitterPercent
+    }
+    if config.CompactDecimals < 0 || config.CompactDecimals > maxCompactDecimals {
+        config.CompactDecimals = defaultCompactDecimals
+    }
+    if config.MaxConcurrentRequests <= 0 {
+        config.MaxConcurrentRequests = defaultMaxConcurrentRequests
+    }
+    if config.FetchHistorySize <= 0 || config.FetchHistorySize > maxFetchHistorySize {
+        config.FetchHistorySize = defaultFetchHistorySize
+    }
+    if !contains(durationFormats, config.DurationFormat) {
+        config.DurationFormat = "days"
+    }
+    if !contains(addMinerShortcuts, config.AddMinerShortcut) {
+        config.AddMinerShortcut = "Ctrl+N"
+    }
+    if !contains(titleMetrics, config.TitleMetric) {
+        config.TitleMetric = "none"
+    }
+    // Hand edits that wouldn't pass the Settings check are dropped
+    if apiURL, err := validateAPIBaseURL(config.APIBaseURL); err == nil {
+        config.APIBaseURL = apiURL
+    } else {
+        if config.APIBaseURL != "" {
+            log.Println("Warning: ignoring API base URL:", err)
+        }
+        config.APIBaseURL = defaultAPIBaseURL
+    }
+    fallbacks, err := parseFallbackAPIURLs(strings.Join(config.FallbackAPIURLs, "\n"))
+    if err != nil {
+        log.Println("Warning: ignoring invalid fallback API URLs:", err)
+    }
+    config.FallbackAPIURLs = fallbacks
+    if config.RPCURL == "" {
+        config.RPCURL = defaultRPCURL
+    }
+    if config.UpdateCheckURL == "" {
+        config.UpdateCheckURL = defaultUpdateCheckURL
+    }
+    if config.DefaultStakeDays < 0 || config.DefaultStakeDays > maxStakeDays {
+        config.DefaultStakeDays = maxStakeDays
+    }
+    if config.PauseAfterMinutes <= 0 {
+        config.PauseAfterMinutes = defaultPauseAfterMinutes
+    }
+    if config.HistorySyncEveryNFetches < 0 {
+        config.HistorySyncEveryNFetches = 0
+    }
+    if config.StartupDelaySeconds < 0 {
+        config.StartupDelaySeconds = 0
+    }
+    if config.StartupDelaySeconds > maxStartupDelaySeconds {
+        config.StartupDelaySeconds = maxStartupDelaySeconds
+    }
+    if !isCurrency(config.Currency) {
+        config.Currency = "USD"
+    }
+    if !isChain(config.Chain) {
+        config.Chain = chainPulsechain
+    }
     return config, nil
 }
 
 func saveConfig(config Config) error {
-    file, err := os.Create("settings/config.json")
+    data, err := json.MarshalIndent(withoutOverrides(config), "", "  ")
     if err != nil {
         return err
     }
-    defer file.Close()
-    encoder := json.NewEncoder(file)
-    encoder.SetIndent("", "  ")
-    return encoder.Encode(config)
+    return store.WriteConfig(append(data, '\n'))
 }
 
-// Utility Functions
-func isMatured(endDate string) (bool, error) {
-    endTime, err := time.Parse(dateLayout, endDate)
-    if err != nil {
-        return false, err
+// Rewrites config.json with the defaults and applies them, miners.json is left alone
+func resetConfig() error {
+    config := defaultConfig()
+    config.ActivePortfolio = configManager.GetConfig().ActivePortfolio // Part of the miners, which are kept
+    markOverridesChanged()
+    if err := saveConfig(config); err != nil {
+        return err
     }
-    now := time.Now()
-    endDateOnly := time.Date(endTime.Year(), endTime.Month(), endTime.Day(), 0, 0, 0, 0, endTime.Location())
-    nowDateOnly := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-    return nowDateOnly.After(endDateOnly) || nowDateOnly.Equal(endDateOnly), nil
+    configManager.SetConfig(config)
+    return nil
 }
 
-func daysLeft(endDate string) (int, error) {
-    endTime, err := time.Parse(dateLayout, endDate)
-    if err != nil {
-        return 0, err
-    }
-    now := time.Now()
-    endDateOnly := time.Date(endTime.Year(), endTime.Month(), endTime.Day(), 0, 0, 0, 0, endTime.Location())
-    nowDateOnly := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
-    if nowDateOnly.After(endDateOnly) {
-        return 0, nil
+// Applies fn to the current config, saves it and publishes it to configManager
+func updateConfig(fn func(*Config)) error {
+    config := configManager.GetConfig()
+    before := config
+    fn(&config)
+    trackOverrideChanges(before, config)
+    if err := saveConfig(config); err != nil {
+        return err
     }
-    duration := endDateOnly.Sub(nowDateOnly)
-    return int(duration.Hours() / 24), nil
+    configManager.SetConfig(config)
+    return nil
 }
 
-func formatWithCommas(num int) string {
-    str := strconv.Itoa(num)
-    n := len(str)
-    if n <= 3 {
-        return str
+func newMinerID() string {
+    b := make([]byte, 8)
+    if _, err := rand.Read(b); err != nil {
+        return strconv.FormatInt(time.Now().UnixNano(), 16)
     }
-    var result []byte
-    for i := 0; i < n; i++ {
-        if i > 0 && (n-i)%3 == 0 {
-            result = append(result, ',')
+    return hex.EncodeToString(b)
+}
+
+// Gives every miner without an ID a new one, reports whether any changed
+func assignMinerIDs(miners []Miner) bool {
+    changed := false
+    for i := range miners {
+        if miners[i].ID == "" {
+            miners[i].ID = newMinerID()
+            changed = true
         }
-        result = append(result, str[i])
     }
-    return string(result)
+    return changed
 }
 
-func formatLongWithCommas(num int64) string {
-    return formatWithCommas(int(num))
+// Marks the active miner with the given ID as completed with its yield, a no-op if it already is
+func completeMiner(miners []Miner, id string, realizedHEX float64) bool {
+    for j, m := range miners {
+        if m.ID == id {
+            if m.Status == "completed" {
+                return false
+            }
+            miners[j].Status = "completed"
+            miners[j].RealizedHEX = realizedHEX
+            return true
+        }
+    }
+    return false
 }
 
-// GUI Creation Functions
-func createProfileTab(miners []Miner, w fyne.Window, refreshTabs func()) fyne.CanvasObject {
-    if len(miners) == 0 {
-        return widget.NewLabel("Empty profile. Please add HEX miners in Settings")
+// Returns miners without the one with the given ID
+func deleteMiner(miners []Miner, id string) []Miner {
+    result := make([]Miner, 0, len(miners))
+    for _, m := range miners {
+        if m.ID != id {
+            result = append(result, m)
+        }
     }
+    return result
+}
 
-    totalTShares := 0.0
-    for _, miner := range miners {
-        if miner.Status != "completed" {
-            totalTShares += miner.TShares
+// Label suffix per miner ID for miners sharing start, end and T-Shares with another,
+// unique miners have none so rows only get the extra text when it is needed
+func duplicateMarks(miners []Miner) map[string]string {
+    type key struct {
+        start, end string
+        tShares    float64
+    }
+    counts := make(map[key]int)
+    for _, m := range miners {
+        counts[key{m.StartDate, m.EndDate, m.TShares}]++
+    }
+    marks := make(map[string]string)
+    for _, m := range miners {
+        if counts[key{m.StartDate, m.EndDate, m.TShares}] > 1 && m.ID != "" {
+            marks[m.ID] = fmt.Sprintf(" [%s]", m.ID[:min(4, len(m.ID))])
         }
     }
-    totalLabel := widget.NewLabel(fmt.Sprintf("Total T-Shares: %.2f", totalTShares))
+    return marks
+}
+
+// Adds miner to the stored miners. They are reloaded first so changes made elsewhere since a
+// tab was built (imports, notifications) aren't clobbered by its older copy
+func addStoredMiner(miner Miner) error {
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    current, err := loadMiners()
+    if err != nil {
+        return err
+    }
+    journalAppend(journalEntry{Op: "add", Miner: &miner})
+    return saveMiners(append(current, miner))
+}
+
+// Adds several miners at once, reloading first like addStoredMiner
+func addStoredMiners(miners []Miner) error {
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    current, err := loadMiners()
+    if err != nil {
+        return err
+    }
+    for i := range miners {
+        journalAppend(journalEntry{Op: "add", Miner: &miners[i]})
+    }
+    return saveMiners(append(current, miners...))
+}
+
+// Deletes the miner with the given ID from the stored miners, reloading first like addStoredMiner
+func deleteStoredMiner(id string) error {
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    current, err := loadMiners()
+    if err != nil {
+        return err
+    }
+    journalAppend(journalEntry{Op: "delete", ID: id})
+    return saveMiners(deleteMiner(current, id))
+}
 
-    totalValueLabel := widget.NewLabel("Total T-Shares Value: $0.00")
+// Loads miners, completes the one with the given ID and saves if anything changed
+// The yield is estimated from the current payout per T-Share and kept for lifetime totals,
+// without a fetched payout it's stored as 0 and counted like a legacy completed miner
+func completeMinerByID(id string) error {
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    miners, err := loadMiners()
+    if err != nil {
+        return err
+    }
     liveDataMutex.Lock()
-    price := latestLiveData.TsharePricePulsechain
+    data := latestLiveData
     liveDataMutex.Unlock()
-    totalValueLabel.SetText(fmt.Sprintf("Total T-Shares Value: $%.2f", totalTShares*price))
-
-    ctx, cancel := context.WithCancel(context.Background())
-    go func() {
+    realized := 0.0
+    for _, m := range miners {
+        if m.ID != id || m.Status == "completed" {
+            continue
+        }
+        if payout := data.Chain(minerChain(m)).PayoutPerTshare; payout > 0 {
+            realized, _ = stakeYield(m, payout)
+        }
+    }
+    if !completeMiner(miners, id, realized) {
+        return nil
+    }
+    journalAppend(journalEntry{Op: "complete", ID: id, RealizedHEX: realized})
+    return saveMiners(miners)
+}
+
+// Flags the stored stakes that matured without being announced and returns them, for the end
+// prompt. Holds minersTxMutex from load to save.
+func takeNewlyMatured(now time.Time) ([]Miner, error) {
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    miners, err := loadMiners()
+    if err != nil {
+        return nil, err
+    }
+    var newlyMatured []Miner
+    for i, miner := range miners {
+        if miner.Status == "completed" || miner.Notified {
+            continue
+        }
+        if state, err := minerState(miner, now); err != nil || !state.Matured() {
+            continue
+        }
+        miners[i].Notified = true
+        newlyMatured = append(newlyMatured, miner)
+    }
+    if len(newlyMatured) == 0 {
+        return nil, nil
+    }
+    for i := range newlyMatured {
+        flagged := newlyMatured[i]
+        flagged.Notified = true
+        journalAppend(journalEntry{Op: "update", Miner: &flagged})
+    }
+    if err := saveMiners(miners); err != nil {
+        return nil, err
+    }
+    return newlyMatured, nil
+}
+
+// Ends the stored matured stakes when auto-end is on and returns them, nothing before the first fetch.
+// Holds minersTxMutex from load to save, so an edit or portfolio switch can't land in between.
+func endMaturedMiners(now time.Time) ([]Miner, error) {
+    if !configManager.GetConfig().AutoEndMatured {
+        return nil, nil
+    }
+    liveDataMutex.Lock()
+    data := latestLiveData
+    liveDataMutex.Unlock()
+    if !hasLiveData(data) {
+        return nil, nil
+    }
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    miners, err := loadMiners()
+    if err != nil {
+        return nil, err
+    }
+    ended := autoCompleteMatured(miners, now, data)
+    if len(ended) == 0 {
+        return nil, nil
+    }
+    for _, miner := range ended {
+        journalAppend(journalEntry{Op: "complete", ID: miner.ID, RealizedHEX: miner.RealizedHEX})
+    }
+    if err := saveMiners(miners); err != nil {
+        return nil, err
+    }
+    return ended, nil
+}
+
+// Marks every matured stake as completed with its yield at the payout of its chain, returns the ended ones.
+// Stakes on a chain without a fetched payout are left for a later call.
+func autoCompleteMatured(miners []Miner, now time.Time, data LiveData) []Miner {
+    var ended []Miner
+    for i, miner := range miners {
+        if state, err := minerState(miner, now); err != nil || !state.Matured() {
+            continue
+        }
+        payout := data.Chain(minerChain(miner)).PayoutPerTshare
+        if payout <= 0 {
+            continue
+        }
+        realized, _ := stakeYield(miner, payout)
+        miners[i].Status = "completed"
+        miners[i].RealizedHEX = realized
+        ended = append(ended, miners[i])
+    }
+    return ended
+}
+
+// Utility Functions
+// Calendar day of t in its own location, as UTC midnight so stored dates and local now compare
+// by day instead of by instant (and without DST making a day 23 or 25 hours)
+func calendarDay(t time.Time) time.Time {
+    return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// Where a miner is in its life. A stake counts as matured from its end day on, so a stake
+// ending today is matured (and can be ended), it just gets its own state for the label.
+type stakeState int
+
+const (
+    stakePending      stakeState = iota // Starts after today
+    stakeActive                         // Started, ends after today
+    stakeMaturesToday                   // Ends today
+    stakeMatured                        // Ended before today, not marked completed
+    stakeCompleted                      // Marked as ended by the user
+)
+
+// Whether the stake has reached its end day and isn't completed yet
+func (s stakeState) Matured() bool {
+    return s == stakeMaturesToday || s == stakeMatured
+}
+
+// The single place deciding a miner's state, everything asking "matured?" goes through it
+func minerState(miner Miner, now time.Time) (stakeState, error) {
+    if miner.Status == "completed" {
+        return stakeCompleted, nil
+    }
+    end, err := time.Parse(dateLayout, miner.EndDate)
+    if err != nil {
+        return stakeActive, err
+    }
+    today := calendarDay(now)
+    switch end := calendarDay(end); {
+    case today.Equal(end):
+        return stakeMaturesToday, nil
+    case today.After(end):
+        return stakeMatured, nil
+    }
+    start, err := time.Parse(dateLayout, miner.StartDate)
+    if err == nil && today.Before(calendarDay(start)) {
+        return stakePending, nil
+    }
+    return stakeActive, nil
+}
+
+func daysLeft(endDate string) (int, error) {
+    return daysLeftAt(endDate, time.Now())
+}
+
+func daysLeftAt(endDate string, now time.Time) (int, error) {
+    endTime, err := time.Parse(dateLayout, endDate)
+    if err != nil {
+        return 0, err
+    }
+    endDateOnly := calendarDay(endTime)
+    nowDateOnly := calendarDay(now)
+    if nowDateOnly.After(endDateOnly) {
+        return 0, nil
+    }
+    duration := endDateOnly.Sub(nowDateOnly)
+    return int(duration.Hours() / 24), nil
+}
+
+// Days left in the configured duration format
+func formatDuration(days int) string {
+    return formatDurationAs(days, configManager.GetConfig().DurationFormat)
+}
+
+// Months are counted as 30 days
+func formatDurationAs(days int, format string) string {
+    switch format {
+    case "weeks-days":
+        if days >= 7 {
+            return fmt.Sprintf("%dw %dd", days/7, days%7)
+        }
+        return fmt.Sprintf("%dd", days)
+    case "months-days":
+        if days >= 30 {
+            return fmt.Sprintf("%dm %dd", days/30, days%30)
+        }
+        return fmt.Sprintf("%dd", days)
+    default:
+        if days == 1 {
+            return "1 day"
+        }
+        return fmt.Sprintf("%d days", days)
+    }
+}
+
+// End date for a stake of days starting at startDate
+func stakeEndDate(startDate string, days int) (string, error) {
+    startTime, err := time.Parse(dateLayout, startDate)
+    if err != nil {
+        return "", err
+    }
+    return startTime.AddDate(0, 0, days).Format(dateLayout), nil
+}
+
+// Fraction (0-1) of the stake duration elapsed at now
+func stakeProgress(startDate, endDate string, now time.Time) (float64, error) {
+    startTime, err := time.Parse(dateLayout, startDate)
+    if err != nil {
+        return 0, err
+    }
+    endTime, err := time.Parse(dateLayout, endDate)
+    if err != nil {
+        return 0, err
+    }
+    nowDateOnly := calendarDay(now)
+    total := endTime.Sub(startTime)
+    if total <= 0 {
+        return 1, nil
+    }
+    progress := float64(nowDateOnly.Sub(startTime)) / float64(total)
+    if progress < 0 {
+        return 0, nil
+    }
+    if progress > 1 {
+        return 1, nil
+    }
+    return progress, nil
+}
+
+func formatWithCommas(num int) string {
+    str := strconv.Itoa(num)
+    n := len(str)
+    if n <= 3 {
+        return str
+    }
+    var result []byte
+    for i := 0; i < n; i++ {
+        if i > 0 && (n-i)%3 == 0 {
+            result = append(result, ',')
+        }
+        result = append(result, str[i])
+    }
+    return string(result)
+}
+
+func formatLongWithCommas(num int64) string {
+    return formatWithCommas(int(num))
+}
+
+var compactSuffixes = []string{"", "K", "M", "B", "T"}
+
+// Shortens f with K/M/B/T suffixes, values below 1000 are shown whole
+func formatCompact(f float64, decimals int) string {
+    sign := ""
+    if f < 0 {
+        sign = "-"
+        f = -f
+    }
+    if f < 999.5 {
+        return sign + strconv.FormatFloat(f, 'f', 0, 64)
+    }
+    i := 0
+    for i < len(compactSuffixes)-1 && f >= 1000 {
+        f /= 1000
+        i++
+    }
+    // Rounding can carry into the next unit (999.6 -> 1000, 999.999K -> 1000.00K)
+    if i == 0 {
+        f /= 1000
+        i++
+    } else if rounded, _ := strconv.ParseFloat(strconv.FormatFloat(f, 'f', decimals, 64), 64); rounded >= 1000 && i < len(compactSuffixes)-1 {
+        f /= 1000
+        i++
+    }
+    return sign + strconv.FormatFloat(f, 'f', decimals, 64) + compactSuffixes[i]
+}
+
+// Large HEX amounts, compact when enabled in settings
+func formatHEX(f float64) string {
+    config := configManager.GetConfig()
+    if config.CompactNumbers {
+        return formatCompact(f, config.CompactDecimals) + " HEX"
+    }
+    return formatWithCommas(int(f)) + " HEX"
+}
+
+// Payout per T-Share with decimals, more decimals are used when a small non-zero payout
+// would otherwise show as 0
+func formatPayoutAs(v float64, decimals int) string {
+    text := strconv.FormatFloat(v, 'f', decimals, 64)
+    for v != 0 && decimals < maxPayoutDecimals {
+        if shown, _ := strconv.ParseFloat(text, 64); shown != 0 {
+            break
+        }
+        decimals++
+        text = strconv.FormatFloat(v, 'f', decimals, 64)
+    }
+    return text + " HEX"
+}
+
+func formatPayout(v float64) string {
+    return formatPayoutAs(v, configManager.GetConfig().PayoutDecimals)
+}
+
+// Commas grouping the integer part by thousands, "1,234.5" but not the decimal comma of "12,5"
+var thousandsPattern = regexp.MustCompile(`^\d{1,3}(,\d{3})+(\.\d+)?$`)
+
+// Strips thousands separators, whitespace and currency symbols from pasted numbers
+// (T-Shares, HEX amounts, prices), anything else is left for the parser to reject
+func sanitizeNumber(s string) string {
+    s = strings.Map(func(r rune) rune {
+        if unicode.IsSpace(r) || unicode.Is(unicode.Sc, r) {
+            return -1
+        }
+        return r
+    }, s)
+    if thousandsPattern.MatchString(s) {
+        s = strings.ReplaceAll(s, ",", "")
+    }
+    return s
+}
+
+// T-Shares typed or pasted into the add form, they must be a positive number
+func parseTShares(text string) (float64, error) {
+    text = sanitizeNumber(text)
+    if text == "" {
+        return 0, fmt.Errorf("T-Shares is required")
+    }
+    val, err := strconv.ParseFloat(text, 64)
+    if err != nil {
+        return 0, fmt.Errorf("T-Shares must be a valid number")
+    }
+    if val <= 0 {
+        return 0, fmt.Errorf("T-Shares must be positive number")
+    }
+    return val, nil
+}
+
+func contains(list []string, s string) bool {
+    for _, item := range list {
+        if item == s {
+            return true
+        }
+    }
+    return false
+}
+
+func isChartField(field string) bool {
+    return contains(chartFields, field)
+}
+
+// Reports whether penalties exceed a warning threshold, 0 means disabled
+func exceedsPenaltyThreshold(penalties, threshold float64) bool {
+    return threshold > 0 && penalties > threshold
+}
+
+// Hides a holdings value while privacy mode is on
+func maskPrivate(s string) string {
+    if configManager.GetConfig().PrivacyMode {
+        return privacyMask
+    }
+    return s
+}
+
+// Formats T-Shares with the configured number of decimals
+func formatTShares(v float64) string {
+    return maskPrivate(strconv.FormatFloat(v, 'f', configManager.GetConfig().TSharesDecimals, 64))
+}
+
+// Number of pages for itemCount items, an empty list still has one page
+func pageCount(itemCount, itemsPerPage int) int {
+    if itemCount <= 0 {
+        return 1
+    }
+    return (itemCount + itemsPerPage - 1) / itemsPerPage
+}
+
+// Whether Previous and Next should be enabled on page current of total
+func pageButtonStates(current, total int) (bool, bool) {
+    return current > 1, current < total
+}
+
+// Index range [start, end) of the items shown on page
+func pageBounds(page, itemsPerPage, itemCount int) (int, int) {
+    start := (page - 1) * itemsPerPage
+    if start > itemCount {
+        start = itemCount
+    }
+    end := start + itemsPerPage
+    if end > itemCount {
+        end = itemCount
+    }
+    return start, end
+}
+
+// Previous/Next navigation calling show with the item range of the current page
+type pageNav struct {
+    itemsPerPage   int
+    itemCount      int
+    currentPage    int
+    show           func(start, end int)
+    pageLabel      *widget.Label
+    previousButton *widget.Button
+    nextButton     *widget.Button
+    Bar            *fyne.Container
+}
+
+func newPageNav(itemCount, itemsPerPage int, show func(start, end int)) *pageNav {
+    nav := &pageNav{itemsPerPage: itemsPerPage, itemCount: itemCount, currentPage: 1, show: show}
+    nav.pageLabel = widget.NewLabel("")
+    nav.previousButton = widget.NewButton("Previous", func() {
+        if nav.currentPage > 1 {
+            nav.currentPage--
+            nav.update()
+        }
+    })
+    nav.nextButton = widget.NewButton("Next", func() {
+        if nav.currentPage < pageCount(nav.itemCount, nav.itemsPerPage) {
+            nav.currentPage++
+            nav.update()
+        }
+    })
+    nav.Bar = container.NewHBox(nav.previousButton, nav.pageLabel, nav.nextButton)
+    nav.update()
+    return nav
+}
+
+// Changes the number of items (e.g. after filtering) and goes back to the first page
+func (nav *pageNav) SetItemCount(itemCount int) {
+    nav.itemCount = itemCount
+    nav.currentPage = 1
+    nav.update()
+}
+
+func (nav *pageNav) update() {
+    totalPages := pageCount(nav.itemCount, nav.itemsPerPage)
+    nav.show(pageBounds(nav.currentPage, nav.itemsPerPage, nav.itemCount))
+    nav.pageLabel.SetText(fmt.Sprintf("Page %d of %d", nav.currentPage, totalPages))
+    previousEnabled, nextEnabled := pageButtonStates(nav.currentPage, totalPages)
+    setButtonEnabled(nav.previousButton, previousEnabled)
+    setButtonEnabled(nav.nextButton, nextEnabled)
+}
+
+func setButtonEnabled(button *widget.Button, enabled bool) {
+    if enabled {
+        button.Enable()
+    } else {
+        button.Disable()
+    }
+}
+
+// GUI Creation Functions
+// readOnly hides everything that would save, used for shared portfolios
+func createProfileTab(miners []Miner, w fyne.Window, refreshTabs func(), readOnly bool) fyne.CanvasObject {
+    if len(miners) == 0 {
+        if readOnly {
+            return widget.NewLabel("Shared portfolio has no miners")
+        }
+        if name := configManager.GetConfig().ActivePortfolio; name != "" {
+            return widget.NewLabel(fmt.Sprintf("Portfolio %s is empty. Please add HEX miners in Settings", name))
+        }
+        return widget.NewLabel("Empty profile. Please add HEX miners in Settings")
+    }
+
+    liveDataMutex.Lock()
+    data := latestLiveData
+    liveDataMutex.Unlock()
+    summaryMiners := totalsMiners(miners)
+    if readOnly {
+        summaryMiners = miners // The local archive isn't part of it
+    }
+    includeCompleted := configManager.GetConfig().IncludeCompletedInTotals
+    summary := computePortfolioSummary(summaryMiners, data, time.Now(), includeCompleted)
+    views := buildMinerViewModels(miners, time.Now(), data)
+    totalTShares := summary.TotalTShares
+    showNet := configManager.GetConfig().ShowNetValue
+    netTShares := netActiveTShares(miners, time.Now())
+    for chain, tShares := range summary.ChainTShares {
+        netTShares[chain] += tShares - summary.ChainActive[chain] // Completed stakes have no late penalty
+    }
+    var totalLabel fyne.CanvasObject
+    if configManager.GetConfig().ShowLifetimeTShares {
+        totalLabel = container.NewHBox(
+            widget.NewLabel(fmt.Sprintf("Active T-Shares: %s", formatTShares(summary.ActiveTShares))),
+            widget.NewLabel(fmt.Sprintf("Lifetime T-Shares (incl. completed): %s", formatTShares(summary.LifetimeTShares))),
+        )
+    } else {
+        totalLabel = widget.NewLabel(fmt.Sprintf("Total T-Shares: %s", formatTShares(totalTShares)))
+    }
+    gainLabel := widget.NewLabel("")
+    if !summary.HasGain {
+        gainLabel.Hide()
+    }
+    lifetimeCheck := widget.NewCheck("Show lifetime T-Shares", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.ShowLifetimeTShares = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        refreshTabs()
+    })
+    lifetimeCheck.Checked = configManager.GetConfig().ShowLifetimeTShares
+
+    // Earned and projected HEX over all stakes, kept apart from the current holdings value
+    yieldLabel := widget.NewLabel(lifetimeYieldText(summary))
+    yieldLabel.Wrapping = fyne.TextWrapWord
+    if !configManager.GetConfig().ShowLifetimeYield {
+        yieldLabel.Hide()
+    }
+    yieldCheck := widget.NewCheck("Show lifetime HEX earned + projected", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.ShowLifetimeYield = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        refreshTabs()
+    })
+    yieldCheck.Checked = configManager.GetConfig().ShowLifetimeYield
+
+    totalValueLabel := widget.NewLabel("Total T-Shares Value: " + calculatingText)
+    sinceLaunchLabel := widget.NewLabel("")
+    chainTotalsLabel := widget.NewLabel("")
+    chainTotalsLabel.Hide()
+    recomputeTotals := func() {
+        liveDataMutex.Lock()
+        live := latestLiveData
+        direction, hasTrend := priceTrend(priceSamples)
+        liveDataMutex.Unlock()
+        // The gain is estimated from the fetched payout, so it follows each fetch
+        if summary.HasGain {
+            gainLabel.SetText(gainText(computePortfolioSummary(summaryMiners, live, time.Now(), includeCompleted), hasLiveData(live)))
+        }
+        pinnedPrice := configManager.GetConfig().PinnedTsharePrice
+        price, pinned := valuationPrice(live.TsharePricePulsechain, pinnedPrice)
+        if !chainPricesKnown(summary.ChainTShares, live, pinnedPrice) {
+            totalValueLabel.SetText("Total T-Shares Value: " + calculatingText)
+            sinceLaunchLabel.SetText("Since launch: -")
+            return
+        }
+        if text := chainTotalsText(summary, live, pinnedPrice); text != "" {
+            chainTotalsLabel.SetText(text)
+            chainTotalsLabel.Show()
+        }
+        total := chainValue(summary.ChainTShares, live, pinnedPrice)
+        net := chainValue(netTShares, live, pinnedPrice)
+        text := fmt.Sprintf("Total T-Shares Value: %s", maskPrivate(formatMoney(total, 2)))
+        if showNet && net < total {
+            text = fmt.Sprintf("Total T-Shares Value: %s (net of est. late penalties: %s)", maskPrivate(formatMoney(total, 2)), maskPrivate(formatMoney(net, 2)))
+        }
+        if pinned {
+            text += fmt.Sprintf(" (valued at pinned %s)", formatMoney(price, tsharePriceDecimals))
+        } else if hasTrend { // The live trend says nothing about a pinned value
+            text += fmt.Sprintf(" %s (1h)", priceTrendArrow(direction))
+        }
+        if note := fxStaleNote(); note != "" {
+            text += " - " + note
+        }
+        totalValueLabel.SetText(text)
+        if !pinned {
+            baseline, _ := sessionBaselineData()
+            sinceLaunchLabel.SetText(sinceLaunchValueText(chainValue(summary.ChainTShares, baseline, 0), total))
+        } else {
+            sinceLaunchLabel.SetText("Since launch: - (valued at a pinned price)")
+        }
+    }
+    recomputeTotals()
+
+    // Tapping the total value recomputes it immediately
+    totalValueTrigger := newUpdateTrigger()
+    totalValueTrigger.onTapped = func(_ *fyne.PointEvent) {
+        recomputeTotals()
+    }
+    recomputeButton := widget.NewButton("Recompute", recomputeTotals)
+    totalValueRow := container.NewHBox(container.NewStack(totalValueLabel, totalValueTrigger), recomputeButton)
+    resetSinceLaunchButton := widget.NewButton("Reset", func() {
+        resetSessionBaseline()
+        recomputeTotals()
+    })
+    sinceLaunchRow := container.NewHBox(sinceLaunchLabel, resetSinceLaunchButton)
+    if !configManager.GetConfig().ShowSinceLaunch {
+        sinceLaunchRow.Hide()
+    }
+
+    // Miner rows built before the prices of their chains were fetched show placeholders, they
+    // are rebuilt once those land
+    coldStart := !chainPricesKnown(summary.ChainTShares, data, 0) && refreshTabs != nil
+    ctx, cancel := context.WithCancel(context.Background())
+    go func() {
         frequency := configManager.GetLiveDataFrequency()
         ticker := time.NewTicker(time.Duration(frequency) * time.Minute)
         changeCh := configManager.Subscribe()
+        liveCh := liveDataUpdated.Subscribe()
+        defer configManager.Unsubscribe(changeCh)
+        defer liveDataUpdated.Unsubscribe(liveCh)
         defer ticker.Stop()
         for {
             select {
+            case <-liveCh:
+                if coldStart {
+                    liveDataMutex.Lock()
+                    ready := chainPricesKnown(summary.ChainTShares, latestLiveData, 0)
+                    liveDataMutex.Unlock()
+                    if ready {
+                        coldStart = false
+                        fyne.Do(refreshTabs)
+                        continue
+                    }
+                }
+                fyne.DoAndWait(recomputeTotals)
             case <-ticker.C:
-                liveDataMutex.Lock()
-                price := latestLiveData.TsharePricePulsechain
-                liveDataMutex.Unlock()
-                fyne.DoAndWait(func() {
-                    totalValueLabel.SetText(fmt.Sprintf("Total T-Shares Value: $%.2f", totalTShares*price))
-                    totalValueLabel.Refresh()
-                })
+                fyne.DoAndWait(recomputeTotals)
                 frequency = configManager.GetLiveDataFrequency()
                 ticker.Reset(time.Duration(frequency) * time.Minute)
             case <-changeCh:
@@ -388,191 +1670,132 @@ func createProfileTab(miners []Miner, w fyne.Window, refreshTabs func()) fyne.Ca
             }
         }
     }()
-    fyne.CurrentApp().Lifecycle().SetOnStopped(cancel)
 
-    // Pagination for Active Miners
+    // Pagination for Active Miners. Miners with data that can't be used (e.g. a hand-edited
+    // date) get their own section instead of disappearing from the list.
     activeMiners := []Miner{}
-    for _, miner := range miners {
-        if miner.Status != "completed" {
-            activeMiners = append(activeMiners, miner)
+    activeViews := []minerView{}
+    invalidBox := container.NewVBox()
+    for _, view := range views {
+        miner := view.Miner
+        if view.State == stakeCompleted {
+            continue
+        }
+        if view.Err != nil {
+            label := widget.NewLabel(fmt.Sprintf("Start: %s, End: %s, T-Shares: %s - %v", miner.StartDate, miner.EndDate, formatTShares(miner.TShares), view.Err))
+            label.Importance = widget.DangerImportance
+            if readOnly {
+                invalidBox.Add(label)
+                continue
+            }
+            invalidBox.Add(container.NewHBox(label, widget.NewButton("Edit", func() {
+                showEditMinerDialog(miner, w, refreshTabs)
+            })))
+            continue
         }
+        activeMiners = append(activeMiners, miner)
+        activeViews = append(activeViews, view)
+    }
+    invalidSection := container.NewVBox(widget.NewLabel("Miners With Invalid Data"), invalidBox)
+    if len(invalidBox.Objects) == 0 {
+        invalidSection.Hide()
     }
 
     const itemsPerPage = 5
-    totalPages := (len(activeMiners) + itemsPerPage - 1) / itemsPerPage
-    currentPage := 1
 
     activeBox := container.NewVBox()
-    pageLabel := widget.NewLabel(fmt.Sprintf("Page %d of %d", currentPage, totalPages))
+    marks := duplicateMarks(miners)
+    shownViews := activeViews // Active miners matching the tag filter
 
-    updateActiveMiners := func() {
+    updateActiveMiners := func(startIndex, endIndex int) {
         activeBox.Objects = nil
-        startIndex := (currentPage - 1) * itemsPerPage
-        endIndex := startIndex + itemsPerPage
-        if endIndex > len(activeMiners) {
-            endIndex = len(activeMiners)
-        }
         for i := startIndex; i < endIndex; i++ {
-            miner := activeMiners[i]
+            view := shownViews[i]
+            miner := view.Miner
             var entry fyne.CanvasObject
-            matured, err := isMatured(miner.EndDate)
-            if err != nil {
-                continue
+            matured := view.State.Matured()
+            maturedText := "(Matured)"
+            if view.State == stakeMaturesToday {
+                maturedText = "(Matures today)"
             }
-            if matured {
-                idx := i // Adjusted index for activeMiners slice
-                endButton := widget.NewButton("END", func() {
+            if matured && readOnly {
+                entry = widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %s%s %s%s%s%s", miner.StartDate, miner.EndDate, formatTShares(miner.TShares), chainNote(miner), maturedText, view.gainNote("Gain"), netValueNote(view, data.Chain(minerChain(miner)).TsharePrice), marks[miner.ID]))
+            } else if matured {
+                idx := i // Adjusted index for shownViews slice
+                var endButton *widget.Button
+                endButton = widget.NewButton("END", func() {
+                    endButton.Disable() // Ignore repeated clicks while the dialog is open
                     dialog.ShowConfirm("Congratulations!", "Have you ended the mining contract and minted HEX?", func(yes bool) {
-                        if yes {
-                            // Find the original miner index in miners slice
-                            for j, m := range miners {
-                                if m.StartDate == activeMiners[idx].StartDate &&
-                                    m.EndDate == activeMiners[idx].EndDate &&
-                                    m.TShares == activeMiners[idx].TShares {
-                                    miners[j].Status = "completed"
-                                    break
-                                }
-                            }
-                            if err := saveMiners(miners); err != nil {
-                                log.Println("Error saving miners:", err)
-                            }
-                            refreshTabs()
+                        if !yes {
+                            endButton.Enable()
+                            return
+                        }
+                        if err := completeMinerByID(shownViews[idx].Miner.ID); err != nil {
+                            log.Println("Error saving miners:", err)
                         }
+                        refreshTabs()
                     }, w)
                 })
                 endButtonContainer := container.NewMax(endButton)
                 endButtonContainer.Resize(fyne.NewSize(60, 30))
 
-                label := widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %.2f (Matured)", miner.StartDate, miner.EndDate, miner.TShares))
+                label := widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %s%s %s%s%s%s", miner.StartDate, miner.EndDate, formatTShares(miner.TShares), chainNote(miner), maturedText, view.gainNote("Gain"), netValueNote(view, data.Chain(minerChain(miner)).TsharePrice), marks[miner.ID]))
                 label.TextStyle = fyne.TextStyle{Bold: true}
                 label.Wrapping = fyne.TextWrapOff
                 label.Resize(fyne.NewSize(300, 30))
 
                 entry = container.NewHBox(label, endButtonContainer)
             } else {
-                days, _ := daysLeft(miner.EndDate)
-                entry = widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %.2f (%d days left)", miner.StartDate, miner.EndDate, miner.TShares, days))
+                entry = widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %s%s (%s left)%s%s", miner.StartDate, miner.EndDate, formatTShares(miner.TShares), chainNote(miner), formatDuration(view.DaysLeft), view.gainNote("Projected Gain"), marks[miner.ID]))
             }
-            activeBox.Add(entry)
+            activeBox.Add(withTagChips(entry, miner.Tags))
         }
-        pageLabel.SetText(fmt.Sprintf("Page %d of %d", currentPage, totalPages))
         activeBox.Refresh()
     }
 
-    var previousButton, nextButton *widget.Button
-    previousButton = widget.NewButton("Previous", func() {
-        if currentPage > 1 {
-            currentPage--
-            updateActiveMiners()
-            if currentPage == 1 {
-                previousButton.Disable()
-            }
-            if currentPage < totalPages {
-                nextButton.Enable()
-            }
-        }
-    })
-    nextButton = widget.NewButton("Next", func() {
-        if currentPage < totalPages {
-            currentPage++
-            updateActiveMiners()
-            if currentPage == totalPages {
-                nextButton.Disable()
-            }
-            if currentPage > 1 {
-                previousButton.Enable()
-            }
-        }
-    })
-
-    updateActiveMiners()
+    activeNav := newPageNav(len(activeMiners), itemsPerPage, updateActiveMiners)
+    navBar := activeNav.Bar
 
-    if currentPage == 1 {
-        previousButton.Disable()
+    // Selecting tags narrows the list to miners having all of them
+    tagFilter := widget.NewCheckGroup(allTags(activeMiners), func(selected []string) {
+        shownViews = filterByTags(activeViews, selected)
+        activeNav.SetItemCount(len(shownViews))
+    })
+    tagFilter.Horizontal = true
+    tagFilterRow := container.NewHBox(widget.NewLabel("Filter by tags:"), tagFilter)
+    if len(tagFilter.Options) == 0 {
+        tagFilterRow.Hide()
     }
-    if currentPage == totalPages || totalPages == 0 {
-        nextButton.Disable()
+
+    completedViews := []minerView{}
+    for _, view := range views {
+        if view.State == stakeCompleted {
+            completedViews = append(completedViews, view)
+        }
     }
 
-    navBar := container.NewHBox(previousButton, pageLabel, nextButton)
-
-    completedMinersButton := widget.NewButton("View Completed Miners", func() {
-        completedMiners := []Miner{}
-        for j := range miners {
-            if miners[j].Status == "completed" {
-                completedMiners = append(completedMiners, miners[j])
-            }
-        }
-
+    // Only offered when there is something to show
+    completedMinersButton := widget.NewButton(fmt.Sprintf("View Completed Miners (%d)", len(completedViews)), func() {
         completedWindow := fyne.CurrentApp().NewWindow("Completed Miners")
         completedWindow.Resize(fyne.NewSize(600, 400))
 
-        if len(completedMiners) == 0 {
-            completedWindow.SetContent(widget.NewLabel("No completed miners."))
-            completedWindow.Show()
-            return
-        }
-
         const itemsPerPage = 10
-        totalPages := (len(completedMiners) + itemsPerPage - 1) / itemsPerPage
-        currentPage := 1
 
         minersBox := container.NewVBox()
-        pageLabel := widget.NewLabel(fmt.Sprintf("Page %d of %d", currentPage, totalPages))
 
-        updateMiners := func() {
+        updateMiners := func(startIndex, endIndex int) {
             minersBox.Objects = nil
-            startIndex := (currentPage - 1) * itemsPerPage
-            endIndex := startIndex + itemsPerPage
-            if endIndex > len(completedMiners) {
-                endIndex = len(completedMiners)
-            }
             for i := startIndex; i < endIndex; i++ {
-                miner := completedMiners[i]
-                label := widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %.2f", miner.StartDate, miner.EndDate, miner.TShares))
+                view := completedViews[i]
+                miner := view.Miner
+                label := widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %s%s%s%s", miner.StartDate, miner.EndDate, formatTShares(miner.TShares), chainNote(miner), view.gainNote("Gain"), marks[miner.ID]))
                 label.Wrapping = fyne.TextWrapOff
                 minersBox.Add(label)
             }
-            pageLabel.SetText(fmt.Sprintf("Page %d of %d", currentPage, totalPages))
             minersBox.Refresh()
         }
 
-        var previousButton, nextButton *widget.Button
-        previousButton = widget.NewButton("Previous", func() {
-            if currentPage > 1 {
-                currentPage--
-                updateMiners()
-                if currentPage == 1 {
-                    previousButton.Disable()
-                }
-                if currentPage < totalPages {
-                    nextButton.Enable()
-                }
-            }
-        })
-        nextButton = widget.NewButton("Next", func() {
-            if currentPage < totalPages {
-                currentPage++
-                updateMiners()
-                if currentPage == totalPages {
-                    nextButton.Disable()
-                }
-                if currentPage > 1 {
-                    previousButton.Enable()
-                }
-            }
-        })
-
-        updateMiners()
-
-        if currentPage == 1 {
-            previousButton.Disable()
-        }
-        if currentPage == totalPages {
-            nextButton.Disable()
-        }
-
-        navBar := container.NewHBox(previousButton, pageLabel, nextButton)
+        navBar := newPageNav(len(completedViews), itemsPerPage, updateMiners).Bar
         closeButton := widget.NewButton("Close", func() {
             completedWindow.Close()
         })
@@ -588,51 +1811,210 @@ func createProfileTab(miners []Miner, w fyne.Window, refreshTabs func()) fyne.Ca
         completedWindow.Show()
     })
 
-    return container.NewVBox(
+    ladderButton := widget.NewButton("View Stake Ladder", func() {
+        showLadderWindow(miners)
+    })
+
+    reportButton := widget.NewButton("Generate Report", func() {
+        saveDialog := dialog.NewFileSave(func(writer fyne.URIWriteCloser, err error) {
+            if err != nil {
+                showError(err, w)
+                return
+            }
+            if writer == nil {
+                return
+            }
+            defer writer.Close()
+            liveDataMutex.Lock()
+            data := latestLiveData
+            liveDataMutex.Unlock()
+            if err := writeReport(writer, buildReportData(miners, data, time.Now())); err != nil {
+                log.Println("Error writing report:", err)
+                showError(fmt.Errorf("Failed to write report"), w)
+                return
+            }
+            dialog.ShowInformation("Report Saved", fmt.Sprintf("Report written to %s", writer.URI().Name()), w)
+        }, w)
+        saveDialog.SetFileName("hexfetch-report.html")
+        saveDialog.SetFilter(storage.NewExtensionFileFilter([]string{".html"}))
+        saveDialog.Show()
+    })
+
+    if len(completedViews) == 0 {
+        completedMinersButton.Hide()
+    }
+
+    if readOnly {
+        return withTabWork(container.NewVBox(
+            totalLabel,
+            totalValueRow,
+            chainTotalsLabel,
+            gainLabel,
+            yieldLabel,
+            widget.NewLabel("Active Miners"),
+            tagFilterRow,
+            activeBox,
+            navBar,
+            invalidSection,
+            completedMinersButton,
+            ladderButton,
+        ), cancel)
+    }
+
+    archived, err := loadArchivedMiners()
+    if err != nil {
+        log.Println("Error loading archived miners:", err)
+    }
+    archivedButton := widget.NewButton(fmt.Sprintf("View Archived Miners (%d)", len(archived)), func() {
+        showArchivedMinersWindow(archived)
+    })
+    if len(archived) == 0 {
+        archivedButton.Hide()
+    }
+
+    copySummaryButton := widget.NewButton("Copy Summary", func() {
+        liveDataMutex.Lock()
+        data := latestLiveData
+        liveDataMutex.Unlock()
+        fyne.CurrentApp().Clipboard().SetContent(buildSummaryText(miners, data, time.Now()))
+        dialog.ShowInformation("Summary Copied", "Portfolio summary copied to the clipboard", w)
+    })
+
+    portfolioLabel := widget.NewLabel(fmt.Sprintf("Portfolio: %s", configManager.GetConfig().ActivePortfolio))
+    portfolioLabel.TextStyle = fyne.TextStyle{Bold: true}
+    if configManager.GetConfig().ActivePortfolio == "" {
+        portfolioLabel.Hide() // Only worth a line once there are several
+    }
+
+    return withTabWork(container.NewVBox(
+        portfolioLabel,
         totalLabel,
-        totalValueLabel,
+        lifetimeCheck,
+        yieldCheck,
+        totalValueRow,
+        chainTotalsLabel,
+        sinceLaunchRow,
+        gainLabel,
+        yieldLabel,
         widget.NewLabel("Active Miners"),
+        tagFilterRow,
         activeBox,
         navBar,
+        invalidSection,
         completedMinersButton,
-    )
+        archivedButton,
+        ladderButton,
+        reportButton,
+        copySummaryButton,
+    ), cancel)
 }
 
-func createLiveDataTab() fyne.CanvasObject {
-    priceLabel := widget.NewLabel("Price: $0.00")
-    priceLabel.Alignment = fyne.TextAlignCenter
-    priceLabel.TextStyle = fyne.TextStyle{Bold: true}
+func newLiveDataValueLabel() *widget.Label {
+    label := widget.NewLabel("")
+    label.TextStyle = fyne.TextStyle{Bold: true}
+    return label
+}
 
-    tsharePriceLabel := widget.NewLabel("T-Share Price: $0.00")
-    tsharePriceLabel.Alignment = fyne.TextAlignCenter
-    tsharePriceLabel.TextStyle = fyne.TextStyle{Bold: true}
+func createLiveDataTab(refreshTabs func()) fyne.CanvasObject {
+    priceLabel := newLiveDataValueLabel()
+    tsharePriceLabel := newLiveDataValueLabel()
+    tshareRateLabel := newLiveDataValueLabel()
+    payoutLabel := newLiveDataValueLabel()
+    beatLabel := newLiveDataValueLabel()
+    fxNoteLabel := widget.NewLabel("")
+    fxNoteLabel.Importance = widget.LowImportance
+    schemaNoteLabel := widget.NewLabel("")
+    schemaNoteLabel.Importance = widget.WarningImportance
+    sinceLaunchLabel := widget.NewLabel("")
+    chain := configManager.GetConfig().Chain
+
+    // Both chains come with every fetch, switching only changes which one is shown
+    chainSelect := widget.NewSelect(chainOptions(), nil)
+    chainSelect.SetSelected(chainName(chain))
+    chainSelect.OnChanged = func(name string) {
+        if err := updateConfig(func(c *Config) {
+            c.Chain = chainByName(name)
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        refreshTabs()
+    }
 
-    tshareRateLabel := widget.NewLabel("T-Share Rate: 0 HEX")
-    tshareRateLabel.Alignment = fyne.TextAlignCenter
-    tshareRateLabel.TextStyle = fyne.TextStyle{Bold: true}
+    penaltiesLabel := canvas.NewText("", theme.Color(theme.ColorNameForeground))
+    penaltiesLabel.TextStyle = fyne.TextStyle{Bold: true}
+    setPenalties := func(penalties float64) {
+        penaltiesLabel.Text = formatHEX(penalties)
+        if exceedsPenaltyThreshold(penalties, configManager.GetConfig().PenaltyWarnThreshold) {
+            penaltiesLabel.Color = theme.Color(theme.ColorNameWarning)
+        } else {
+            penaltiesLabel.Color = theme.Color(theme.ColorNameForeground)
+        }
+        penaltiesLabel.Refresh()
+    }
 
-    payoutLabel := widget.NewLabel("Payout Per T-Share: 0.0 HEX")
-    payoutLabel.Alignment = fyne.TextAlignCenter
-    payoutLabel.TextStyle = fyne.TextStyle{Bold: true}
+    flashes := map[string]*flashValue{
+        "price":       newFlashValue(priceLabel),
+        "tsharePrice": newFlashValue(tsharePriceLabel),
+        "tshareRate":  newFlashValue(tshareRateLabel),
+        "payout":      newFlashValue(payoutLabel),
+        "penalties":   newFlashValue(container.NewPadded(penaltiesLabel)),
+        "beat":        newFlashValue(beatLabel),
+    }
+    var shown LiveData
+    hasShown := false
 
-    penaltiesLabel := widget.NewLabel("Penalties: 0 HEX")
-    penaltiesLabel.Alignment = fyne.TextAlignCenter
-    penaltiesLabel.TextStyle = fyne.TextStyle{Bold: true}
+    updateValues := func(data LiveData) {
+        if hasShown && configManager.GetConfig().HighlightChanges {
+            for _, field := range changedLiveFields(shown.Chain(chain), data.Chain(chain), shown.Beat, data.Beat) {
+                flashes[field].Flash()
+            }
+        }
+        shown, hasShown = data, true
+        chainData := data.Chain(chain)
+        priceLabel.SetText(formatMoney(chainData.Price, priceDecimals))
+        tsharePriceLabel.SetText(formatMoney(chainData.TsharePrice, tsharePriceDecimals))
+        tshareRateLabel.SetText(formatHEX(chainData.TshareRateHEX))
+        payoutLabel.SetText(formatPayout(chainData.PayoutPerTshare))
+        setPenalties(chainData.PenaltiesHEX)
+        beatLabel.SetText(formatLongWithCommas(data.Beat))
+        fxNoteLabel.SetText(fxStaleNote())
+        schemaNoteLabel.SetText(schemaDriftNote())
+        baseline, _ := sessionBaselineData()
+        sinceLaunchLabel.SetText(sinceLaunchPricesText(baseline.Chain(chain), chainData))
+    }
 
-    beatLabel := widget.NewLabel("Beat: 0")
-    beatLabel.Alignment = fyne.TextAlignCenter
-    beatLabel.TextStyle = fyne.TextStyle{Bold: true}
+    // Health of the primary API and its fallbacks, only shown once there are fallbacks
+    sourcesBox := container.NewVBox()
+    updateSources := func() {
+        sources := apiSources(configManager.GetConfig())
+        sourcesBox.Objects = nil
+        if len(sources) == 1 {
+            sourcesBox.Refresh()
+            return
+        }
+        sourcesBox.Add(widget.NewLabel("Data Sources"))
+        now := time.Now()
+        for i, source := range sources {
+            role := "Primary"
+            if i > 0 {
+                role = fmt.Sprintf("Fallback %d", i)
+            }
+            label := widget.NewLabel(fmt.Sprintf("%s %s: %s", role, source, sourceStatusText(source, now)))
+            label.Wrapping = fyne.TextWrapWord
+            if sourceHealthOf(source).Failures > 0 {
+                label.Importance = widget.WarningImportance
+            }
+            sourcesBox.Add(label)
+        }
+        sourcesBox.Refresh()
+    }
 
     // Initial update
     liveDataMutex.Lock()
     data := latestLiveData
     liveDataMutex.Unlock()
-    priceLabel.SetText(fmt.Sprintf("Price: $%.4f", data.PricePulsechain))
-    tsharePriceLabel.SetText(fmt.Sprintf("T-Share Price: $%.2f", data.TsharePricePulsechain))
-    tshareRateLabel.SetText(fmt.Sprintf("T-Share Rate: %s HEX", formatWithCommas(int(data.TshareRateHEXPulsechain))))
-    payoutLabel.SetText(fmt.Sprintf("Payout Per T-Share: %.1f HEX", data.PayoutPerTsharePulsechain))
-    penaltiesLabel.SetText(fmt.Sprintf("Penalties: %s HEX", formatWithCommas(int(data.PenaltiesHEXPulsechain))))
-    beatLabel.SetText(fmt.Sprintf("Beat: %s", formatLongWithCommas(data.Beat)))
+    updateValues(data)
+    updateSources()
 
     // Start a ticker to periodically update the labels
     ctx, cancel := context.WithCancel(context.Background())
@@ -640,26 +2022,30 @@ func createLiveDataTab() fyne.CanvasObject {
         frequency := configManager.GetLiveDataFrequency()
         ticker := time.NewTicker(time.Duration(frequency) * time.Minute)
         changeCh := configManager.Subscribe()
+        liveCh := liveDataUpdated.Subscribe()
+        sourcesCh := sourceHealthUpdated.Subscribe()
+        defer configManager.Unsubscribe(changeCh)
+        defer liveDataUpdated.Unsubscribe(liveCh)
+        defer sourceHealthUpdated.Unsubscribe(sourcesCh)
         defer ticker.Stop()
         for {
             select {
+            case <-liveCh:
+                liveDataMutex.Lock()
+                data := latestLiveData
+                liveDataMutex.Unlock()
+                fyne.DoAndWait(func() {
+                    updateValues(data)
+                })
+            case <-sourcesCh:
+                fyne.DoAndWait(updateSources)
             case <-ticker.C:
                 liveDataMutex.Lock()
                 data := latestLiveData
                 liveDataMutex.Unlock()
                 fyne.DoAndWait(func() {
-                    priceLabel.SetText(fmt.Sprintf("Price: $%.4f", data.PricePulsechain))
-                    tsharePriceLabel.SetText(fmt.Sprintf("T-Share Price: $%.2f", data.TsharePricePulsechain))
-                    tshareRateLabel.SetText(fmt.Sprintf("T-Share Rate: %s HEX", formatWithCommas(int(data.TshareRateHEXPulsechain))))
-                    payoutLabel.SetText(fmt.Sprintf("Payout Per T-Share: %.1f HEX", data.PayoutPerTsharePulsechain))
-                    penaltiesLabel.SetText(fmt.Sprintf("Penalties: %s HEX", formatWithCommas(int(data.PenaltiesHEXPulsechain))))
-                    beatLabel.SetText(fmt.Sprintf("Beat: %s", formatLongWithCommas(data.Beat)))
-                    priceLabel.Refresh()
-                    tsharePriceLabel.Refresh()
-                    tshareRateLabel.Refresh()
-                    payoutLabel.Refresh()
-                    penaltiesLabel.Refresh()
-                    beatLabel.Refresh()
+                    updateValues(data)
+                    updateSources()
                 })
                 frequency = configManager.GetLiveDataFrequency()
                 ticker.Reset(time.Duration(frequency) * time.Minute)
@@ -673,419 +2059,1823 @@ func createLiveDataTab() fyne.CanvasObject {
         }
     }()
 
-    // Stop the ticker when the app stops
-    fyne.CurrentApp().Lifecycle().SetOnStopped(cancel)
+    // Two columns so names and values line up
+    content := container.New(layout.NewFormLayout(),
+        widget.NewLabel("Chain"), chainSelect,
+        widget.NewLabel("Price"), flashes["price"].Content,
+        widget.NewLabel("T-Share Price"), flashes["tsharePrice"].Content,
+        widget.NewLabel("T-Share Rate"), flashes["tshareRate"].Content,
+        widget.NewLabel("Payout Per T-Share"), flashes["payout"].Content,
+        widget.NewLabel("Penalties"), flashes["penalties"].Content,
+        widget.NewLabel("Beat"), flashes["beat"].Content,
+    )
+
+    popoutButton := widget.NewButton("Pop Out Live Data", showLiveDataPopout)
+
+    resetSinceLaunchButton := widget.NewButton("Reset", func() {
+        resetSessionBaseline()
+        updateValues(shown)
+    })
+    sinceLaunchRow := container.NewHBox(sinceLaunchLabel, resetSinceLaunchButton)
+    if !configManager.GetConfig().ShowSinceLaunch {
+        sinceLaunchRow.Hide()
+    }
+
+    centeredContent := container.NewCenter(container.NewVBox(content, sinceLaunchRow, fxNoteLabel, schemaNoteLabel, sourcesBox, popoutButton))
+
+    return withTabWork(centeredContent, cancel)
+}
+
+type chartOptions struct {
+    LineStyle   string
+    ShowMarkers bool
+    ShowToday   bool // Vertical line at the latest day
+    Width       int
+    Height      int
+    Chain       string // Whose history is charted
+    FromDay     int    // Days charted, all of the history while ToDay is 0
+    ToDay       int
+}
+
+func chartOptionsFromConfig(config Config) chartOptions {
+    return chartOptions{
+        LineStyle:   config.ChartLineStyle,
+        ShowMarkers: config.ChartShowMarkers,
+        ShowToday:   config.ChartShowToday,
+        Width:       config.ChartWidth,
+        Height:      config.ChartHeight,
+        Chain:       config.Chain,
+    }
+}
+
+// Smallest size a chart image asks for: a quarter of the rendered size with the same aspect
+// ratio, so it shrinks with small windows (ImageFillContain scales it) instead of forcing
+// scrolling or clipping
+func chartMinSize(width, height int) fyne.Size {
+    return fyne.NewSize(float32(width)/4, float32(height)/4)
+}
+
+// Chart loads running in the background, waited for before the storage is swapped in tests
+var chartLoads sync.WaitGroup
+
+// Hands out increasing render generations, only the latest may update the image
+type renderGeneration struct {
+    mu     sync.Mutex
+    latest uint64
+}
+
+func (g *renderGeneration) Next() uint64 {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    g.latest++
+    return g.latest
+}
 
-    content := container.NewVBox(
-        container.NewPadded(priceLabel),
-        container.NewPadded(tsharePriceLabel),
-        container.NewPadded(tshareRateLabel),
-        container.NewPadded(payoutLabel),
-        container.NewPadded(penaltiesLabel),
-        container.NewPadded(beatLabel),
+func (g *renderGeneration) IsLatest(gen uint64) bool {
+    g.mu.Lock()
+    defer g.mu.Unlock()
+    return gen == g.latest
+}
+
+// Copy of miners that shares no backing storage with it, tags included
+func copyMiners(miners []Miner) []Miner {
+    copied := make([]Miner, len(miners))
+    for i, miner := range miners {
+        miner.Tags = append([]string(nil), miner.Tags...)
+        copied[i] = miner
+    }
+    return copied
+}
+
+// Year, month and day selects for a date field, the picked date is written to field
+func showCalendarDialog(title string, field *widget.Entry, w fyne.Window) {
+    now := time.Now()
+    selectedDate := now
+    if field.Text != "" {
+        if parsed, err := time.Parse(dateLayout, field.Text); err == nil {
+            selectedDate = parsed
+        }
+    }
+
+    years := make([]string, 0, 11)
+    for y := 2019; y <= (now.AddDate(15, 2, 20).Year()); y++ {
+        years = append(years, strconv.Itoa(y))
+    }
+    yearSelect := widget.NewSelect(years, nil)
+    yearSelect.SetSelected(strconv.Itoa(selectedDate.Year()))
+
+    months := []string{
+        "January", "February", "March", "April", "May", "June",
+        "July", "August", "September", "October", "November", "December",
+    }
+    monthSelect := widget.NewSelect(months, nil)
+    monthSelect.SetSelected(months[selectedDate.Month()-1])
+
+    days := make([]string, 0, 31)
+    for d := 1; d <= 31; d++ {
+        days = append(days, strconv.Itoa(d))
+    }
+    daySelect := widget.NewSelect(days, nil)
+    daySelect.SetSelected(strconv.Itoa(selectedDate.Day()))
+
+    form := &widget.Form{
+        Items: []*widget.FormItem{
+            {Text: "Year", Widget: yearSelect},
+            {Text: "Month", Widget: monthSelect},
+            {Text: "Day", Widget: daySelect},
+        },
+        SubmitText: "Confirm",
+        CancelText: "Cancel",
+    }
+
+    d := dialog.NewCustomWithoutButtons(title, container.NewVBox(
+        form,
+    ), w)
+    form.OnSubmit = func() {
+        year, _ := strconv.Atoi(yearSelect.Selected)
+        monthIndex := 0
+        for i, m := range months {
+            if m == monthSelect.Selected {
+                monthIndex = i + 1
+                break
+            }
+        }
+        day, _ := strconv.Atoi(daySelect.Selected)
+
+        date, err := time.Parse("2006-1-2", fmt.Sprintf("%d-%d-%d", year, monthIndex, day))
+        if err != nil {
+            showError(fmt.Errorf("Invalid date: %s %s, %s", monthSelect.Selected, daySelect.Selected, yearSelect.Selected), w)
+            return
+        }
+
+        field.SetText(date.Format(dateLayout))
+        field.Refresh()
+        d.Hide()
+    }
+    form.OnCancel = func() {
+        d.Hide()
+    }
+    d.Show()
+}
+
+// Existing Miners as a dense table with icon buttons, edit and remove get the miner of their row
+func compactMinerTable(miners []Miner, marks map[string]string, edit func(Miner), remove func(id string)) *fyne.Container {
+    table := container.NewGridWithColumns(4,
+        widget.NewLabelWithStyle("Start", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+        widget.NewLabelWithStyle("End", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+        widget.NewLabelWithStyle("T-Shares", fyne.TextAlignLeading, fyne.TextStyle{Bold: true}),
+        widget.NewLabel(""),
     )
+    for _, miner := range miners {
+        miner := miner
+        editButton := widget.NewButtonWithIcon("", theme.DocumentCreateIcon(), func() {
+            edit(miner)
+        })
+        editButton.Importance = widget.LowImportance
+        deleteButton := widget.NewButtonWithIcon("", theme.DeleteIcon(), func() {
+            remove(miner.ID)
+        })
+        deleteButton.Importance = widget.LowImportance
+        table.Add(widget.NewLabel(miner.StartDate))
+        table.Add(widget.NewLabel(miner.EndDate))
+        table.Add(widget.NewLabel(formatTShares(miner.TShares) + marks[miner.ID]))
+        table.Add(container.NewHBox(editButton, deleteButton))
+    }
+    return table
+}
+
+func createSettingsTab(miners []Miner, w fyne.Window, refreshTabs func()) fyne.CanvasObject {
+    localMiners := copyMiners(miners) // The tab's own copy, edits don't reach the caller's slice
+    startDateField := widget.NewEntry()
+    startDateField.SetPlaceHolder("Click to select Start Date")
+    startDateTap := widget.NewButton("", nil)
+    startDateTap.Importance = widget.LowImportance
+    startDateContainer := container.NewStack(startDateField, startDateTap)
+    endDateField := widget.NewEntry()
+    endDateField.SetPlaceHolder("Click to select End Date")
+    endDateTap := widget.NewButton("", nil)
+    endDateTap.Importance = widget.LowImportance
+    endDateContainer := container.NewStack(endDateField, endDateTap)
+    tSharesEntry := widget.NewEntry()
+    tSharesEntry.SetPlaceHolder("T-Shares")
+    principalEntry := widget.NewEntry()
+    principalEntry.SetPlaceHolder("Principal HEX (optional)")
+    tagsEntry := widget.NewEntry()
+    tagsEntry.SetPlaceHolder("Tags (optional, comma separated)")
+    minerChainSelect := widget.NewSelect(chainOptions(), nil)
+    minerChainSelect.SetSelected(chainName(configManager.GetConfig().Chain))
+
+    tSharesEntry.Validator = func(s string) error {
+        _, err := parseTShares(s)
+        return err
+    }
+
+    // Picking a start date fills in the end date unless the user already chose one
+    autoEndDate := ""
+    startDateField.OnChanged = func(start string) {
+        days := configManager.GetConfig().DefaultStakeDays
+        if days <= 0 || (endDateField.Text != "" && endDateField.Text != autoEndDate) {
+            return
+        }
+        end, err := stakeEndDate(start, days)
+        if err != nil {
+            return
+        }
+        autoEndDate = end
+        endDateField.SetText(end)
+    }
+
+    startDateTap.OnTapped = func() {
+        showCalendarDialog("Select Start Date", startDateField, w)
+    }
+    startDateField.OnSubmitted = func(_ string) {
+        showCalendarDialog("Select Start Date", startDateField, w)
+    }
+    endDateTap.OnTapped = func() {
+        showCalendarDialog("Select End Date", endDateField, w)
+    }
+    endDateField.OnSubmitted = func(_ string) {
+        showCalendarDialog("Select End Date", endDateField, w)
+    }
+
+    addButton := widget.NewButton("Add Miner", func() {
+        if startDateField.Text == "" {
+            showError(fmt.Errorf("Start date is required"), w)
+            return
+        }
+        if endDateField.Text == "" {
+            showError(fmt.Errorf("End date is required"), w)
+            return
+        }
+        if _, err := time.Parse(dateLayout, startDateField.Text); err != nil {
+            showError(fmt.Errorf("Invalid start date format"), w)
+            return
+        }
+        if _, err := time.Parse(dateLayout, endDateField.Text); err != nil {
+            showError(fmt.Errorf("Invalid end date format"), w)
+            return
+        }
+        tShares, err := parseTShares(tSharesEntry.Text)
+        if err != nil {
+            showError(err, w)
+            return
+        }
+        principal := 0.0
+        if text := sanitizeNumber(principalEntry.Text); text != "" {
+            principal, err = strconv.ParseFloat(text, 64)
+            if err != nil || principal < 0 {
+                showError(fmt.Errorf("Principal HEX must be zero or a positive number"), w)
+                return
+            }
+        }
+        newMiner := Miner{
+            ID:           newMinerID(),
+            StartDate:    startDateField.Text,
+            EndDate:      endDateField.Text,
+            TShares:      tShares,
+            Tags:         parseTags(tagsEntry.Text),
+            PrincipalHEX: principal,
+            Chain:        storedChain(chainByName(minerChainSelect.Selected)),
+        }
+        if err := addStoredMiner(newMiner); err != nil {
+            log.Println("Error saving miners:", err)
+            showError(fmt.Errorf("Failed to save miner"), w)
+            return
+        }
+        refreshTabs()
+    })
+    importButton := widget.NewButton("Import Miners from CSV or JSON", func() {
+        showImportMinersDialog(w, refreshTabs)
+    })
+
+    frequencyEntry := widget.NewEntry()
+    frequencyEntry.SetPlaceHolder("Live Data Update Frequency (minutes)")
+    frequencyEntry.SetText(fmt.Sprintf("%d", configManager.GetLiveDataFrequency()))
+
+    saveFrequencyButton := widget.NewButton("Save Frequency", func() {
+        frequency, err := strconv.Atoi(frequencyEntry.Text)
+        if err != nil || frequency <= 0 {
+            showError(fmt.Errorf("Frequency must be a positive integer"), w)
+            return
+        }
+        err = updateConfig(func(c *Config) {
+            c.LiveDataFrequency = frequency
+        })
+        if err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save frequency"), w)
+            return
+        }
+        dialog.ShowInformation("Success", fmt.Sprintf("Live data update frequency set to %d minutes", frequency), w)
+    })
+
+    penaltyThresholdEntry := widget.NewEntry()
+    penaltyThresholdEntry.SetPlaceHolder("Penalty Warning Threshold (HEX, 0 = off)")
+    penaltyThresholdEntry.SetText(strconv.FormatFloat(configManager.GetConfig().PenaltyWarnThreshold, 'f', -1, 64))
+
+    savePenaltyThresholdButton := widget.NewButton("Save Penalty Threshold", func() {
+        threshold, err := strconv.ParseFloat(sanitizeNumber(penaltyThresholdEntry.Text), 64)
+        if err != nil || threshold < 0 {
+            showError(fmt.Errorf("Penalty threshold must be zero or a positive number"), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.PenaltyWarnThreshold = threshold
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save penalty threshold"), w)
+            return
+        }
+        refreshTabs()
+    })
+
+    valueAlertAboveEntry := widget.NewEntry()
+    valueAlertAboveEntry.SetPlaceHolder("Alert when value rises above (0 = off)")
+    valueAlertAboveEntry.SetText(strconv.FormatFloat(configManager.GetConfig().ValueAlertAbove, 'f', -1, 64))
+    valueAlertBelowEntry := widget.NewEntry()
+    valueAlertBelowEntry.SetPlaceHolder("Alert when value falls below (0 = off)")
+    valueAlertBelowEntry.SetText(strconv.FormatFloat(configManager.GetConfig().ValueAlertBelow, 'f', -1, 64))
+
+    saveValueAlertsButton := widget.NewButton("Save Value Alerts", func() {
+        above, err := strconv.ParseFloat(sanitizeNumber(valueAlertAboveEntry.Text), 64)
+        if err != nil || above < 0 {
+            showError(fmt.Errorf("Alert thresholds must be zero or a positive number"), w)
+            return
+        }
+        below, err := strconv.ParseFloat(sanitizeNumber(valueAlertBelowEntry.Text), 64)
+        if err != nil || below < 0 {
+            showError(fmt.Errorf("Alert thresholds must be zero or a positive number"), w)
+            return
+        }
+        if above > 0 && below > 0 && below >= above {
+            showError(fmt.Errorf("The lower alert must be below the upper alert"), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.ValueAlertAbove = above
+            c.ValueAlertBelow = below
+            c.ValueAlertState = valueAlertNone // New thresholds start unalerted
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save value alerts"), w)
+            return
+        }
+        dialog.ShowInformation("Success", "Value alerts saved", w)
+    })
+
+    currencySelect := widget.NewSelect(currencies, nil)
+    currencySelect.SetSelected(configManager.GetConfig().Currency)
+    currencySelect.OnChanged = func(code string) {
+        if err := updateConfig(func(c *Config) {
+            c.Currency = code
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save currency"), w)
+            return
+        }
+        go func() {
+            refreshFXRate()
+            fyne.Do(refreshTabs)
+        }()
+    }
+
+    compactCheck := widget.NewCheck("Compact large HEX figures (1.23B HEX)", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.CompactNumbers = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            return
+        }
+        refreshTabs()
+    })
+    compactCheck.Checked = configManager.GetConfig().CompactNumbers
+
+    compactDecimalsEntry := widget.NewEntry()
+    compactDecimalsEntry.SetPlaceHolder(fmt.Sprintf("Compact Decimals (0-%d)", maxCompactDecimals))
+    compactDecimalsEntry.SetText(strconv.Itoa(configManager.GetConfig().CompactDecimals))
+
+    saveCompactDecimalsButton := widget.NewButton("Save Compact Decimals", func() {
+        decimals, err := strconv.Atoi(compactDecimalsEntry.Text)
+        if err != nil || decimals < 0 || decimals > maxCompactDecimals {
+            showError(fmt.Errorf("Compact decimals must be an integer between 0 and %d", maxCompactDecimals), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.CompactDecimals = decimals
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save compact decimals"), w)
+            return
+        }
+        refreshTabs()
+    })
+
+    highlightCheck := widget.NewCheck("Highlight live data values when they change", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.HighlightChanges = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    highlightCheck.Checked = configManager.GetConfig().HighlightChanges
+
+    dashboardCheck := widget.NewCheck("Show Dashboard tab", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.ShowDashboard = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        refreshTabs()
+    })
+    dashboardCheck.Checked = configManager.GetConfig().ShowDashboard
+
+    pinnedPriceEntry := widget.NewEntry()
+    pinnedPriceEntry.SetPlaceHolder("Pinned T-Share Price in USD (Profile value, empty = live)")
+    if pinned := configManager.GetConfig().PinnedTsharePrice; pinned > 0 {
+        pinnedPriceEntry.SetText(strconv.FormatFloat(pinned, 'f', -1, 64))
+    }
+    pinPriceButton := widget.NewButton("Pin Price", func() {
+        price, err := strconv.ParseFloat(sanitizeNumber(pinnedPriceEntry.Text), 64)
+        if err != nil || price <= 0 {
+            showError(fmt.Errorf("Pinned price must be a positive number"), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.PinnedTsharePrice = price
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save pinned price"), w)
+            return
+        }
+        refreshTabs()
+    })
+    livePriceButton := widget.NewButton("Use Live Price", func() {
+        if err := updateConfig(func(c *Config) {
+            c.PinnedTsharePrice = 0
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save pinned price"), w)
+            return
+        }
+        refreshTabs()
+    })
+
+    sinceLaunchCheck := widget.NewCheck("Show changes since launch on the Profile and Live Data tabs", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.ShowSinceLaunch = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        refreshTabs()
+    })
+    sinceLaunchCheck.Checked = configManager.GetConfig().ShowSinceLaunch
+
+    rememberTabCheck := widget.NewCheck("Reopen on the last used tab", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.RememberLastTab = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    rememberTabCheck.Checked = configManager.GetConfig().RememberLastTab
+
+    includeCompletedCheck := widget.NewCheck("Count completed miners toward totals (T-Shares, value, gain)", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.IncludeCompletedInTotals = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        refreshTabs()
+    })
+    includeCompletedCheck.Checked = configManager.GetConfig().IncludeCompletedInTotals
+
+    netValueCheck := widget.NewCheck("Show matured stake values net of estimated late penalties", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.ShowNetValue = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        refreshTabs()
+    })
+    netValueCheck.Checked = configManager.GetConfig().ShowNetValue
+
+    keepScreenOnCheck := widget.NewCheck("Keep screen on while the window is focused", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.KeepScreenOn = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            return
+        }
+        screenWake.SetEnabled(checked)
+    })
+    keepScreenOnCheck.Checked = configManager.GetConfig().KeepScreenOn
+    keepScreenOnHelpLabel := widget.NewLabel(keepScreenOnHelp)
+    keepScreenOnHelpLabel.Importance = widget.LowImportance
+    keepScreenOnHelpLabel.Wrapping = fyne.TextWrapWord
+
+    durationSelect := widget.NewSelect(durationFormats, nil)
+    durationSelect.SetSelected(configManager.GetConfig().DurationFormat)
+    durationSelect.OnChanged = func(format string) {
+        if err := updateConfig(func(c *Config) {
+            c.DurationFormat = format
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save duration format"), w)
+            return
+        }
+        refreshTabs()
+    }
+
+    chartWidthEntry := widget.NewEntry()
+    chartWidthEntry.SetPlaceHolder(fmt.Sprintf("Chart Width (px, %d-%d)", minChartDimension, maxChartDimension))
+    chartWidthEntry.SetText(strconv.Itoa(configManager.GetConfig().ChartWidth))
+    chartHeightEntry := widget.NewEntry()
+    chartHeightEntry.SetPlaceHolder(fmt.Sprintf("Chart Height (px, %d-%d)", minChartDimension, maxChartDimension))
+    chartHeightEntry.SetText(strconv.Itoa(configManager.GetConfig().ChartHeight))
+    saveChartSizeButton := widget.NewButton("Save Chart Size", func() {
+        width, err := strconv.Atoi(chartWidthEntry.Text)
+        if err != nil || width < minChartDimension || width > maxChartDimension {
+            showError(fmt.Errorf("Chart width must be an integer between %d and %d", minChartDimension, maxChartDimension), w)
+            return
+        }
+        height, err := strconv.Atoi(chartHeightEntry.Text)
+        if err != nil || height < minChartDimension || height > maxChartDimension {
+            showError(fmt.Errorf("Chart height must be an integer between %d and %d", minChartDimension, maxChartDimension), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.ChartWidth = width
+            c.ChartHeight = height
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save chart size"), w)
+            return
+        }
+        refreshTabs()
+    })
+
+    shortcutSelect := widget.NewSelect(addMinerShortcuts, nil)
+    shortcutSelect.SetSelected(configManager.GetConfig().AddMinerShortcut)
+    shortcutSelect.OnChanged = func(option string) {
+        if err := updateConfig(func(c *Config) {
+            c.AddMinerShortcut = option
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save shortcut"), w)
+            return
+        }
+        refreshTabs()
+    }
+
+    titleSelect := widget.NewSelect(titleMetrics, nil)
+    titleSelect.SetSelected(configManager.GetConfig().TitleMetric)
+    titleSelect.OnChanged = func(metric string) {
+        if err := updateConfig(func(c *Config) {
+            c.TitleMetric = metric
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save window title setting"), w)
+            return
+        }
+        refreshTabs()
+    }
+
+    decimalsEntry := widget.NewEntry()
+    decimalsEntry.SetPlaceHolder("T-Shares Decimals (0-6)")
+    decimalsEntry.SetText(strconv.Itoa(configManager.GetConfig().TSharesDecimals))
+
+    saveDecimalsButton := widget.NewButton("Save Decimals", func() {
+        decimals, err := strconv.Atoi(decimalsEntry.Text)
+        if err != nil || decimals < 0 || decimals > maxTSharesDecimals {
+            showError(fmt.Errorf("Decimals must be an integer between 0 and %d", maxTSharesDecimals), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.TSharesDecimals = decimals
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save decimals"), w)
+            return
+        }
+        refreshTabs()
+    })
+
+    payoutDecimalsEntry := widget.NewEntry()
+    payoutDecimalsEntry.SetPlaceHolder(fmt.Sprintf("Payout Decimals (0-%d)", maxPayoutDecimals))
+    payoutDecimalsEntry.SetText(strconv.Itoa(configManager.GetConfig().PayoutDecimals))
+
+    savePayoutDecimalsButton := widget.NewButton("Save Payout Decimals", func() {
+        decimals, err := strconv.Atoi(payoutDecimalsEntry.Text)
+        if err != nil || decimals < 0 || decimals > maxPayoutDecimals {
+            showError(fmt.Errorf("Payout decimals must be an integer between 0 and %d", maxPayoutDecimals), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.PayoutDecimals = decimals
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save payout decimals"), w)
+            return
+        }
+        refreshTabs()
+    })
 
-    centeredContent := container.NewCenter(content)
+    moneyDecimalsEntry := widget.NewEntry()
+    moneyDecimalsEntry.SetPlaceHolder(fmt.Sprintf("Money Decimals (0-%d, empty = currency default)", maxMoneyDecimals))
+    if decimals := configManager.GetConfig().MoneyDecimals; decimals >= 0 {
+        moneyDecimalsEntry.SetText(strconv.Itoa(decimals))
+    }
+
+    saveMoneyDecimalsButton := widget.NewButton("Save Money Decimals", func() {
+        decimals := -1
+        if text := strings.TrimSpace(moneyDecimalsEntry.Text); text != "" {
+            var err error
+            decimals, err = strconv.Atoi(text)
+            if err != nil || decimals < 0 || decimals > maxMoneyDecimals {
+                showError(fmt.Errorf("Money decimals must be empty or an integer between 0 and %d", maxMoneyDecimals), w)
+                return
+            }
+        }
+        if err := updateConfig(func(c *Config) {
+            c.MoneyDecimals = decimals
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save money decimals"), w)
+            return
+        }
+        refreshTabs()
+    })
+
+    jitterEntry := widget.NewEntry()
+    jitterEntry.SetPlaceHolder("Fetch Jitter (percent, 0-50)")
+    jitterEntry.SetText(strconv.Itoa(configManager.GetConfig().PollJitterPercent))
+
+    saveJitterButton := widget.NewButton("Save Jitter", func() {
+        percent, err := strconv.Atoi(jitterEntry.Text)
+        if err != nil || percent < 0 || percent > maxPollJitterPercent {
+            showError(fmt.Errorf("Jitter must be an integer between 0 and %d", maxPollJitterPercent), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.PollJitterPercent = percent
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save jitter"), w)
+        }
+    })
+
+    streamCheck := widget.NewCheck("Use live data stream (restart to apply)", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.LiveDataStream = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    streamCheck.Checked = configManager.GetConfig().LiveDataStream
+
+    streamURLEntry := widget.NewEntry()
+    streamURLEntry.SetPlaceHolder("Live Data Stream URL (Server-Sent Events)")
+    streamURLEntry.SetText(configManager.GetConfig().LiveDataStreamURL)
+
+    saveStreamURLButton := widget.NewButton("Save Stream URL", func() {
+        url := strings.TrimSpace(streamURLEntry.Text)
+        if url != "" && !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+            showError(fmt.Errorf("Stream URL must start with http:// or https://"), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.LiveDataStreamURL = url
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save stream URL"), w)
+        }
+    })
+
+    maxRequestsEntry := widget.NewEntry()
+    maxRequestsEntry.SetPlaceHolder("Max Concurrent Requests")
+    maxRequestsEntry.SetText(strconv.Itoa(configManager.GetConfig().MaxConcurrentRequests))
+
+    saveMaxRequestsButton := widget.NewButton("Save Max Requests", func() {
+        limit, err := strconv.Atoi(maxRequestsEntry.Text)
+        if err != nil || limit <= 0 {
+            showError(fmt.Errorf("Max concurrent requests must be a positive integer"), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.MaxConcurrentRequests = limit
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save max requests"), w)
+        }
+    })
+
+    resumeCheck := widget.NewCheck("Refresh live data after resuming from sleep", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.RefreshOnResume = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    resumeCheck.Checked = configManager.GetConfig().RefreshOnResume
+
+    pauseCheck := widget.NewCheck("Pause fetching while the window is inactive", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.PauseWhenInactive = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    pauseCheck.Checked = configManager.GetConfig().PauseWhenInactive
+
+    pauseAfterEntry := widget.NewEntry()
+    pauseAfterEntry.SetPlaceHolder("Pause After (minutes inactive)")
+    pauseAfterEntry.SetText(strconv.Itoa(configManager.GetConfig().PauseAfterMinutes))
+
+    savePauseAfterButton := widget.NewButton("Save Pause Delay", func() {
+        minutes, err := strconv.Atoi(pauseAfterEntry.Text)
+        if err != nil || minutes <= 0 {
+            showError(fmt.Errorf("Pause delay must be a positive integer"), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.PauseAfterMinutes = minutes
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save pause delay"), w)
+        }
+    })
+
+    startupDelayEntry := widget.NewEntry()
+    startupDelayEntry.SetPlaceHolder(fmt.Sprintf("Startup Delay (seconds before first fetch, 0-%d)", maxStartupDelaySeconds))
+    startupDelayEntry.SetText(strconv.Itoa(configManager.GetConfig().StartupDelaySeconds))
+
+    saveStartupDelayButton := widget.NewButton("Save Startup Delay", func() {
+        seconds, err := strconv.Atoi(startupDelayEntry.Text)
+        if err != nil || seconds < 0 || seconds > maxStartupDelaySeconds {
+            showError(fmt.Errorf("Startup delay must be between 0 and %d seconds", maxStartupDelaySeconds), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.StartupDelaySeconds = seconds
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save startup delay"), w)
+        }
+    })
+
+    historySyncEntry := widget.NewEntry()
+    historySyncEntry.SetPlaceHolder("Sync History Every N Fetches (0 = only at startup)")
+    historySyncEntry.SetText(strconv.Itoa(configManager.GetConfig().HistorySyncEveryNFetches))
+
+    saveHistorySyncButton := widget.NewButton("Save History Sync", func() {
+        every, err := strconv.Atoi(historySyncEntry.Text)
+        if err != nil || every < 0 {
+            showError(fmt.Errorf("History sync interval must be zero or a positive integer"), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.HistorySyncEveryNFetches = every
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save history sync interval"), w)
+        }
+    })
+
+    stakeDaysEntry := widget.NewEntry()
+    stakeDaysEntry.SetPlaceHolder(fmt.Sprintf("Default Stake Length (days, 0-%d, 0 = off)", maxStakeDays))
+    stakeDaysEntry.SetText(strconv.Itoa(configManager.GetConfig().DefaultStakeDays))
+
+    saveStakeDaysButton := widget.NewButton("Save Stake Length", func() {
+        days, err := strconv.Atoi(stakeDaysEntry.Text)
+        if err != nil || days < 0 || days > maxStakeDays {
+            showError(fmt.Errorf("Stake length must be an integer between 0 and %d", maxStakeDays), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.DefaultStakeDays = days
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save stake length"), w)
+        }
+    })
+
+    autoEndCheck := widget.NewCheck("Prompt to end stakes when they mature", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.AutoEndPrompt = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    autoEndCheck.Checked = configManager.GetConfig().AutoEndPrompt
+    maturityNotifyCheck := widget.NewCheck("Notify when stakes mature and before their grace period ends", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.MaturityNotifications = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    maturityNotifyCheck.Checked = configManager.GetConfig().MaturityNotifications
+    trayCheck := widget.NewCheck("Show matured stakes in the system tray (restart to apply)", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.TrayAlerts = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    trayCheck.Checked = configManager.GetConfig().TrayAlerts
+    trayModeCheck := widget.NewCheck("Run in the system tray with the prices in its menu, closing the window keeps the app running (restart to apply)", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.TrayMode = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    trayModeCheck.Checked = configManager.GetConfig().TrayMode
+
+    // Risky, so turning it on has to be confirmed
+    var autoEndMaturedCheck *widget.Check
+    saveAutoEndMatured := func(enabled bool) {
+        if err := updateConfig(func(c *Config) {
+            c.AutoEndMatured = enabled
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    }
+    autoEndMaturedCheck = widget.NewCheck("Automatically mark matured stakes as ended (advanced)", func(checked bool) {
+        if !checked {
+            saveAutoEndMatured(false)
+            return
+        }
+        dialog.ShowConfirm("Automatically End Stakes", "Matured stakes will be marked as ended in the app without asking, with the yield at the payout of that moment. This doesn't end them on chain - you still have to do that yourself to avoid late penalties. Turn it on?", func(yes bool) {
+            if !yes {
+                autoEndMaturedCheck.SetChecked(false)
+                return
+            }
+            saveAutoEndMatured(true)
+        }, w)
+    })
+    autoEndMaturedCheck.Checked = configManager.GetConfig().AutoEndMatured
+    autoEndMaturedHelp := widget.NewLabel("Ended stakes are reported as alerts. Use it only if you end stakes on chain as soon as they mature.")
+    autoEndMaturedHelp.Importance = widget.WarningImportance
+    autoEndMaturedHelp.Wrapping = fyne.TextWrapWord
+
+    sharedURLEntry := widget.NewEntry()
+    sharedURLEntry.SetPlaceHolder("Shared Portfolio URL (miners JSON)")
+    sharedURLEntry.SetText(configManager.GetConfig().SharedPortfolioURL)
+
+    openSharedButton := widget.NewButton("Open Shared Portfolio", func() {
+        url := strings.TrimSpace(sharedURLEntry.Text)
+        if !strings.HasPrefix(url, "http://") && !strings.HasPrefix(url, "https://") {
+            showError(fmt.Errorf("Shared portfolio URL must start with http:// or https://"), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.SharedPortfolioURL = url
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        showSharedPortfolioWindow(url, w)
+    })
+
+    walletEntry := widget.NewEntry()
+    walletEntry.SetPlaceHolder("PulseChain wallet address (0x...)")
+    walletEntry.SetText(configManager.GetConfig().WalletAddress)
+    rpcURLEntry := widget.NewEntry()
+    rpcURLEntry.SetPlaceHolder("PulseChain RPC URL")
+    rpcURLEntry.SetText(configManager.GetConfig().RPCURL)
+    var walletImportButton *widget.Button
+    walletImportButton = widget.NewButton("Import Stakes From Wallet", func() {
+        address := strings.TrimSpace(walletEntry.Text)
+        if !isWalletAddress(address) {
+            showError(fmt.Errorf("Wallet address must be 0x followed by 40 hex digits"), w)
+            return
+        }
+        rpcURL, err := validateRPCURL(rpcURLEntry.Text)
+        if err != nil {
+            showError(fmt.Errorf("Invalid RPC URL: %v", err), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.WalletAddress = address
+            c.RPCURL = rpcURL
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        walletImportButton.SetText("Importing…")
+        walletImportButton.Disable()
+        go func() {
+            stakes, err := fetchWalletStakes(rpcURL, address)
+            existing, loadErr := loadMiners()
+            fyne.Do(func() {
+                walletImportButton.SetText("Import Stakes From Wallet")
+                walletImportButton.Enable()
+                if err != nil {
+                    log.Println("Error fetching wallet stakes:", err)
+                    showError(fmt.Errorf("Failed to read stakes: %v", err), w)
+                    return
+                }
+                if loadErr != nil {
+                    log.Println("Error loading miners:", loadErr)
+                    showError(fmt.Errorf("Failed to load miners"), w)
+                    return
+                }
+                if len(stakes) == 0 {
+                    dialog.ShowInformation("Import Stakes", "The wallet has no active stakes", w)
+                    return
+                }
+                showImportPreview(walletImportRows(stakes, existing), w, refreshTabs)
+            })
+        }()
+    })
+
+    updateCheck := widget.NewCheck("Check for a new version at startup", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.UpdateCheck = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    updateCheck.Checked = configManager.GetConfig().UpdateCheck
+    offlineCheck := widget.NewCheck("Offline mode (no network requests, the stored history and miners are still shown)", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.OfflineMode = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            return
+        }
+        if !checked { // Catch up at once instead of at the next tick
+            requestLiveFetch()
+            go syncHistory()
+        }
+    })
+    offlineCheck.Checked = configManager.GetConfig().OfflineMode
+
+    portfolioSelect := newPortfolioSelect(w, refreshTabs)
+    managePortfoliosButton := widget.NewButton("Manage Portfolios", func() {
+        showPortfolioManager(w, refreshTabs)
+    })
+
+    archiveDaysEntry := widget.NewEntry()
+    archiveDaysEntry.SetPlaceHolder("Archive completed miners ended more than N days ago (0 = off)")
+    archiveDaysEntry.SetText(strconv.Itoa(configManager.GetConfig().ArchiveAfterDays))
+    saveArchiveDaysButton := widget.NewButton("Save Archive Age", func() {
+        days, err := strconv.Atoi(archiveDaysEntry.Text)
+        if err != nil || days < 0 || days > maxArchiveAfterDays {
+            showError(fmt.Errorf("Archive age must be an integer between 0 and %d", maxArchiveAfterDays), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.ArchiveAfterDays = days
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save archive age"), w)
+            return
+        }
+        archivedCount, err := archiveOldMiners(time.Now())
+        if err != nil {
+            log.Println("Error archiving miners:", err)
+            showError(fmt.Errorf("Failed to archive miners"), w)
+            return
+        }
+        if archivedCount > 0 {
+            dialog.ShowInformation("Miners Archived", fmt.Sprintf("Archived %d completed miners", archivedCount), w)
+        }
+        refreshTabs()
+    })
+    includeArchivedCheck := widget.NewCheck("Count archived miners toward totals", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.IncludeArchivedInTotals = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        refreshTabs()
+    })
+    includeArchivedCheck.Checked = configManager.GetConfig().IncludeArchivedInTotals
+
+    journalCheck := widget.NewCheck("Journal miner changes (recovers them after a crash)", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.MinerJournal = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            return
+        }
+        if !checked {
+            os.Remove(minersJournalPath())
+            return
+        }
+        // Start the journal from the current miners
+        current, err := loadMiners()
+        if err != nil {
+            log.Println("Error loading miners:", err)
+            return
+        }
+        journalCheckpoint(current)
+    })
+    journalCheck.Checked = configManager.GetConfig().MinerJournal
+
+    strictDecodeCheck := widget.NewCheck("Warn when the API format changes (strict decode)", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.StrictDecode = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        if !checked {
+            clearSchemaDrift()
+        }
+    })
+    strictDecodeCheck.Checked = configManager.GetConfig().StrictDecode
+
+    inAppAlertsCheck := widget.NewCheck("Show alerts in the app instead of as system notifications", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.InAppAlerts = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
+    })
+    inAppAlertsCheck.Checked = configManager.GetConfig().InAppAlerts
+
+    quietStatusLabel := widget.NewLabel(quietStatusText(configManager.GetConfig(), time.Now()))
+    quietStatusLabel.Importance = widget.LowImportance
+    quietSelect := widget.NewSelect(quietOptions, func(option string) {
+        if err := setQuietMode(option, time.Now()); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save quiet mode"), w)
+            return
+        }
+        quietStatusLabel.SetText(quietStatusText(configManager.GetConfig(), time.Now()))
+    })
+    quietSelect.PlaceHolder = "On"
+    quietSelect.Selected = quietSelection(configManager.GetConfig(), time.Now())
+    notificationsNoteLabel := widget.NewLabel(notificationsUnavailableNote)
+    notificationsNoteLabel.Importance = widget.WarningImportance
+    if notificationsAvailable(runtime.GOOS, os.Getenv) {
+        notificationsNoteLabel.Hide()
+    }
 
-    return centeredContent
-}
+    fetchHistoryEntry := widget.NewEntry()
+    fetchHistoryEntry.SetPlaceHolder(fmt.Sprintf("Fetch History Size (1-%d)", maxFetchHistorySize))
+    fetchHistoryEntry.SetText(strconv.Itoa(configManager.GetConfig().FetchHistorySize))
 
-func createChartTab() fyne.CanvasObject {
-    selectField := widget.NewSelect([]string{"pricePulseX", "tshareRateHEX", "dailyPayoutHEX"}, nil)
-    chartImage := canvas.NewImageFromFile("") // Placeholder
-    chartImage.FillMode = canvas.ImageFillContain
-    chartImage.SetMinSize(fyne.NewSize(600, 400))
+    saveFetchHistoryButton := widget.NewButton("Save Fetch History Size", func() {
+        size, err := strconv.Atoi(fetchHistoryEntry.Text)
+        if err != nil || size <= 0 || size > maxFetchHistorySize {
+            showError(fmt.Errorf("Fetch history size must be between 1 and %d", maxFetchHistorySize), w)
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.FetchHistorySize = size
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            showError(fmt.Errorf("Failed to save fetch history size"), w)
+        }
+    })
+    connectionButton := widget.NewButton("Connection History", showConnectionWindow)
 
-    container := container.NewBorder(selectField, nil, nil, nil, chartImage)
+    apiURLEntry := widget.NewEntry()
+    apiURLEntry.SetPlaceHolder("API Base URL")
+    apiURLEntry.SetText(configManager.GetConfig().APIBaseURL)
 
-    updateChart := func(field string) {
-        data, err := loadLocalHEXJSON()
+    saveAPIURLButton := widget.NewButton("Save API URL", func() {
+        apiURL, err := validateAPIBaseURL(apiURLEntry.Text)
         if err != nil {
-            log.Println("Error loading HEXJSON:", err)
-            return
-        }
-        if len(data) == 0 {
-            chartImage.Resource = nil
-            chartImage.Refresh()
-            return
-        }
-        graph := chart.Chart{
-            XAxis: chart.XAxis{Name: "Current Day"},
-            YAxis: chart.YAxis{Name: field},
-            Series: []chart.Series{
-                chart.ContinuousSeries{
-                    XValues: make([]float64, len(data)),
-                    YValues: make([]float64, len(data)),
-                },
-            },
-        }
-        for i, entry := range data {
-            graph.Series[0].(chart.ContinuousSeries).XValues[i] = float64(entry.CurrentDay)
-            switch field {
-            case "pricePulseX":
-                graph.Series[0].(chart.ContinuousSeries).YValues[i] = entry.PricePulseX
-            case "tshareRateHEX":
-                graph.Series[0].(chart.ContinuousSeries).YValues[i] = entry.TshareRateHEX
-            case "dailyPayoutHEX":
-                graph.Series[0].(chart.ContinuousSeries).YValues[i] = entry.DailyPayoutHEX
+            showError(fmt.Errorf("Invalid API URL: %v", err), w)
+            return
+        }
+        save := func() {
+            if err := updateConfig(func(c *Config) {
+                c.APIBaseURL = apiURL
+            }); err != nil {
+                log.Println("Error saving config:", err)
+                showError(fmt.Errorf("Failed to save API URL"), w)
+                return
             }
+            apiURLEntry.SetText(apiURL)
         }
-        buffer := bytes.NewBuffer(nil)
-        err = graph.Render(chart.PNG, buffer)
-        if err != nil {
-            log.Println("Error rendering chart:", err)
+        if apiURL == defaultAPIBaseURL || apiURL == configManager.GetConfig().APIBaseURL {
+            save()
             return
         }
-        chartImage.Resource = fyne.NewStaticResource("chart", buffer.Bytes())
-        chartImage.Refresh()
-    }
-
-    selectField.OnChanged = updateChart
-    updateChart("pricePulseX") // Default
-
-    return container
-}
-
-func createSettingsTab(miners []Miner, w fyne.Window, refreshTabs func()) fyne.CanvasObject {
-    localMiners := miners
-    startDateField := widget.NewEntry()
-    startDateField.SetPlaceHolder("Click to select Start Date")
-    startDateTap := widget.NewButton("", nil)
-    startDateTap.Importance = widget.LowImportance
-    startDateContainer := container.NewStack(startDateField, startDateTap)
-    endDateField := widget.NewEntry()
-    endDateField.SetPlaceHolder("Click to select End Date")
-    endDateTap := widget.NewButton("", nil)
-    endDateTap.Importance = widget.LowImportance
-    endDateContainer := container.NewStack(endDateField, endDateTap)
-    tSharesEntry := widget.NewEntry()
-    tSharesEntry.SetPlaceHolder("T-Shares")
+        // All prices and values come from this host, a wrong one shows wrong numbers
+        message := fmt.Sprintf("Fetch all data from %s instead of %s?\n\nOnly use an address you trust, the numbers shown will come from it.", apiURL, defaultAPIBaseURL)
+        dialog.ShowConfirm("Change API URL", message, func(yes bool) {
+            if yes {
+                save()
+            }
+        }, w)
+    })
 
-    tSharesEntry.Validator = func(s string) error {
-        if s == "" {
-            return fmt.Errorf("T-Shares is required")
-        }
-        val, err := strconv.ParseFloat(s, 64)
+    fallbackURLsEntry := widget.NewMultiLineEntry()
+    fallbackURLsEntry.SetPlaceHolder("Fallback API URLs, one per line (tried in order when the API URL fails)")
+    fallbackURLsEntry.SetText(strings.Join(configManager.GetConfig().FallbackAPIURLs, "\n"))
+    fallbackURLsEntry.SetMinRowsVisible(3)
+    saveFallbackURLsButton := widget.NewButton("Save Fallback URLs", func() {
+        urls, err := parseFallbackAPIURLs(fallbackURLsEntry.Text)
         if err != nil {
-            return fmt.Errorf("T-Shares must be a valid number")
+            showError(fmt.Errorf("Invalid fallback URL: %v", err), w)
+            return
         }
-        if val <= 0 {
-            return fmt.Errorf("T-Shares must be positive number")
+        save := func() {
+            if err := updateConfig(func(c *Config) {
+                c.FallbackAPIURLs = urls
+            }); err != nil {
+                log.Println("Error saving config:", err)
+                showError(fmt.Errorf("Failed to save fallback URLs"), w)
+                return
+            }
+            fallbackURLsEntry.SetText(strings.Join(urls, "\n"))
+            sourceHealthUpdated.Notify() // The Live Data tab lists the new sources
         }
-        return nil
-    }
-
-    showCalendarDialog := func(title string, field *widget.Entry, w fyne.Window) {
-        now := time.Now()
-        selectedDate := now
-        if field.Text != "" {
-            if parsed, err := time.Parse(dateLayout, field.Text); err == nil {
-                selectedDate = parsed
+        var added []string
+        for _, url := range urls {
+            if !contains(configManager.GetConfig().FallbackAPIURLs, url) && url != defaultAPIBaseURL {
+                added = append(added, url)
             }
         }
-
-        years := make([]string, 0, 11)
-        for y := 2019; y <= (now.AddDate(15, 2, 20).Year()); y++ {
-            years = append(years, strconv.Itoa(y))
+        if len(added) == 0 {
+            save()
+            return
         }
-        yearSelect := widget.NewSelect(years, nil)
-        yearSelect.SetSelected(strconv.Itoa(selectedDate.Year()))
+        // Same trust question as for the API URL, the numbers may come from these
+        message := fmt.Sprintf("Fetch data from %s when the API URL fails?\n\nOnly use addresses you trust, the numbers shown will come from them.", strings.Join(added, ", "))
+        dialog.ShowConfirm("Add Fallback URLs", message, func(yes bool) {
+            if yes {
+                save()
+            }
+        }, w)
+    })
 
-        months := []string{
-            "January", "February", "March", "April", "May", "June",
-            "July", "August", "September", "October", "November", "December",
-        }
-        monthSelect := widget.NewSelect(months, nil)
-        monthSelect.SetSelected(months[selectedDate.Month()-1])
+    resetButton := widget.NewButton("Reset Settings to Defaults", func() {
+        dialog.ShowConfirm("Reset Settings", "Reset all settings to their defaults? Miners are kept.", func(yes bool) {
+            if !yes {
+                return
+            }
+            if err := resetConfig(); err != nil {
+                log.Println("Error saving config:", err)
+                showError(fmt.Errorf("Failed to reset settings"), w)
+                return
+            }
+            screenWake.SetEnabled(configManager.GetConfig().KeepScreenOn)
+            go func() {
+                refreshFXRate()
+                fyne.Do(refreshTabs)
+            }()
+        }, w)
+    })
 
-        days := make([]string, 0, 31)
-        for d := 1; d <= 31; d++ {
-            days = append(days, strconv.Itoa(d))
-        }
-        daySelect := widget.NewSelect(days, nil)
-        daySelect.SetSelected(strconv.Itoa(selectedDate.Day()))
+    // Actions take the miner ID, never a list index, so they hit the right miner in either layout
+    confirmDelete := func(id string) {
+        dialog.ShowConfirm("Delete Miner", "Do you want to delete this HEX miner?", func(yes bool) {
+            if yes {
+                if err := deleteStoredMiner(id); err != nil {
+                    log.Println("Error saving miners:", err)
+                    showError(fmt.Errorf("Failed to delete miner"), w)
+                    return
+                }
+                refreshTabs()
+            }
+        }, w)
+    }
 
-        form := &widget.Form{
-            Items: []*widget.FormItem{
-                {Text: "Year", Widget: yearSelect},
-                {Text: "Month", Widget: monthSelect},
-                {Text: "Day", Widget: daySelect},
-            },
-            SubmitText: "Confirm",
-            CancelText: "Cancel",
+    compactList := configManager.GetConfig().CompactMinerList
+    compactListCheck := widget.NewCheck("Compact list", func(checked bool) {
+        if err := updateConfig(func(c *Config) {
+            c.CompactMinerList = checked
+        }); err != nil {
+            log.Println("Error saving config:", err)
         }
+        refreshTabs()
+    })
+    compactListCheck.Checked = compactList
 
-        d := dialog.NewCustomWithoutButtons(title, container.NewVBox(
-            form,
-        ), w)
-        form.OnSubmit = func() {
-            year, _ := strconv.Atoi(yearSelect.Selected)
-            monthIndex := 0
-            for i, m := range months {
-                if m == monthSelect.Selected {
-                    monthIndex = i + 1
-                    break
-                }
-            }
-            day, _ := strconv.Atoi(daySelect.Selected)
+    // Pagination for Existing Miners, the compact table fits more per page
+    itemsPerPage := 5
+    if compactList {
+        itemsPerPage = 15
+    }
 
-            date, err := time.Parse("2006-1-2", fmt.Sprintf("%d-%d-%d", year, monthIndex, day))
-            if err != nil {
-                dialog.ShowError(fmt.Errorf("Invalid date: %s %s, %s", monthSelect.Selected, daySelect.Selected, yearSelect.Selected), w)
-                return
-            }
+    minersList := container.NewVBox()
+    marks := duplicateMarks(localMiners)
 
-            field.SetText(date.Format(dateLayout))
-            field.Refresh()
-            d.Hide()
+    updateMinersList := func(startIndex, endIndex int) {
+        minersList.Objects = nil
+        if compactList {
+            minersList.Add(compactMinerTable(localMiners[startIndex:endIndex], marks, func(miner Miner) {
+                showEditMinerDialog(miner, w, refreshTabs)
+            }, confirmDelete))
+            minersList.Refresh()
+            return
         }
-        form.OnCancel = func() {
-            d.Hide()
+        for i := startIndex; i < endIndex; i++ {
+            miner := localMiners[i]
+            id := miner.ID
+            editButton := widget.NewButton("Edit", func() {
+                showEditMinerDialog(miner, w, refreshTabs)
+            })
+            deleteButton := widget.NewButton("Delete", func() {
+                confirmDelete(id)
+            })
+            minerLabel := widget.NewLabel(fmt.Sprintf("Start: %s, End: %s, T-Shares: %s%s", localMiners[i].StartDate, localMiners[i].EndDate, formatTShares(localMiners[i].TShares), marks[localMiners[i].ID]))
+            minersList.Add(container.NewHBox(withTagChips(minerLabel, localMiners[i].Tags), editButton, deleteButton))
         }
-        d.Show()
+        minersList.Refresh()
     }
 
-    startDateTap.OnTapped = func() {
-        showCalendarDialog("Select Start Date", startDateField, w)
-    }
-    startDateField.OnSubmitted = func(_ string) {
-        showCalendarDialog("Select Start Date", startDateField, w)
+    navBar := newPageNav(len(localMiners), itemsPerPage, updateMinersList).Bar
+
+    scroll := container.NewVScroll(container.NewVBox(
+        widget.NewLabel("Live Data Settings"),
+        offlineCheck,
+        frequencyEntry,
+        saveFrequencyButton,
+        penaltyThresholdEntry,
+        savePenaltyThresholdButton,
+        widget.NewLabel("Portfolio value alerts (in the display currency)"),
+        valueAlertAboveEntry,
+        valueAlertBelowEntry,
+        saveValueAlertsButton,
+        jitterEntry,
+        saveJitterButton,
+        maxRequestsEntry,
+        saveMaxRequestsButton,
+        streamCheck,
+        streamURLEntry,
+        saveStreamURLButton,
+        resumeCheck,
+        pauseCheck,
+        pauseAfterEntry,
+        savePauseAfterButton,
+        startupDelayEntry,
+        saveStartupDelayButton,
+        historySyncEntry,
+        saveHistorySyncButton,
+        widget.NewLabel("Display Settings"),
+        container.New(layout.NewFormLayout(),
+            widget.NewLabel("Currency"), currencySelect,
+            widget.NewLabel("Days Left Format"), durationSelect,
+            widget.NewLabel("Window Title"), titleSelect,
+            widget.NewLabel("Add Miner Shortcut"), shortcutSelect,
+        ),
+        decimalsEntry,
+        saveDecimalsButton,
+        moneyDecimalsEntry,
+        saveMoneyDecimalsButton,
+        payoutDecimalsEntry,
+        savePayoutDecimalsButton,
+        compactCheck,
+        compactDecimalsEntry,
+        saveCompactDecimalsButton,
+        chartWidthEntry,
+        chartHeightEntry,
+        saveChartSizeButton,
+        highlightCheck,
+        includeCompletedCheck,
+        netValueCheck,
+        pinnedPriceEntry,
+        container.NewHBox(pinPriceButton, livePriceButton),
+        dashboardCheck,
+        sinceLaunchCheck,
+        rememberTabCheck,
+        keepScreenOnCheck,
+        keepScreenOnHelpLabel,
+        widget.NewLabel("Maturity Settings"),
+        autoEndCheck,
+        maturityNotifyCheck,
+        trayCheck,
+        trayModeCheck,
+        autoEndMaturedCheck,
+        autoEndMaturedHelp,
+        stakeDaysEntry,
+        saveStakeDaysButton,
+        widget.NewLabel("Updates"),
+        updateCheck,
+        widget.NewLabel("Portfolios"),
+        container.New(layout.NewFormLayout(), widget.NewLabel("Active Portfolio"), portfolioSelect),
+        managePortfoliosButton,
+        widget.NewLabel("Import From Wallet"),
+        walletEntry,
+        rpcURLEntry,
+        walletImportButton,
+        widget.NewLabel("Shared Portfolio"),
+        sharedURLEntry,
+        openSharedButton,
+        widget.NewLabel("Advanced"),
+        journalCheck,
+        archiveDaysEntry,
+        saveArchiveDaysButton,
+        includeArchivedCheck,
+        strictDecodeCheck,
+        inAppAlertsCheck,
+        notificationsNoteLabel,
+        container.New(layout.NewFormLayout(), widget.NewLabel("Quiet Mode"), quietSelect),
+        quietStatusLabel,
+        connectionButton,
+        fetchHistoryEntry,
+        saveFetchHistoryButton,
+        apiURLEntry,
+        saveAPIURLButton,
+        fallbackURLsEntry,
+        saveFallbackURLsButton,
+        resetButton,
+        widget.NewLabel("Add New Miner"),
+        startDateContainer,
+        endDateContainer,
+        tSharesEntry,
+        principalEntry,
+        tagsEntry,
+        container.NewHBox(widget.NewLabel("Chain"), minerChainSelect),
+        addButton,
+        importButton,
+        widget.NewLabel("Existing Miners"),
+        compactListCheck,
+        newUndoEditButton(w, refreshTabs),
+        minersList,
+        navBar,
+    ))
+    focusNewMinerForm = func() {
+        scroll.ScrollToOffset(fyne.NewPos(0, startDateContainer.Position().Y))
+        w.Canvas().Focus(startDateTap)
     }
-    endDateTap.OnTapped = func() {
-        showCalendarDialog("Select End Date", endDateField, w)
+    return scroll
+}
+
+// Active miners at launch and how many of them matured without being ended
+type maturitySummary struct {
+    Active  int
+    Matured int
+}
+
+func reconcileMaturity(miners []Miner, now time.Time) maturitySummary {
+    var summary maturitySummary
+    for _, miner := range miners {
+        state, err := minerState(miner, now)
+        if state == stakeCompleted {
+            continue
+        }
+        summary.Active++
+        if err == nil && state.Matured() {
+            summary.Matured++
+        }
     }
-    endDateField.OnSubmitted = func(_ string) {
-        showCalendarDialog("Select End Date", endDateField, w)
+    return summary
+}
+
+func maturitySummaryMessage(summary maturitySummary) string {
+    if summary.Matured == 1 {
+        return "1 stake matured while you were away and is waiting to be ended."
     }
+    return fmt.Sprintf("%d stakes matured while you were away and are waiting to be ended.", summary.Matured)
+}
 
-    addButton := widget.NewButton("Add Miner", func() {
-        if startDateField.Text == "" {
-            dialog.ShowError(fmt.Errorf("Start date is required"), w)
+// Periodically prompts to end stakes that matured while the app is running
+func startMaturityWatcher(w fyne.Window, refreshTabs func()) {
+    // Runs on every fetch as well, the realized yield needs a fetched payout
+    autoEnd := func() {
+        ended, err := endMaturedMiners(time.Now())
+        if err != nil {
+            log.Println("Error ending matured stakes:", err)
             return
         }
-        if endDateField.Text == "" {
-            dialog.ShowError(fmt.Errorf("End date is required"), w)
+        if len(ended) == 0 {
             return
         }
-        if _, err := time.Parse(dateLayout, startDateField.Text); err != nil {
-            dialog.ShowError(fmt.Errorf("Invalid start date format"), w)
-            return
+        for _, miner := range ended {
+            log.Println("Ended matured stake automatically:", miner.StartDate, "-", miner.EndDate)
+            notify("Stake Ended", fmt.Sprintf("Stake %s - %s (%s T-Shares) matured and was marked as ended", miner.StartDate, miner.EndDate, formatTShares(miner.TShares)))
         }
-        if _, err := time.Parse(dateLayout, endDateField.Text); err != nil {
-            dialog.ShowError(fmt.Errorf("Invalid end date format"), w)
+        fyne.Do(refreshTabs)
+    }
+    // Sent independently of the prompt, which only works while the app is looked at
+    notifyMaturities := func() {
+        if !configManager.GetConfig().MaturityNotifications {
             return
         }
-        if tSharesEntry.Text == "" {
-            dialog.ShowError(fmt.Errorf("T-Shares is required"), w)
+        now := time.Now()
+        notices, err := takeMaturityNotices(now)
+        if err != nil {
+            log.Println("Error saving maturity notices:", err)
             return
         }
-        if err := tSharesEntry.Validate(); err != nil {
-            dialog.ShowError(err, w)
-            return
+        if len(notices.Matured) > 0 {
+            notify(maturedNotification(notices.Matured))
+        }
+        if len(notices.GraceEnding) > 0 {
+            notify(graceEndingNotification(notices.GraceEnding, now))
         }
-        tShares, err := strconv.ParseFloat(tSharesEntry.Text, 64)
+    }
+    updateTray := func() {
+        miners, err := loadMiners()
         if err != nil {
-            dialog.ShowError(fmt.Errorf("Invalid T-Shares: %v", err), w)
+            log.Println("Error loading miners:", err)
             return
         }
-        newMiner := Miner{
-            StartDate: startDateField.Text,
-            EndDate:   endDateField.Text,
-            TShares:   tShares,
-        }
-        localMiners = append(localMiners, newMiner)
-        if err := saveMiners(localMiners); err != nil {
-            log.Println("Error saving miners:", err)
-        }
-        refreshTabs()
-    })
-
-    frequencyEntry := widget.NewEntry()
-    frequencyEntry.SetPlaceHolder("Live Data Update Frequency (minutes)")
-    frequencyEntry.SetText(fmt.Sprintf("%d", configManager.GetLiveDataFrequency()))
-
-    saveFrequencyButton := widget.NewButton("Save Frequency", func() {
-        frequency, err := strconv.Atoi(frequencyEntry.Text)
-        if err != nil || frequency <= 0 {
-            dialog.ShowError(fmt.Errorf("Frequency must be a positive integer"), w)
+        count := maturedCount(miners, time.Now())
+        fyne.Do(func() {
+            setTrayMatured(w, count)
+        })
+    }
+    check := func() {
+        // Auto-end takes matured stakes without asking
+        if !configManager.GetConfig().AutoEndPrompt || configManager.GetConfig().AutoEndMatured {
             return
         }
-        config := Config{LiveDataFrequency: frequency}
-        if err := saveConfig(config); err != nil {
-            log.Println("Error saving config:", err)
-            dialog.ShowError(fmt.Errorf("Failed to save frequency"), w)
+        // Stakes stay unannounced in quiet mode, so they are asked about once it ends
+        if quietActive(configManager.GetConfig(), time.Now()) {
             return
         }
-        configManager.SetLiveDataFrequency(frequency)
-        dialog.ShowInformation("Success", fmt.Sprintf("Live data update frequency set to %d minutes", frequency), w)
-    })
-
-    // Pagination for Existing Miners
-    const itemsPerPage = 5
-    totalPages := (len(localMiners) + itemsPerPage - 1) / itemsPerPage
-    currentPage := 1
-
-    minersList := container.NewVBox()
-    pageLabel := widget.NewLabel(fmt.Sprintf("Page %d of %d", currentPage, totalPages))
-
-    updateMinersList := func() {
-        minersList.Objects = nil
-        startIndex := (currentPage - 1) * itemsPerPage
-        endIndex := startIndex + itemsPerPage
-        if endIndex > len(localMiners) {
-            endIndex = len(localMiners)
+        newlyMatured, err := takeNewlyMatured(time.Now())
+        if err != nil {
+            log.Println("Error saving miners:", err)
+            return
         }
-        for i := startIndex; i < endIndex; i++ {
-            idx := i
-            deleteButton := widget.NewButton("Delete", func() {
-                dialog.ShowConfirm("Delete Miner", "Do you want to delete this HEX miner?", func(yes bool) {
-                    if yes {
-                        localMiners = append(localMiners[:idx], localMiners[idx+1:]...)
-                        if err := saveMiners(localMiners); err != nil {
-                            log.Println("Error saving miners:", err)
-                        }
-                        refreshTabs()
+        for _, miner := range newlyMatured {
+            miner := miner
+            fyne.Do(func() {
+                message := fmt.Sprintf("Stake %s - %s (%s T-Shares) matured - mark as ended?", miner.StartDate, miner.EndDate, formatTShares(miner.TShares))
+                dialog.ShowConfirm("Stake Matured", message, func(yes bool) {
+                    if !yes {
+                        return
                     }
+                    if err := completeMinerByID(miner.ID); err != nil {
+                        log.Println("Error saving miners:", err)
+                    }
+                    refreshTabs()
                 }, w)
             })
-            minerLabel := widget.NewLabel(fmt.Sprintf("Start: %s, End: %s, T-Shares: %.2f", localMiners[i].StartDate, localMiners[i].EndDate, localMiners[i].TShares))
-            minersList.Add(container.NewHBox(minerLabel, deleteButton))
         }
-        pageLabel.SetText(fmt.Sprintf("Page %d of %d", currentPage, totalPages))
-        minersList.Refresh()
     }
 
-    var previousButton, nextButton *widget.Button
-    previousButton = widget.NewButton("Previous", func() {
-        if currentPage > 1 {
-            currentPage--
-            updateMinersList()
-            if currentPage == 1 {
-                previousButton.Disable()
-            }
-            if currentPage < totalPages {
-                nextButton.Enable()
+    go func() {
+        ticker := time.NewTicker(maturityCheckInterval)
+        liveCh := liveDataUpdated.Subscribe()
+        defer ticker.Stop()
+        notifyMaturities()
+        check()
+        updateTray()
+        for {
+            select {
+            case <-ticker.C:
+                autoEnd() // Before the prompts, which skip the stakes it ended
+                notifyMaturities()
+                check()
+                updateTray()
+            case <-liveCh:
+                autoEnd()
             }
         }
-    })
-    nextButton = widget.NewButton("Next", func() {
-        if currentPage < totalPages {
-            currentPage++
-            updateMinersList()
-            if currentPage == totalPages {
-                nextButton.Disable()
-            }
-            if currentPage > 1 {
-                previousButton.Enable()
-            }
+    }()
+}
+
+// Tab titled name, nil when there's none (e.g. the Dashboard was turned off since)
+func findTab(items []*container.TabItem, name string) *container.TabItem {
+    for _, item := range items {
+        if item.Text == name {
+            return item
         }
-    })
+    }
+    return nil
+}
 
-    updateMinersList()
+// Selects the tab saved last when remembering it is on and it still exists, fallback
+// otherwise (nil keeps the current one)
+func restoreLastTab(tabs *container.AppTabs, fallback *container.TabItem) {
+    config := configManager.GetConfig()
+    if last := findTab(tabs.Items, config.LastTab); last != nil && config.RememberLastTab {
+        tabs.Select(last)
+    } else if fallback != nil {
+        tabs.Select(fallback)
+    }
+}
 
-    if currentPage == 1 {
-        previousButton.Disable()
+// Saves the title of each tab selected from now on while remembering it is on
+func rememberSelectedTab(tabs *container.AppTabs) {
+    tabs.OnSelected = func(item *container.TabItem) {
+        config := configManager.GetConfig()
+        if !config.RememberLastTab || item.Text == config.LastTab {
+            return
+        }
+        if err := updateConfig(func(c *Config) {
+            c.LastTab = item.Text
+        }); err != nil {
+            log.Println("Error saving config:", err)
+        }
     }
-    if currentPage == totalPages || totalPages == 0 {
-        nextButton.Disable()
+}
+
+// Stops of the background work (tickers, subscriptions) of tab contents, by the content the
+// constructor returned. Replacing a content stops its work, the rest stops with the app.
+var (
+    tabWorkMutex sync.Mutex
+    tabWork      = map[fyne.CanvasObject]context.CancelFunc{}
+)
+
+// Ties cancel to content and returns content, for the tab constructors
+func withTabWork(content fyne.CanvasObject, cancel context.CancelFunc) fyne.CanvasObject {
+    tabWorkMutex.Lock()
+    defer tabWorkMutex.Unlock()
+    tabWork[content] = cancel
+    return content
+}
+
+func stopTabWork(content fyne.CanvasObject) {
+    tabWorkMutex.Lock()
+    cancel := tabWork[content]
+    delete(tabWork, content)
+    tabWorkMutex.Unlock()
+    if cancel != nil {
+        cancel()
     }
+}
 
-    navBar := container.NewHBox(previousButton, pageLabel, nextButton)
+func stopAllTabWork() {
+    tabWorkMutex.Lock()
+    cancels := tabWork
+    tabWork = map[fyne.CanvasObject]context.CancelFunc{}
+    tabWorkMutex.Unlock()
+    for _, cancel := range cancels {
+        cancel()
+    }
+}
 
-    return container.NewVBox(
-        widget.NewLabel("Live Data Settings"),
-        frequencyEntry,
-        saveFrequencyButton,
-        widget.NewLabel("Add New Miner"),
-        startDateContainer,
-        endDateContainer,
-        tSharesEntry,
-        addButton,
-        widget.NewLabel("Existing Miners"),
-        minersList,
-        navBar,
-    )
+// Replaces a tab's content, keeping the scroll position when both old and new scroll, and
+// stops the background work of the old one
+func swapTabContent(item *container.TabItem, content fyne.CanvasObject) {
+    if oldScroll, ok := item.Content.(*container.Scroll); ok {
+        if newScroll, ok := content.(*container.Scroll); ok {
+            newScroll.Offset = oldScroll.Offset
+        }
+    }
+    old := item.Content
+    item.Content = content
+    stopTabWork(old)
 }
 
 // Main Function
 func main() {
+    flagValues, err := flagOverrides(os.Args[1:])
+    if err != nil {
+        log.Println("Error parsing flags:", err)
+    }
+    activeOverrides = envOverrides(os.Getenv).merge(flagValues)
+    if activeOverrides.DataDir != "" {
+        os.MkdirAll(activeOverrides.DataDir, 0755)
+        if err := os.Chdir(activeOverrides.DataDir); err != nil {
+            log.Println("Error changing to data directory:", err)
+        }
+    }
+
     os.MkdirAll("data", 0755)
     os.MkdirAll("settings", 0755)
 
-    if err := updateLocalHEXJSON(); err != nil {
-        log.Println("Error updating local HEXJSON:", err)
+    releaseLock, err := acquireInstanceLock(instanceLockFile)
+    if err == errAlreadyRunning {
+        log.Println("HEX Stats is already running, PID:", lockHolderPID(instanceLockFile))
+        showAlreadyRunning()
+        return
+    } else if err != nil {
+        log.Println("Error creating instance lock:", err)
+    } else {
+        defer releaseLock()
     }
 
-    miners, err := loadMiners()
+    store, err = openStorage(activeOverrides.Storage)
     if err != nil {
-        log.Println("Error loading miners:", err)
+        log.Println("Error opening storage:", err)
+        showStorageError(err)
+        return
     }
+    defer store.Close()
 
     // Load initial config and set in configManager
     config, err := loadConfig()
     if err != nil {
         log.Println("Error loading config:", err)
-        config.LiveDataFrequency = defaultLiveDataFrequency
+        config = defaultConfig()
     }
-    configManager.SetLiveDataFrequency(config.LiveDataFrequency)
+    overriddenFileConfig = config
+    config = applyOverrides(applyEnvOverrides(config), flagValues)
+    configManager.SetConfig(config)
+
+    // Sync history in the background so a slow download doesn't delay startup,
+    // after the config is loaded so the configured API is used
+    go syncHistory()
 
-    // Initial fetch of live data at startup
-    data, err := fetchLiveData()
+    recoverMinersFromJournal()
+    runArchivePass()
+    miners, err := loadMiners()
     if err != nil {
-        log.Println("Error during initial live data fetch:", err)
-    } else {
-        liveDataMutex.Lock()
-        latestLiveData = data
-        liveDataMutex.Unlock()
+        log.Println("Error loading miners:", err)
     }
 
-    // Start periodic live data fetching
+    // Live data is fetched in the background so a slow network doesn't delay the window,
+    // tabs start with empty values and update once the first fetch lands
     go func() {
         frequency := configManager.GetLiveDataFrequency()
         log.Println("Starting live data fetch ticker with frequency:", frequency, "minutes")
-        ticker := time.NewTicker(time.Duration(frequency) * time.Minute)
+        ticker := time.NewTicker(liveFetchInterval())
+        resumeTicker := time.NewTicker(resumeCheckInterval)
+        lastResumeCheck := time.Now()
         changeCh := configManager.Subscribe()
         defer ticker.Stop()
+        defer resumeTicker.Stop()
+        fetchCount := 0
+        fetch := func() {
+            if configManager.GetConfig().OfflineMode {
+                log.Println("Offline mode, skipping live data fetch")
+                return
+            }
+            fetchCount++
+            if historySyncDue(fetchCount, configManager.GetConfig().HistorySyncEveryNFetches) {
+                go syncHistory()
+            }
+            if liveStreamConnected.Load() {
+                refreshFXRate() // The stream already keeps live data current
+                return
+            }
+            data, err := fetchLiveData()
+            recordFetch("live data", err)
+            if err != nil {
+                log.Println("Error fetching live data:", err)
+            } else {
+                setLiveData(data, time.Now())
+                // log.Println("Updated latestLiveData with TsharePricePulsechain:", latestLiveData.TsharePricePulsechain)
+            }
+            refreshFXRate()
+        }
+        waitStartupDelay(time.After)
+        fetch()
         for {
             select {
             case <-ticker.C:
-                data, err := fetchLiveData()
-                if err != nil {
-                    log.Println("Error fetching live data:", err)
+                if enabled, after := pauseSettings(); fetchPause.Paused(time.Now(), enabled, after) {
+                    log.Println("Window inactive, skipping live data fetch")
                 } else {
-                    liveDataMutex.Lock()
-                    latestLiveData = data
-                    liveDataMutex.Unlock()
-                    // log.Println("Updated latestLiveData with TsharePricePulsechain:", latestLiveData.TsharePricePulsechain)
+                    fetch()
                 }
-                frequency = configManager.GetLiveDataFrequency()
-                ticker.Reset(time.Duration(frequency) * time.Minute)
+                ticker.Reset(liveFetchInterval())
+            case now := <-resumeTicker.C:
+                slept := sleptBetween(lastResumeCheck, now, resumeCheckInterval)
+                lastResumeCheck = now
+                enabled, after := pauseSettings()
+                if slept && configManager.GetConfig().RefreshOnResume && !fetchPause.Paused(now, enabled, after) {
+                    log.Println("Resumed from sleep, refreshing live data")
+                    fetch()
+                    ticker.Reset(liveFetchInterval())
+                }
+            case <-fetchPause.resumeCh:
+                log.Println("Window active again, resuming live data fetch")
+                fetch()
+                ticker.Reset(liveFetchInterval())
+            case <-fetchNowCh:
+                fetch()
+                ticker.Reset(liveFetchInterval())
             case <-changeCh:
-                frequency = configManager.GetLiveDataFrequency()
-                // log.Println("Live data fetch ticker resetting to frequency:", frequency, "minutes")
-                ticker.Reset(time.Duration(frequency) * time.Minute)
+                // log.Println("Live data fetch ticker resetting to frequency:", configManager.GetLiveDataFrequency(), "minutes")
+                ticker.Reset(liveFetchInterval())
             }
         }
     }()
 
+    if config.LiveDataStream && config.LiveDataStreamURL != "" {
+        streamCtx, stopStream := context.WithCancel(context.Background())
+        defer stopStream()
+        go runLiveDataStream(streamCtx, config.LiveDataStreamURL)
+    }
+
     a := app.New()
     iconResource := fyne.NewStaticResource("icon.png", appIcon)
     a.SetIcon(iconResource)
-    w := a.NewWindow("HEX Stats")
+    w := a.NewWindow(appTitle)
+    alertWindow = w
     w.Resize(fyne.NewSize(800, 600))
 
+    a.Lifecycle().SetOnEnteredForeground(func() {
+        enabled, after := pauseSettings()
+        fetchPause.Focus(time.Now(), enabled, after)
+        screenWake.Focus(configManager.GetConfig().KeepScreenOn)
+    })
+    a.Lifecycle().SetOnExitedForeground(func() {
+        fetchPause.Blur(time.Now())
+        screenWake.Blur()
+    })
+    a.Lifecycle().SetOnStopped(stopAllTabWork)
+
+    // Stays above the tabs across refreshes
+    updateBanner := container.NewVBox()
+    portfolioBar := container.NewVBox()
+
+    privacyItem := fyne.NewMenuItem("Privacy Mode", nil)
+    privacyShortcut := &desktop.CustomShortcut{KeyName: fyne.KeyP, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+    privacyItem.Shortcut = privacyShortcut
+    viewMenu := fyne.NewMenu("View", privacyItem)
+
+    // Built once, refreshes only swap the tab contents so the selected tab stays put
+    dashboardTab := container.NewTabItem("Dashboard", widget.NewLabel(""))
+    profileTab := container.NewTabItem("Profile", widget.NewLabel(""))
+    liveDataTab := container.NewTabItem("Live Data", widget.NewLabel(""))
+    chartTab := container.NewTabItem("Charts", createChartTab(w)) // Keeps its zoom and pan, see tabsRebuilt
+    yieldTab := container.NewTabItem("Yield", widget.NewLabel(""))
+    settingsTab := container.NewTabItem("Settings", widget.NewLabel(""))
+    tabs := container.NewAppTabs(profileTab, liveDataTab, chartTab, yieldTab, settingsTab)
+    w.SetContent(container.NewBorder(container.NewVBox(updateBanner, portfolioBar), nil, nil, nil, tabs))
     var refreshTabs func()
-    refreshTabs = func() {
+    var addShortcut *desktop.CustomShortcut
+    rebuildTabs := func() {
         log.Println("Refreshing tabs")
+        privacyItem.Checked = configManager.GetConfig().PrivacyMode // Also picks up a settings reset
+        viewMenu.Refresh()
         miners, _ = loadMiners()
-        profileTab := container.NewTabItem("Profile", createProfileTab(miners, w, refreshTabs))
-        liveDataTab := container.NewTabItem("Live Data", createLiveDataTab())
-        //chartTab := container.NewTabItem("Chart", createChartTab())
-        settingsTab := container.NewTabItem("Settings", createSettingsTab(miners, w, refreshTabs))
-        tabs := container.NewAppTabs(profileTab, liveDataTab, settingsTab) // chartTab
-        w.SetContent(tabs)
+        selected := tabs.Selected()
+        // The optional dashboard comes first when shown
+        showDashboard := configManager.GetConfig().ShowDashboard
+        if showDashboard {
+            swapTabContent(dashboardTab, createDashboardTab(miners))
+            if tabs.Items[0] != dashboardTab {
+                tabs.Items = append([]*container.TabItem{dashboardTab}, tabs.Items...)
+            }
+        } else if tabs.Items[0] == dashboardTab {
+            tabs.Items = tabs.Items[1:]
+            swapTabContent(dashboardTab, widget.NewLabel(""))
+        }
+        swapTabContent(profileTab, createProfileTab(miners, w, refreshTabs, false))
+        swapTabContent(liveDataTab, createLiveDataTab(refreshTabs))
+        swapTabContent(yieldTab, createYieldTab(miners))
+        swapTabContent(settingsTab, createSettingsTab(miners, w, refreshTabs))
+        portfolioBar.Objects = []fyne.CanvasObject{newPortfolioBar(w, refreshTabs)}
+        portfolioBar.Refresh()
+        tabs.Refresh()
+        if selected == dashboardTab && !showDashboard {
+            selected = profileTab
+        }
+        tabs.Select(selected)
+        updateWindowTitle(w, miners) // Same data and privacy mode as the tabs
+        if trayInstalled {
+            updateTrayMenu(w) // Follows the chain picked on the Live Data tab
+        }
+        // Re-registered as the setting may have changed
+        addShortcut = registerAddMinerShortcut(w.Canvas(), addShortcut, configManager.GetConfig().AddMinerShortcut, func() {
+            tabs.Select(settingsTab)
+            if focusNewMinerForm != nil {
+                focusNewMinerForm()
+            }
+        })
+        tabsRebuilt.Notify()
     }
+    refreshTabs = (&refreshScheduler{rebuild: rebuildTabs}).Request
 
     refreshTabs()
+    var openingTab *container.TabItem
+    if configManager.GetConfig().ShowDashboard {
+        openingTab = dashboardTab // Opens on the dashboard when it's shown
+    }
+    restoreLastTab(tabs, openingTab)
+    rememberSelectedTab(tabs)
+
+    togglePrivacy := func() {
+        enabled := !configManager.GetConfig().PrivacyMode
+        if err := updateConfig(func(c *Config) {
+            c.PrivacyMode = enabled
+        }); err != nil {
+            log.Println("Error saving config:", err)
+            return
+        }
+        refreshTabs()
+    }
+    privacyItem.Action = togglePrivacy
+    w.SetMainMenu(fyne.NewMainMenu(viewMenu, newExportMenu(w)))
+    w.Canvas().AddShortcut(privacyShortcut, func(_ fyne.Shortcut) {
+        togglePrivacy()
+    })
+
+    // With the auto-end prompt on the watcher asks about each stake instead, with auto-end
+    // on it ends them after the first fetch
+    if !configManager.GetConfig().AutoEndPrompt && !configManager.GetConfig().AutoEndMatured {
+        summary := reconcileMaturity(miners, time.Now())
+        log.Println("Startup maturity check:", summary.Active, "active,", summary.Matured, "matured")
+        if summary.Matured > 0 {
+            dialog.ShowCustomConfirm("Stakes Matured", "View Profile", "Later", widget.NewLabel(maturitySummaryMessage(summary)), func(view bool) {
+                if view {
+                    tabs.Select(profileTab)
+                }
+            }, w)
+        }
+    }
+    setTrayMatured(w, maturedCount(miners, time.Now()))
+    if configManager.GetConfig().TrayAlerts || configManager.GetConfig().TrayMode {
+        if installTray(w) && configManager.GetConfig().TrayMode {
+            runningInTray.Store(true)
+            w.SetCloseIntercept(func() {
+                log.Println("Window closed, still running in the system tray")
+                w.Hide()
+            })
+        }
+    }
+    startMaturityWatcher(w, refreshTabs)
+    startValueAlertWatcher()
+    startQuietWatcher()
+    startTitleUpdater(w, func() []Miner {
+        return miners // Reloaded by every rebuild, which also runs on the UI thread
+    })
+    checkForUpdate(updateBanner)
     w.ShowAndRun()
+    screenWake.Release()
 }
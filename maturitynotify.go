@@ -0,0 +1,108 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+// Notifications when a stake matures (its grace days start) and again shortly before the grace
+// days run out, each sent once per stake. Flags on the miner remember what was sent, Notified is
+// shared with the end prompt so a stake is announced as matured once.
+
+const graceWarningDays = 3 // Grace days left when the reminder goes out
+
+type maturityNotices struct {
+    Matured     []Miner
+    GraceEnding []Miner
+}
+
+func (n maturityNotices) Empty() bool {
+    return len(n.Matured) == 0 && len(n.GraceEnding) == 0
+}
+
+// Flags the stakes with a notice due on miners (to be saved) and returns them
+func collectMaturityNotices(miners []Miner, now time.Time) maturityNotices {
+    var notices maturityNotices
+    for i, miner := range miners {
+        if state, err := minerState(miner, now); err != nil || !state.Matured() {
+            continue
+        }
+        overdue, err := daysOverdueAt(miner.EndDate, now)
+        if err != nil {
+            continue
+        }
+        if overdue >= latePenaltyGraceDays-graceWarningDays {
+            if !miner.GraceNotified {
+                // A stake found this late only gets the reminder
+                miners[i].GraceNotified = true
+                miners[i].Notified = true
+                notices.GraceEnding = append(notices.GraceEnding, miners[i])
+            }
+            continue
+        }
+        if !miner.Notified {
+            miners[i].Notified = true
+            notices.Matured = append(notices.Matured, miners[i])
+        }
+    }
+    return notices
+}
+
+// Flags the stored stakes with a notice due and returns the notices. Holds minersTxMutex from
+// load to save so an edit or portfolio switch can't land in between.
+func takeMaturityNotices(now time.Time) (maturityNotices, error) {
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    miners, err := loadMiners()
+    if err != nil {
+        return maturityNotices{}, err
+    }
+    notices := collectMaturityNotices(miners, now)
+    if notices.Empty() {
+        return notices, nil
+    }
+    for _, miner := range append(append([]Miner(nil), notices.Matured...), notices.GraceEnding...) {
+        journalAppend(journalEntry{Op: "update", Miner: &miner})
+    }
+    if err := saveMiners(miners); err != nil {
+        return maturityNotices{}, err
+    }
+    return notices, nil
+}
+
+func stakeText(miner Miner) string {
+    return fmt.Sprintf("Stake %s - %s (%s T-Shares)", miner.StartDate, miner.EndDate, formatTShares(miner.TShares))
+}
+
+// Title and text of the matured notification, one for all stakes found in a check
+func maturedNotification(miners []Miner) (string, string) {
+    if len(miners) == 1 {
+        return "Stake Matured", fmt.Sprintf("%s matured, end it within %d days to avoid late penalties", stakeText(miners[0]), latePenaltyGraceDays)
+    }
+    return "Stakes Matured", fmt.Sprintf("%d stakes matured, end them within %d days to avoid late penalties", len(miners), latePenaltyGraceDays)
+}
+
+func graceEndingNotification(miners []Miner, now time.Time) (string, string) {
+    if len(miners) > 1 {
+        return "Grace Period Ending", fmt.Sprintf("%d matured stakes are about to lose HEX to late penalties, end them soon", len(miners))
+    }
+    overdue, _ := daysOverdueAt(miners[0].EndDate, now)
+    left := latePenaltyGraceDays - overdue
+    switch {
+    case left <= 0:
+        return "Late Penalties Started", fmt.Sprintf("%s is past its grace period and losing HEX to late penalties", stakeText(miners[0]))
+    case left == 1:
+        return "Grace Period Ending", fmt.Sprintf("%s has 1 grace day left before late penalties start", stakeText(miners[0]))
+    }
+    return "Grace Period Ending", fmt.Sprintf("%s has %d grace days left before late penalties start", stakeText(miners[0]), left)
+}
+
+func maturedCount(miners []Miner, now time.Time) int {
+    count := 0
+    for _, miner := range miners {
+        if state, err := minerState(miner, now); err == nil && state.Matured() {
+            count++
+        }
+    }
+    return count
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+    "errors"
+    "testing"
+)
+
+// Loses every miners write, like a crash while saving
+type failingMinersStorage struct {
+    jsonStorage
+}
+
+func (failingMinersStorage) WriteMiners(portfolio string, data []byte) error {
+    return errors.New("disk full")
+}
+
+// A stake is noticed once when it matures and once when its grace days run low, one found
+// late only gets the reminder
+func TestCollectMaturityNotices(t *testing.T) {
+    now := testDay(t, "20-06-2025")
+    miners := []Miner{
+        {ID: "matured", StartDate: "01-01-2025", EndDate: "18-06-2025", TShares: 1},
+        {ID: "late", StartDate: "01-01-2025", EndDate: "08-06-2025", TShares: 1},
+        {ID: "prompted", StartDate: "01-01-2025", EndDate: "18-06-2025", TShares: 1, Notified: true}, // Asked about by the end prompt
+        {ID: "active", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 1},
+    }
+    notices := collectMaturityNotices(miners, now)
+    if len(notices.Matured) != 1 || notices.Matured[0].ID != "matured" {
+        t.Errorf("matured notices = %+v, want only the newly matured stake", notices.Matured)
+    }
+    if len(notices.GraceEnding) != 1 || notices.GraceEnding[0].ID != "late" {
+        t.Errorf("grace notices = %+v, want the stake in its last grace days", notices.GraceEnding)
+    }
+    if !miners[0].Notified || !miners[1].Notified || !miners[1].GraceNotified || miners[3].Notified {
+        t.Errorf("miners = %+v, want the noticed stakes flagged", miners)
+    }
+    if again := collectMaturityNotices(miners, now); !again.Empty() {
+        t.Errorf("second check = %+v, want nothing sent twice", again)
+    }
+}
+
+// The flags are saved under the miners lock and journaled, so a failed save can be recovered
+func TestTakeMaturityNotices(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.MinerJournal = true
+    })
+    useTempStorage(t)
+    now := testDay(t, "20-06-2025")
+    if err := saveMiners([]Miner{{ID: "matured", StartDate: "01-01-2025", EndDate: "18-06-2025", TShares: 1}}); err != nil {
+        t.Fatal(err)
+    }
+    notices, err := takeMaturityNotices(now)
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(notices.Matured) != 1 {
+        t.Fatalf("notices = %+v, want the matured stake", notices)
+    }
+    if miners, _ := loadMiners(); len(miners) != 1 || !miners[0].Notified {
+        t.Errorf("saved miners = %+v, want the stake flagged", miners)
+    }
+    if notices, _ := takeMaturityNotices(now); !notices.Empty() {
+        t.Errorf("second check = %+v, want nothing sent twice", notices)
+    }
+
+    // A save that fails sends nothing and leaves the change in the journal
+    if err := saveMiners([]Miner{{ID: "grace", StartDate: "01-01-2025", EndDate: "08-06-2025", TShares: 1}}); err != nil {
+        t.Fatal(err)
+    }
+    store = failingMinersStorage{}
+    if notices, err := takeMaturityNotices(now); err == nil || !notices.Empty() {
+        t.Errorf("failed save = %+v, %v, want an error and no notices", notices, err)
+    }
+    entries, err := readJournal(minersJournalPath())
+    if err != nil {
+        t.Fatal(err)
+    }
+    last := entries[len(entries)-1]
+    if last.Op != "update" || last.Miner == nil || last.Miner.ID != "grace" || !last.Miner.GraceNotified {
+        t.Errorf("last journal entry = %+v, want the flagged stake", last)
+    }
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+    "fmt"
+    "time"
+)
+
+// Per-miner figures the miner lists show, worked out in one pass per refresh so the
+// date math isn't repeated for every row that gets drawn
+type minerView struct {
+    Miner        Miner
+    State        stakeState
+    Err          error // Stored data can't be used, the figures below are zero
+    DaysLeft     int
+    DaysOverdue  int     // Days past the end date of a matured stake
+    Progress     float64 // Fraction (0-1) of the stake duration elapsed
+    YieldHEX     float64 // Realized for completed miners, projected for others
+    HasYield     bool
+    GainHEX      float64
+    GainFraction float64 // Gain as a fraction of the principal
+    HasGain      bool    // Whether the miner has a principal
+    AwaitingData bool    // The gain needs the payout, which isn't fetched yet
+}
+
+func buildMinerViewModels(miners []Miner, now time.Time, data LiveData) []minerView {
+    views := make([]minerView, 0, len(miners))
+    for _, miner := range miners {
+        payout := data.Chain(minerChain(miner)).PayoutPerTshare
+        view := minerView{Miner: miner}
+        if miner.Status != "completed" {
+            if err := validateMiner(miner); err != nil {
+                view.Err = err
+                views = append(views, view)
+                continue
+            }
+        }
+        view.State, _ = minerState(miner, now)
+        if view.State != stakeCompleted {
+            view.DaysLeft, _ = daysLeftAt(miner.EndDate, now)
+            view.DaysOverdue, _ = daysOverdueAt(miner.EndDate, now)
+            view.Progress, _ = stakeProgress(miner.StartDate, miner.EndDate, now)
+        }
+        view.YieldHEX, view.HasYield = stakeYield(miner, payout)
+        view.GainHEX, view.GainFraction, view.HasGain = minerGain(miner, payout)
+        view.AwaitingData = view.HasGain && payout <= 0 && !(miner.Status == "completed" && miner.RealizedHEX > 0)
+        views = append(views, view)
+    }
+    return views
+}
+
+// Row suffix with the gain, empty for miners without a principal
+func (v minerView) gainNote(label string) string {
+    if !v.HasGain {
+        return ""
+    }
+    if v.AwaitingData {
+        return fmt.Sprintf(", %s: %s", label, calculatingText)
+    }
+    return fmt.Sprintf(", %s: +%s HEX (%.1f%%)", label, maskPrivate(formatWithCommas(int(v.GainHEX))), v.GainFraction*100)
+}
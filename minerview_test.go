@@ -0,0 +1,82 @@
+package main
+
+import "testing"
+
+func TestBuildMinerViewModels(t *testing.T) {
+    useConfig(t, nil)
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {ID: "active", StartDate: "01-05-2025", EndDate: "11-06-2025", TShares: 2, PrincipalHEX: 1000},
+        {ID: "eth", StartDate: "01-05-2025", EndDate: "11-06-2025", TShares: 2, Chain: chainEthereum},
+        {ID: "matured", StartDate: "01-01-2025", EndDate: "22-05-2025", TShares: 1},
+        {ID: "pending", StartDate: "11-06-2025", EndDate: "21-06-2025", TShares: 1},
+        {ID: "completed", StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 4, Status: "completed", RealizedHEX: 500, PrincipalHEX: 1000},
+        {ID: "invalid", StartDate: "31-02-2025", EndDate: "01-01-2026", TShares: 1},
+    }
+    views := buildMinerViewModels(miners, now, LiveData{PayoutPerTsharePulsechain: 3, PayoutPerTshareEthereum: 5})
+    if len(views) != len(miners) {
+        t.Fatalf("got %d views, want one per miner", len(views))
+    }
+    tests := []struct {
+        state    stakeState
+        daysLeft int
+        overdue  int
+        progress float64
+        yield    float64
+        gain     float64
+        hasGain  bool
+    }{
+        {stakeActive, 10, 0, 31.0 / 41, 2 * 3 * 41, 2 * 3 * 41, true},
+        {stakeActive, 10, 0, 31.0 / 41, 2 * 5 * 41, 0, false}, // Its own chain's payout
+        {stakeMatured, 0, 10, 1, 1 * 3 * 141, 0, false},
+        {stakePending, 20, 0, 0, 1 * 3 * 10, 0, false},
+        {stakeCompleted, 0, 0, 0, 500, 500, true}, // The recorded yield, no date math
+    }
+    for i, tt := range tests {
+        view := views[i]
+        if view.Miner.ID != miners[i].ID || view.Err != nil {
+            t.Errorf("view %d = %+v, want miner %s without an error", i, view, miners[i].ID)
+            continue
+        }
+        if view.State != tt.state || view.DaysLeft != tt.daysLeft || view.DaysOverdue != tt.overdue || !near(float32(view.Progress), float32(tt.progress)) {
+            t.Errorf("%s: state %v, %d days left, %d overdue, progress %v, want %v, %d, %d, %v", view.Miner.ID, view.State, view.DaysLeft, view.DaysOverdue, view.Progress, tt.state, tt.daysLeft, tt.overdue, tt.progress)
+        }
+        if !view.HasYield || view.YieldHEX != tt.yield || view.HasGain != tt.hasGain || view.GainHEX != tt.gain {
+            t.Errorf("%s: yield %v (%v), gain %v (%v), want %v and %v (%v)", view.Miner.ID, view.YieldHEX, view.HasYield, view.GainHEX, view.HasGain, tt.yield, tt.gain, tt.hasGain)
+        }
+        if view.AwaitingData {
+            t.Errorf("%s waits for data that was fetched", view.Miner.ID)
+        }
+    }
+    if invalid := views[5]; invalid.Err == nil || invalid.DaysLeft != 0 || invalid.HasYield {
+        t.Errorf("invalid miner view = %+v, want the error and no figures", invalid)
+    }
+}
+
+// Before the first fetch gains wait for the payout, except a completed miner's recorded one
+func TestMinerViewAwaitingData(t *testing.T) {
+    useConfig(t, nil)
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {StartDate: "01-05-2025", EndDate: "11-06-2025", TShares: 2, PrincipalHEX: 1000},
+        {StartDate: "01-05-2025", EndDate: "11-06-2025", TShares: 2},
+        {StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 4, Status: "completed", RealizedHEX: 100, PrincipalHEX: 1000},
+    }
+    views := buildMinerViewModels(miners, now, LiveData{})
+    if !views[0].AwaitingData || views[1].AwaitingData || views[2].AwaitingData {
+        t.Errorf("awaiting data = %v, %v, %v, want only the active miner with a principal", views[0].AwaitingData, views[1].AwaitingData, views[2].AwaitingData)
+    }
+    tests := []struct {
+        view minerView
+        want string
+    }{
+        {views[0], ", Gain: " + calculatingText},
+        {views[1], ""},
+        {views[2], ", Gain: +100 HEX (10.0%)"},
+    }
+    for _, tt := range tests {
+        if got := tt.view.gainNote("Gain"); got != tt.want {
+            t.Errorf("gainNote = %q, want %q", got, tt.want)
+        }
+    }
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+    "log"
+    "os"
+    "runtime"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/dialog"
+)
+
+// Alerts go out as system notifications where they can be delivered and as in-app
+// dialogs otherwise. Fyne doesn't report failed notifications, so availability is
+// judged from the platform and the user can force in-app alerts if theirs are dropped.
+
+// Window in-app alerts are shown over, set once the main window exists
+var alertWindow fyne.Window
+
+const notificationsUnavailableNote = "System notifications unavailable - using in-app alerts"
+
+// Whether system notifications can be delivered, Linux and BSD need a session bus
+func notificationsAvailable(goos string, getenv func(string) string) bool {
+    switch goos {
+    case "linux", "freebsd", "openbsd", "netbsd":
+        return getenv("DBUS_SESSION_BUS_ADDRESS") != ""
+    }
+    return true
+}
+
+func systemNotificationsUsable() bool {
+    return !configManager.GetConfig().InAppAlerts && notificationsAvailable(runtime.GOOS, os.Getenv)
+}
+
+// Shows an alert, call from any goroutine. In quiet mode it is kept for the summary instead.
+func notify(title, content string) {
+    if quietActive(configManager.GetConfig(), time.Now()) {
+        if err := holdBackAlert(title + ": " + content); err != nil {
+            log.Println("Error saving config:", err)
+        }
+        return
+    }
+    if systemNotificationsUsable() {
+        fyne.CurrentApp().SendNotification(fyne.NewNotification(title, content))
+        return
+    }
+    if alertWindow == nil {
+        log.Println("Alert dropped, no window yet:", title, "-", content)
+        return
+    }
+    fyne.Do(func() {
+        dialog.ShowInformation(title, content, alertWindow)
+    })
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+    "testing"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/test"
+)
+
+func TestNotificationsAvailable(t *testing.T) {
+    noBus := func(string) string { return "" }
+    withBus := func(key string) string {
+        return map[string]string{"DBUS_SESSION_BUS_ADDRESS": "unix:path=/run/user/1000/bus"}[key]
+    }
+    tests := []struct {
+        goos   string
+        getenv func(string) string
+        want   bool
+    }{
+        {"linux", noBus, false},
+        {"linux", withBus, true},
+        {"freebsd", noBus, false},
+        {"windows", noBus, true},
+        {"darwin", noBus, true},
+    }
+    for _, tt := range tests {
+        if got := notificationsAvailable(tt.goos, tt.getenv); got != tt.want {
+            t.Errorf("notificationsAvailable(%s, bus %v) = %v, want %v", tt.goos, tt.getenv("DBUS_SESSION_BUS_ADDRESS") != "", got, tt.want)
+        }
+    }
+}
+
+// With system notifications unusable the alert is shown in the window instead of sent
+func TestNotifyFallsBackToInAppAlert(t *testing.T) {
+    useTestApp(t)
+    useConfig(t, func(c *Config) {
+        c.InAppAlerts = true
+    })
+    previous := alertWindow
+    window := test.NewWindow(nil)
+    alertWindow = window
+    t.Cleanup(func() {
+        window.Close()
+        alertWindow = previous
+    })
+
+    test.AssertNotificationSent(t, nil, func() {
+        notify("Stake Ended", "A stake matured")
+        fyne.DoAndWait(func() {})
+    })
+    if window.Canvas().Overlays().Top() == nil {
+        t.Error("no in-app alert was shown")
+    }
+
+    // Without a window yet the alert is dropped rather than crashing
+    alertWindow = nil
+    notify("Stake Ended", "A stake matured")
+}
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package main
+
+import "fyne.io/fyne/v2"
+
+// Always-on-top needs a platform call fyne doesn't expose, only Windows is supported
+func setAlwaysOnTop(_ fyne.Window) {}
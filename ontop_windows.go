@@ -0,0 +1,29 @@
+package main
+
+import (
+    "syscall"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/driver"
+)
+
+var setWindowPos = syscall.NewLazyDLL("user32.dll").NewProc("SetWindowPos")
+
+const (
+    hwndTopmost = ^uintptr(0) // HWND_TOPMOST (-1)
+    swpNoSize   = 0x0001
+    swpNoMove   = 0x0002
+)
+
+// Keeps w above other windows, must be called after the window is shown
+func setAlwaysOnTop(w fyne.Window) {
+    native, ok := w.(driver.NativeWindow)
+    if !ok {
+        return
+    }
+    native.RunNative(func(ctx any) {
+        if win, ok := ctx.(driver.WindowsWindowContext); ok && win.HWND != 0 {
+            setWindowPos.Call(win.HWND, hwndTopmost, 0, 0, 0, 0, swpNoMove|swpNoSize)
+        }
+    })
+}
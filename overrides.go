@@ -0,0 +1,142 @@
+package main
+
+import (
+    "flag"
+    "log"
+    "os"
+    "strconv"
+    "strings"
+    "sync"
+)
+
+// Config overrides for scripted or containerized setups. Command-line flags win over
+// environment variables, which win over config.json:
+//
+//   -frequency / HEXFETCH_FREQUENCY   live data fetch frequency in minutes
+//   -api-url   / HEXFETCH_API_URL     base URL of the hexdailystats API, https only
+//   -currency  / HEXFETCH_CURRENCY    display currency
+//   -dir       / HEXFETCH_DIR         directory holding data/ and settings/
+//   -storage   / HEXFETCH_STORAGE     sqlite (default) or json for the files of older versions
+type overrides struct {
+    Frequency  int
+    APIBaseURL string
+    Currency   string
+    DataDir    string
+    Storage    string
+}
+
+func envOverrides(getenv func(string) string) overrides {
+    o := overrides{
+        APIBaseURL: getenv("HEXFETCH_API_URL"),
+        Currency:   strings.ToUpper(getenv("HEXFETCH_CURRENCY")),
+        DataDir:    getenv("HEXFETCH_DIR"),
+        Storage:    strings.ToLower(getenv("HEXFETCH_STORAGE")),
+    }
+    if frequency, err := strconv.Atoi(getenv("HEXFETCH_FREQUENCY")); err == nil && frequency > 0 {
+        o.Frequency = frequency
+    }
+    return o
+}
+
+func flagOverrides(args []string) (overrides, error) {
+    var o overrides
+    flags := flag.NewFlagSet("hexfetch", flag.ContinueOnError)
+    flags.IntVar(&o.Frequency, "frequency", 0, "live data fetch frequency in minutes")
+    flags.StringVar(&o.APIBaseURL, "api-url", "", "base URL of the hexdailystats API")
+    flags.StringVar(&o.Currency, "currency", "", "display currency")
+    flags.StringVar(&o.DataDir, "dir", "", "directory holding data/ and settings/")
+    flags.StringVar(&o.Storage, "storage", "", "sqlite or json")
+    err := flags.Parse(args)
+    o.Currency = strings.ToUpper(o.Currency)
+    o.Storage = strings.ToLower(o.Storage)
+    return o, err
+}
+
+// Fields set in high replace those in o
+func (o overrides) merge(high overrides) overrides {
+    if high.Frequency > 0 {
+        o.Frequency = high.Frequency
+    }
+    if high.APIBaseURL != "" {
+        o.APIBaseURL = high.APIBaseURL
+    }
+    if high.Currency != "" {
+        o.Currency = high.Currency
+    }
+    if high.DataDir != "" {
+        o.DataDir = high.DataDir
+    }
+    if contains(storageBackends, high.Storage) {
+        o.Storage = high.Storage
+    }
+    return o
+}
+
+func applyOverrides(cfg Config, o overrides) Config {
+    if o.Frequency > 0 {
+        cfg.LiveDataFrequency = o.Frequency
+    }
+    // Checked like the Settings entry, a bad override leaves the config.json URL
+    if o.APIBaseURL != "" {
+        if apiURL, err := validateAPIBaseURL(o.APIBaseURL); err == nil {
+            cfg.APIBaseURL = apiURL
+        } else {
+            log.Println("Warning: ignoring API URL override:", err)
+        }
+    }
+    if isCurrency(o.Currency) {
+        cfg.Currency = o.Currency
+    }
+    return cfg
+}
+
+func applyEnvOverrides(cfg Config) Config {
+    return applyOverrides(cfg, envOverrides(os.Getenv))
+}
+
+// Overrides in effect and the config.json values they replaced, so saving the config
+// doesn't write override values back to the file
+var activeOverrides overrides
+var overriddenFileConfig Config
+
+// Overridable fields the user has changed in the app since startup, those are saved as set
+// even when the new value happens to equal the override
+var changedOverrides struct {
+    mu         sync.Mutex
+    Frequency  bool
+    APIBaseURL bool
+    Currency   bool
+}
+
+// Records which overridable fields a config change touched
+func trackOverrideChanges(before, after Config) {
+    changedOverrides.mu.Lock()
+    defer changedOverrides.mu.Unlock()
+    changedOverrides.Frequency = changedOverrides.Frequency || before.LiveDataFrequency != after.LiveDataFrequency
+    changedOverrides.APIBaseURL = changedOverrides.APIBaseURL || before.APIBaseURL != after.APIBaseURL
+    changedOverrides.Currency = changedOverrides.Currency || before.Currency != after.Currency
+}
+
+// Counts every overridable field as changed, after a reset the file gets the defaults
+func markOverridesChanged() {
+    changedOverrides.mu.Lock()
+    defer changedOverrides.mu.Unlock()
+    changedOverrides.Frequency, changedOverrides.APIBaseURL, changedOverrides.Currency = true, true, true
+}
+
+// Undoes active overrides on fields the user hasn't changed since startup
+func withoutOverrides(cfg Config) Config {
+    o := activeOverrides
+    changedOverrides.mu.Lock()
+    defer changedOverrides.mu.Unlock()
+    if o.Frequency > 0 && !changedOverrides.Frequency {
+        cfg.LiveDataFrequency = overriddenFileConfig.LiveDataFrequency
+    }
+    if o.APIBaseURL != "" && !changedOverrides.APIBaseURL {
+        cfg.APIBaseURL = overriddenFileConfig.APIBaseURL
+    }
+    if isCurrency(o.Currency) && !changedOverrides.Currency {
+        cfg.Currency = overriddenFileConfig.Currency
+    }
+    return cfg
+}
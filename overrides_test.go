@@ -0,0 +1,119 @@
+package main
+
+import (
+    "encoding/json"
+    "testing"
+)
+
+// Runs the test with o as the active overrides of fileConfig
+func useOverrides(t *testing.T, o overrides, fileConfig Config) {
+    t.Helper()
+    previous, previousFile := activeOverrides, overriddenFileConfig
+    activeOverrides, overriddenFileConfig = o, fileConfig
+    changedOverrides.mu.Lock()
+    changedOverrides.Frequency, changedOverrides.APIBaseURL, changedOverrides.Currency = false, false, false
+    changedOverrides.mu.Unlock()
+    t.Cleanup(func() {
+        activeOverrides, overriddenFileConfig = previous, previousFile
+        changedOverrides.mu.Lock()
+        changedOverrides.Frequency, changedOverrides.APIBaseURL, changedOverrides.Currency = false, false, false
+        changedOverrides.mu.Unlock()
+    })
+}
+
+func TestOverridePrecedence(t *testing.T) {
+    env := map[string]string{
+        "HEXFETCH_FREQUENCY": "15",
+        "HEXFETCH_API_URL":   "https://env.example.com/",
+        "HEXFETCH_CURRENCY":  "eur",
+    }
+    file := defaultConfig()
+    file.LiveDataFrequency = 5
+    file.Currency = "USD"
+
+    fromEnv := applyOverrides(file, envOverrides(func(key string) string { return env[key] }))
+    if fromEnv.LiveDataFrequency != 15 || fromEnv.APIBaseURL != "https://env.example.com" || fromEnv.Currency != "EUR" {
+        t.Errorf("env overrides gave %d, %s, %s", fromEnv.LiveDataFrequency, fromEnv.APIBaseURL, fromEnv.Currency)
+    }
+
+    flags, err := flagOverrides([]string{"-frequency", "30", "-currency", "gbp"})
+    if err != nil {
+        t.Fatal(err)
+    }
+    merged := envOverrides(func(key string) string { return env[key] }).merge(flags)
+    got := applyOverrides(file, merged)
+    if got.LiveDataFrequency != 30 || got.Currency != "GBP" {
+        t.Errorf("flags gave %d, %s, want them to win over env", got.LiveDataFrequency, got.Currency)
+    }
+    if got.APIBaseURL != "https://env.example.com" {
+        t.Errorf("API URL = %s, want the env value where no flag is set", got.APIBaseURL)
+    }
+
+    // Unset or unusable values leave the file's
+    got = applyOverrides(file, envOverrides(func(key string) string {
+        return map[string]string{"HEXFETCH_FREQUENCY": "soon", "HEXFETCH_CURRENCY": "XYZ"}[key]
+    }))
+    if got.LiveDataFrequency != 5 || got.Currency != "USD" || got.APIBaseURL != file.APIBaseURL {
+        t.Errorf("invalid env values changed the config: %d, %s, %s", got.LiveDataFrequency, got.Currency, got.APIBaseURL)
+    }
+}
+
+// Saving keeps config.json's values for overridden fields unless the user changed them,
+// even to the override's value
+func TestSaveConfigWithoutOverrides(t *testing.T) {
+    useTempStorage(t)
+    file := defaultConfig()
+    file.LiveDataFrequency = 5
+    useOverrides(t, overrides{Frequency: 15, Currency: "EUR"}, file)
+    useConfig(t, func(c *Config) {
+        *c = applyOverrides(file, activeOverrides)
+    })
+    saved := func() Config {
+        t.Helper()
+        data, err := store.ReadConfig()
+        if err != nil {
+            t.Fatal(err)
+        }
+        var config Config
+        if err := json.Unmarshal(data, &config); err != nil {
+            t.Fatal(err)
+        }
+        return config
+    }
+
+    if err := updateConfig(func(c *Config) { c.PrivacyMode = true }); err != nil {
+        t.Fatal(err)
+    }
+    if config := saved(); config.LiveDataFrequency != 5 || config.Currency != "USD" || !config.PrivacyMode {
+        t.Errorf("saved %d, %s, privacy %v, want the file values and the new setting", config.LiveDataFrequency, config.Currency, config.PrivacyMode)
+    }
+
+    if err := updateConfig(func(c *Config) { c.LiveDataFrequency = 20 }); err != nil {
+        t.Fatal(err)
+    }
+    if err := updateConfig(func(c *Config) { c.LiveDataFrequency = 15 }); err != nil {
+        t.Fatal(err)
+    }
+    if config := saved(); config.LiveDataFrequency != 15 || config.Currency != "USD" {
+        t.Errorf("saved %d, %s, want the frequency the user picked and the file currency", config.LiveDataFrequency, config.Currency)
+    }
+    if configManager.GetConfig().Currency != "EUR" {
+        t.Error("saving dropped the currency override from the running config")
+    }
+}
+
+func TestAPIURLOverrideValidated(t *testing.T) {
+    file := defaultConfig()
+    file.APIBaseURL = "https://mirror.example.com"
+    for _, bad := range []string{"http://evil.example.com", "evil.example.com", "https://evil.example.com?steal=1"} {
+        if got := applyOverrides(file, overrides{APIBaseURL: bad}); got.APIBaseURL != file.APIBaseURL {
+            t.Errorf("override %q gave %s, want the config.json URL kept", bad, got.APIBaseURL)
+        }
+    }
+    env := envOverrides(func(key string) string {
+        return map[string]string{"HEXFETCH_API_URL": "http://evil.example.com"}[key]
+    })
+    if got := applyOverrides(file, env); got.APIBaseURL != file.APIBaseURL {
+        t.Errorf("HEXFETCH_API_URL over plain http gave %s", got.APIBaseURL)
+    }
+}
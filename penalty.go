@@ -0,0 +1,74 @@
+package main
+
+import (
+    "strconv"
+    "time"
+)
+
+// Late-end penalty estimate for matured stakes that haven't been ended yet.
+// HEX gives 14 grace days, then takes 1/700 of the stake per day until nothing is left.
+const (
+    latePenaltyGraceDays = 14
+    latePenaltyDays      = 700
+)
+
+// Whole days since endDate, 0 while the stake hasn't ended
+func daysOverdueAt(endDate string, now time.Time) (int, error) {
+    endTime, err := time.Parse(dateLayout, endDate)
+    if err != nil {
+        return 0, err
+    }
+    overdue := int(calendarDay(now).Sub(calendarDay(endTime)).Hours() / 24)
+    if overdue < 0 {
+        return 0, nil
+    }
+    return overdue, nil
+}
+
+// Share of the stake lost to the late penalty after daysOverdue days
+func latePenaltyFraction(daysOverdue int) float64 {
+    penaltyDays := daysOverdue - latePenaltyGraceDays
+    if penaltyDays <= 0 {
+        return 0
+    }
+    if penaltyDays >= latePenaltyDays {
+        return 1
+    }
+    return float64(penaltyDays) / latePenaltyDays
+}
+
+func netOfLatePenalty(gross float64, daysOverdue int) float64 {
+    return gross * (1 - latePenaltyFraction(daysOverdue))
+}
+
+// Active T-Shares per chain with each matured stake scaled down by its estimated late penalty
+func netActiveTShares(miners []Miner, now time.Time) map[string]float64 {
+    total := map[string]float64{}
+    for _, miner := range miners {
+        if miner.Status == "completed" {
+            continue
+        }
+        overdue, err := daysOverdueAt(miner.EndDate, now)
+        if err != nil {
+            overdue = 0
+        }
+        total[minerChain(miner)] += netOfLatePenalty(miner.TShares, overdue)
+    }
+    return total
+}
+
+// Row suffix with the net value of a matured stake, empty when not shown
+func netValueNote(view minerView, tsharePrice float64) string {
+    if !configManager.GetConfig().ShowNetValue {
+        return ""
+    }
+    if tsharePrice <= 0 {
+        return ", Net Value: " + calculatingText
+    }
+    fraction := latePenaltyFraction(view.DaysOverdue)
+    net := maskPrivate(formatMoney(netOfLatePenalty(view.Miner.TShares*tsharePrice, view.DaysOverdue), 2))
+    if fraction == 0 {
+        return ", Net Value: " + net + " (no late penalty yet)"
+    }
+    return ", Net Value: " + net + " (est. late penalty " + strconv.FormatFloat(fraction*100, 'f', 1, 64) + "%)"
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestNetOfLatePenalty(t *testing.T) {
+    tests := []struct {
+        overdue int
+        want    float64
+    }{
+        {0, 700},
+        {latePenaltyGraceDays, 700}, // Last grace day
+        {latePenaltyGraceDays + 1, 699},
+        {latePenaltyGraceDays + 350, 350},
+        {latePenaltyGraceDays + latePenaltyDays - 1, 1},
+        {latePenaltyGraceDays + latePenaltyDays, 0},
+        {5000, 0},
+    }
+    for _, tt := range tests {
+        if got := netOfLatePenalty(700, tt.overdue); !near(float32(got), float32(tt.want)) {
+            t.Errorf("netOfLatePenalty(700, %d) = %v, want %v", tt.overdue, got, tt.want)
+        }
+    }
+}
+
+func TestDaysOverdueAt(t *testing.T) {
+    now := testDay(t, "01-06-2025")
+    tests := []struct {
+        end  string
+        want int
+    }{
+        {"01-07-2025", 0}, // Not ended, no penalty
+        {"01-06-2025", 0},
+        {"31-05-2025", 1},
+        {"01-06-2024", 365},
+    }
+    for _, tt := range tests {
+        if got, err := daysOverdueAt(tt.end, now); err != nil || got != tt.want {
+            t.Errorf("daysOverdueAt(%s) = %d, %v, want %d", tt.end, got, err, tt.want)
+        }
+    }
+    if _, err := daysOverdueAt("bad", now); err == nil {
+        t.Error("daysOverdueAt accepted an invalid date")
+    }
+}
+
+func TestNetValueNote(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.ShowNetValue = true
+    })
+    view := minerView{Miner: Miner{TShares: 2}, DaysOverdue: latePenaltyGraceDays + 70}
+    if got, want := netValueNote(view, 100), ", Net Value: $180.00 (est. late penalty 10.0%)"; got != want {
+        t.Errorf("netValueNote = %q, want %q", got, want)
+    }
+    view.DaysOverdue = 3
+    if got := netValueNote(view, 100); !strings.Contains(got, "$200.00 (no late penalty yet)") {
+        t.Errorf("netValueNote within the grace period = %q", got)
+    }
+    if got := netValueNote(view, 0); got != ", Net Value: "+calculatingText {
+        t.Errorf("netValueNote before a fetch = %q", got)
+    }
+    useConfig(t, nil)
+    if got := netValueNote(view, 100); got != "" {
+        t.Errorf("netValueNote with the setting off = %q", got)
+    }
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+    "context"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/driver/desktop"
+    "fyne.io/fyne/v2/layout"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Small always-on-top window with just the prices, for watching while doing other things
+func showLiveDataPopout() {
+    a := fyne.CurrentApp()
+    var popout fyne.Window
+    if drv, ok := a.Driver().(desktop.Driver); ok {
+        popout = drv.CreateSplashWindow() // Borderless
+    } else {
+        popout = a.NewWindow("HEX Stats")
+    }
+
+    priceLabel := newLiveDataValueLabel()
+    tsharePriceLabel := newLiveDataValueLabel()
+    update := func() {
+        liveDataMutex.Lock()
+        data := latestLiveData
+        liveDataMutex.Unlock()
+        chainData := data.Chain(configManager.GetConfig().Chain)
+        priceLabel.SetText(formatMoney(chainData.Price, priceDecimals))
+        tsharePriceLabel.SetText(formatMoney(chainData.TsharePrice, tsharePriceDecimals))
+    }
+    update()
+
+    ctx, cancel := context.WithCancel(context.Background())
+    go func() {
+        ticker := time.NewTicker(time.Duration(configManager.GetLiveDataFrequency()) * time.Minute)
+        changeCh := configManager.Subscribe()
+        liveCh := liveDataUpdated.Subscribe()
+        defer configManager.Unsubscribe(changeCh)
+        defer liveDataUpdated.Unsubscribe(liveCh)
+        defer ticker.Stop()
+        for {
+            select {
+            case <-liveCh:
+                fyne.Do(update)
+            case <-ticker.C:
+                fyne.Do(update)
+            case <-changeCh:
+                ticker.Reset(time.Duration(configManager.GetLiveDataFrequency()) * time.Minute)
+            case <-ctx.Done():
+                return
+            }
+        }
+    }()
+    popout.SetOnClosed(cancel)
+
+    popout.SetContent(container.NewVBox(
+        container.New(layout.NewFormLayout(),
+            widget.NewLabel("Price"), priceLabel,
+            widget.NewLabel("T-Share Price"), tsharePriceLabel,
+        ),
+        widget.NewButton("Close", popout.Close),
+    ))
+    popout.Show()
+    setAlwaysOnTop(popout)
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "os"
+    "path/filepath"
+    "regexp"
+    "sort"
+    "strings"
+    "time"
+)
+
+// Named portfolios keep their files (journal, archive, miners.json with -storage json) in
+// settings/portfolios/<name>, the default portfolio stays in settings/ so existing installs keep
+// their files. Called portfolios in the UI so they aren't mixed up with the Profile tab.
+const (
+    portfoliosDir        = "settings/portfolios"
+    defaultPortfolioName = "Default"
+)
+
+var portfolioNamePattern = regexp.MustCompile(`^[A-Za-z0-9 _-]{1,40}$`)
+
+// Directory of a portfolio, "" is the default one
+func portfolioDir(name string) string {
+    if name == "" {
+        return "settings"
+    }
+    return filepath.Join(portfoliosDir, name)
+}
+
+func minersJournalPath() string {
+    return filepath.Join(portfolioDir(configManager.GetConfig().ActivePortfolio), "miners.journal")
+}
+
+func validatePortfolioName(name string) error {
+    if !portfolioNamePattern.MatchString(name) || strings.TrimSpace(name) != name {
+        return fmt.Errorf("portfolio names are 1-40 letters, digits, spaces, - or _")
+    }
+    if strings.EqualFold(name, defaultPortfolioName) {
+        return fmt.Errorf("%q is the name of the default portfolio", defaultPortfolioName)
+    }
+    return nil
+}
+
+// Named portfolios in alphabetical order, the default one isn't included
+func listPortfolios() ([]string, error) {
+    entries, err := os.ReadDir(portfoliosDir)
+    if err != nil {
+        if os.IsNotExist(err) {
+            return nil, nil
+        }
+        return nil, err
+    }
+    var names []string
+    for _, entry := range entries {
+        if entry.IsDir() && validatePortfolioName(entry.Name()) == nil {
+            names = append(names, entry.Name())
+        }
+    }
+    sort.Strings(names)
+    return names, nil
+}
+
+func portfolioExists(name string) bool {
+    if name == "" {
+        return true
+    }
+    if validatePortfolioName(name) != nil {
+        return false // Never a path outside portfoliosDir
+    }
+    info, err := os.Stat(portfolioDir(name))
+    return err == nil && info.IsDir()
+}
+
+// Creates an empty portfolio, switching to it is up to the caller
+func createPortfolio(name string) error {
+    if err := validatePortfolioName(name); err != nil {
+        return err
+    }
+    if portfolioExists(name) {
+        return fmt.Errorf("portfolio %q already exists", name)
+    }
+    return os.MkdirAll(portfolioDir(name), 0755)
+}
+
+// Makes name the active portfolio, its miners are loaded from then on. Changes loaded from
+// the previous portfolio can't be saved into this one while it switches.
+func switchPortfolio(name string) error {
+    if !portfolioExists(name) {
+        return fmt.Errorf("portfolio %q doesn't exist", name)
+    }
+    minersTxMutex.Lock()
+    defer minersTxMutex.Unlock()
+    minersMutex.Lock() // No save may be halfway through writing the previous portfolio
+    err := updateConfig(func(c *Config) {
+        c.ActivePortfolio = name
+    })
+    minersMutex.Unlock()
+    if err != nil {
+        return err
+    }
+    recoverMinersFromJournal()
+    if _, err := archiveAgedMiners(time.Now()); err != nil {
+        log.Println("Error archiving miners:", err)
+    }
+    return nil
+}
+
+// Renames a named portfolio, following it when it's the active one
+func renamePortfolio(from, to string) error {
+    if from == "" {
+        return fmt.Errorf("the default portfolio can't be renamed")
+    }
+    if err := validatePortfolioName(to); err != nil {
+        return err
+    }
+    if !portfolioExists(from) {
+        return fmt.Errorf("portfolio %q doesn't exist", from)
+    }
+    if portfolioExists(to) {
+        return fmt.Errorf("portfolio %q already exists", to)
+    }
+    minersMutex.Lock()
+    defer minersMutex.Unlock()
+    if err := os.Rename(portfolioDir(from), portfolioDir(to)); err != nil {
+        return err
+    }
+    if err := store.RenameMiners(from, to); err != nil {
+        os.Rename(portfolioDir(to), portfolioDir(from)) // Keeps the miners and the directory together
+        return err
+    }
+    if configManager.GetConfig().ActivePortfolio != from {
+        return nil
+    }
+    return updateConfig(func(c *Config) {
+        c.ActivePortfolio = to
+    })
+}
+
+// Removes a named portfolio with its miners, journal and archive. The active portfolio
+// can't be deleted, switch away from it first.
+func deletePortfolio(name string) error {
+    if name == "" {
+        return fmt.Errorf("the default portfolio can't be deleted")
+    }
+    if name == configManager.GetConfig().ActivePortfolio {
+        return fmt.Errorf("portfolio %q is active, switch to another one first", name)
+    }
+    if !portfolioExists(name) {
+        return fmt.Errorf("portfolio %q doesn't exist", name)
+    }
+    minersMutex.Lock()
+    defer minersMutex.Unlock()
+    if err := store.DeleteMiners(name); err != nil {
+        return err
+    }
+    return os.RemoveAll(portfolioDir(name))
+}
+
+// Number of active and completed miners saved in a portfolio
+func portfolioMinerCount(name string) (int, error) {
+    minersMutex.RLock()
+    data, err := store.ReadMiners(name)
+    minersMutex.RUnlock()
+    if err != nil || data == nil {
+        return 0, err
+    }
+    var miners []Miner
+    if err := json.Unmarshal(data, &miners); err != nil {
+        return 0, err
+    }
+    return len(miners), nil
+}
+
+// Name shown in the UI, "" is the default portfolio
+func portfolioDisplayName(name string) string {
+    if name == "" {
+        return defaultPortfolioName
+    }
+    return name
+}
@@ -0,0 +1,186 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+func TestValidatePortfolioName(t *testing.T) {
+    for _, name := range []string{"Family", "Trust 2025", "a_b-c"} {
+        if err := validatePortfolioName(name); err != nil {
+            t.Errorf("validatePortfolioName(%q) = %v, want it accepted", name, err)
+        }
+    }
+    for _, name := range []string{"", " padded", "../x", "a/b", `a\b`, ".", "default", "Default", "way too long for a portfolio name of forty"} {
+        if err := validatePortfolioName(name); err == nil {
+            t.Errorf("validatePortfolioName(%q) accepted", name)
+        }
+    }
+}
+
+// Each portfolio keeps its own miners, switching loads the other set
+func TestPortfoliosIsolated(t *testing.T) {
+    useConfig(t, nil)
+    useTempStorage(t)
+    a := Miner{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 1}
+    b := Miner{ID: "b", StartDate: "01-02-2025", EndDate: "01-02-2026", TShares: 2}
+    if err := saveMiners([]Miner{a}); err != nil {
+        t.Fatal(err)
+    }
+
+    if err := createPortfolio("Family"); err != nil {
+        t.Fatal(err)
+    }
+    if err := createPortfolio("Family"); err == nil {
+        t.Error("created the same portfolio twice")
+    }
+    if names, err := listPortfolios(); err != nil || !reflect.DeepEqual(names, []string{"Family"}) {
+        t.Errorf("listPortfolios = %q, %v, want the new one", names, err)
+    }
+    if configManager.GetConfig().ActivePortfolio != "" {
+        t.Error("creating a portfolio switched to it")
+    }
+
+    if err := switchPortfolio("Family"); err != nil {
+        t.Fatal(err)
+    }
+    if miners, _ := loadMiners(); len(miners) != 0 {
+        t.Errorf("new portfolio has miners %+v", miners)
+    }
+    if err := saveMiners([]Miner{b}); err != nil {
+        t.Fatal(err)
+    }
+    if err := switchPortfolio(""); err != nil {
+        t.Fatal(err)
+    }
+    if miners, _ := loadMiners(); !reflect.DeepEqual(miners, []Miner{a}) {
+        t.Errorf("default portfolio = %+v, want only its own miner", miners)
+    }
+    if count, err := portfolioMinerCount("Family"); err != nil || count != 1 {
+        t.Errorf("portfolioMinerCount(Family) = %d, %v, want 1", count, err)
+    }
+    if err := switchPortfolio("Missing"); err == nil {
+        t.Error("switched to a portfolio that doesn't exist")
+    }
+}
+
+// A hand-edited active portfolio can't point outside the portfolios directory
+func TestLoadConfigActivePortfolio(t *testing.T) {
+    useConfig(t, nil)
+    useTempStorage(t)
+    os.MkdirAll(filepath.Join(portfoliosDir, "Family"), 0755)
+    os.MkdirAll(filepath.Join("settings", "x"), 0755) // Where ../x leads
+    tests := []struct {
+        stored string
+        want   string
+    }{
+        {`{"activePortfolio": "Family"}`, "Family"},
+        {`{"activePortfolio": "../x"}`, ""},
+        {`{"activePortfolio": "../../data"}`, ""},
+        {`{"activePortfolio": "Removed"}`, ""},
+    }
+    for _, tt := range tests {
+        if err := store.WriteConfig([]byte(tt.stored)); err != nil {
+            t.Fatal(err)
+        }
+        config, err := loadConfig()
+        if err != nil {
+            t.Fatal(err)
+        }
+        if config.ActivePortfolio != tt.want {
+            t.Errorf("%s: ActivePortfolio = %q, want %q", tt.stored, config.ActivePortfolio, tt.want)
+        }
+    }
+}
+
+// Renaming moves the miners and files with the portfolio and follows it when active,
+// deleting removes both; for the JSON files and the database alike
+func TestRenameDeletePortfolio(t *testing.T) {
+    for _, backend := range storageBackends {
+        t.Run(backend, func(t *testing.T) {
+            useConfig(t, nil)
+            useTempStorage(t)
+            if backend == "sqlite" {
+                s, err := openSQLiteStorage(sqliteStoragePath)
+                if err != nil {
+                    t.Fatal(err)
+                }
+                defer s.Close()
+                store = s
+            }
+            miner := Miner{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 1}
+            for _, name := range []string{"Family", "Trust"} {
+                if err := createPortfolio(name); err != nil {
+                    t.Fatal(err)
+                }
+            }
+            if err := switchPortfolio("Family"); err != nil {
+                t.Fatal(err)
+            }
+            if err := saveMiners([]Miner{miner}); err != nil {
+                t.Fatal(err)
+            }
+            if err := saveArchivedMiners([]Miner{miner}); err != nil {
+                t.Fatal(err)
+            }
+
+            if err := renamePortfolio("Family", "Trust"); err == nil {
+                t.Error("renamed onto an existing portfolio")
+            }
+            for _, tt := range []struct{ from, to string }{{"", "Other"}, {"Missing", "Other"}, {"Family", "../x"}} {
+                if err := renamePortfolio(tt.from, tt.to); err == nil {
+                    t.Errorf("renamed %q to %q", tt.from, tt.to)
+                }
+            }
+            if err := renamePortfolio("Family", "Household"); err != nil {
+                t.Fatal(err)
+            }
+            if names, _ := listPortfolios(); !reflect.DeepEqual(names, []string{"Household", "Trust"}) {
+                t.Errorf("portfolios after renaming = %q", names)
+            }
+            if active := configManager.GetConfig().ActivePortfolio; active != "Household" {
+                t.Errorf("active portfolio %q after renaming it, want Household", active)
+            }
+            if miners, _ := loadMiners(); !reflect.DeepEqual(miners, []Miner{miner}) {
+                t.Errorf("miners after renaming = %+v, want them moved along", miners)
+            }
+            if archived, _ := loadArchivedMiners(); !reflect.DeepEqual(archived, []Miner{miner}) {
+                t.Errorf("archive after renaming = %+v, want it moved along", archived)
+            }
+
+            if err := deletePortfolio("Household"); err == nil {
+                t.Error("deleted the active portfolio")
+            }
+            if err := deletePortfolio(""); err == nil {
+                t.Error("deleted the default portfolio")
+            }
+            if err := switchPortfolio(""); err != nil {
+                t.Fatal(err)
+            }
+            if err := deletePortfolio("Household"); err != nil {
+                t.Fatal(err)
+            }
+            if names, _ := listPortfolios(); !reflect.DeepEqual(names, []string{"Trust"}) {
+                t.Errorf("portfolios after deleting = %q", names)
+            }
+            if _, err := os.Stat(portfolioDir("Household")); !os.IsNotExist(err) {
+                t.Errorf("directory left after deleting: %v", err)
+            }
+            if data, err := store.ReadMiners("Household"); err != nil || data != nil {
+                t.Errorf("miners left after deleting: %s, %v", data, err)
+            }
+            // A new portfolio of the same name starts empty
+            if err := createPortfolio("Household"); err != nil {
+                t.Fatal(err)
+            }
+            if count, err := portfolioMinerCount("Household"); err != nil || count != 0 {
+                t.Errorf("recreated portfolio has %d miners (%v), want none", count, err)
+            }
+            if err := deletePortfolio("Missing"); err == nil {
+                t.Error("deleted a portfolio that doesn't exist")
+            }
+        })
+    }
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "strings"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/dialog"
+    "fyne.io/fyne/v2/layout"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Portfolio select above the tabs and the dialog for creating, renaming and deleting them
+
+func portfolioOptions() []string {
+    options := []string{defaultPortfolioName}
+    names, err := listPortfolios()
+    if err != nil {
+        log.Println("Error listing portfolios:", err)
+    }
+    return append(options, names...)
+}
+
+// Portfolio name of a select option, "" for the default one
+func portfolioFromOption(option string) string {
+    if option == defaultPortfolioName {
+        return ""
+    }
+    return option
+}
+
+func activatePortfolio(name string, w fyne.Window, refreshTabs func()) {
+    if name == configManager.GetConfig().ActivePortfolio {
+        return
+    }
+    if err := switchPortfolio(name); err != nil {
+        log.Println("Error switching portfolio:", err)
+        showError(fmt.Errorf("Failed to switch portfolio: %v", err), w)
+        return
+    }
+    refreshTabs()
+}
+
+func newPortfolioSelect(w fyne.Window, refreshTabs func()) *widget.Select {
+    portfolioSelect := widget.NewSelect(portfolioOptions(), nil)
+    portfolioSelect.SetSelected(portfolioDisplayName(configManager.GetConfig().ActivePortfolio))
+    portfolioSelect.OnChanged = func(option string) {
+        activatePortfolio(portfolioFromOption(option), w, refreshTabs)
+    }
+    return portfolioSelect
+}
+
+// Toolbar row above the tabs, rebuilt with them so it follows the active portfolio
+func newPortfolioBar(w fyne.Window, refreshTabs func()) fyne.CanvasObject {
+    manageButton := widget.NewButton("Manage...", func() {
+        showPortfolioManager(w, refreshTabs)
+    })
+    return container.NewHBox(widget.NewLabel("Portfolio:"), newPortfolioSelect(w, refreshTabs), manageButton)
+}
+
+func minerCountText(name string) string {
+    count, err := portfolioMinerCount(name)
+    switch {
+    case err != nil:
+        return "miners can't be read"
+    case count == 1:
+        return "1 miner"
+    }
+    return fmt.Sprintf("%d miners", count)
+}
+
+func showPortfolioManager(w fyne.Window, refreshTabs func()) {
+    rows := container.NewVBox()
+    var fillRows func()
+    changed := func() {
+        fillRows()
+        refreshTabs()
+    }
+    renamePrompt := func(name string) {
+        entry := widget.NewEntry()
+        entry.SetText(name)
+        dialog.ShowForm("Rename Portfolio", "Rename", "Cancel", []*widget.FormItem{widget.NewFormItem("Name", entry)}, func(ok bool) {
+            if !ok {
+                return
+            }
+            if err := renamePortfolio(name, strings.TrimSpace(entry.Text)); err != nil {
+                log.Println("Error renaming portfolio:", err)
+                showError(fmt.Errorf("Failed to rename portfolio: %v", err), w)
+                return
+            }
+            changed()
+        }, w)
+    }
+    deletePrompt := func(name string) {
+        message := fmt.Sprintf("Delete portfolio %s with its %s, journal and archive? This can't be undone.", name, minerCountText(name))
+        dialog.ShowConfirm("Delete Portfolio", message, func(yes bool) {
+            if !yes {
+                return
+            }
+            if err := deletePortfolio(name); err != nil {
+                log.Println("Error deleting portfolio:", err)
+                showError(fmt.Errorf("Failed to delete portfolio: %v", err), w)
+                return
+            }
+            changed()
+        }, w)
+    }
+    fillRows = func() {
+        rows.Objects = nil
+        active := configManager.GetConfig().ActivePortfolio
+        for _, option := range portfolioOptions() {
+            name := portfolioFromOption(option)
+            text := fmt.Sprintf("%s (%s)", option, minerCountText(name))
+            if name == active {
+                text += ", active"
+            }
+            switchButton := widget.NewButton("Switch", func() {
+                activatePortfolio(name, w, refreshTabs)
+                fillRows()
+            })
+            renameButton := widget.NewButton("Rename", func() {
+                renamePrompt(name)
+            })
+            deleteButton := widget.NewButton("Delete", func() {
+                deletePrompt(name)
+            })
+            if name == active {
+                switchButton.Disable()
+                deleteButton.Disable() // Switch away first
+            }
+            if name == "" {
+                renameButton.Disable()
+                deleteButton.Disable()
+            }
+            rows.Add(container.NewHBox(widget.NewLabel(text), layout.NewSpacer(), switchButton, renameButton, deleteButton))
+        }
+        rows.Refresh()
+    }
+    fillRows()
+
+    newEntry := widget.NewEntry()
+    newEntry.SetPlaceHolder("New portfolio name")
+    createButton := widget.NewButton("Create Portfolio", func() {
+        name := strings.TrimSpace(newEntry.Text)
+        if err := createPortfolio(name); err != nil {
+            showError(err, w)
+            return
+        }
+        newEntry.SetText("")
+        activatePortfolio(name, w, refreshTabs)
+        fillRows()
+    })
+
+    d := dialog.NewCustom("Portfolios", "Close", container.NewVBox(rows, widget.NewSeparator(), newEntry, createButton), w)
+    d.Resize(fyne.NewSize(520, 0))
+    d.Show()
+}
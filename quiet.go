@@ -0,0 +1,127 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "strings"
+    "sync"
+    "time"
+)
+
+// Quiet mode holds back alerts until it ends, optionally at a set time, then shows one summary.
+// The held back alerts are saved with the config so a restart doesn't lose them.
+
+const quietCheckInterval = 30 * time.Second
+
+var quietDurations = map[string]time.Duration{
+    "1 hour":  time.Hour,
+    "2 hours": 2 * time.Hour,
+    "8 hours": 8 * time.Hour,
+}
+
+// Options of the Settings select in display order
+var quietOptions = []string{"Off", "1 hour", "2 hours", "8 hours", "Until turned off"}
+
+// Serializes changes to the held back alerts, notify runs on any goroutine
+var quietAlertsMutex sync.Mutex
+
+func holdBackAlert(message string) error {
+    quietAlertsMutex.Lock()
+    defer quietAlertsMutex.Unlock()
+    return updateConfig(func(c *Config) {
+        c.QuietAlerts = append(c.QuietAlerts, message)
+    })
+}
+
+// Whether alerts are held back at now, QuietUntil zero means until turned off
+func quietActive(config Config, now time.Time) bool {
+    return config.QuietMode && (config.QuietUntil.IsZero() || now.Before(config.QuietUntil))
+}
+
+func quietSummary(messages []string) string {
+    var b strings.Builder
+    if len(messages) == 1 {
+        b.WriteString("1 alert while quiet mode was on:\n")
+    } else {
+        fmt.Fprintf(&b, "%d alerts while quiet mode was on:\n", len(messages))
+    }
+    for _, message := range messages {
+        b.WriteString("- " + message + "\n")
+    }
+    return b.String()
+}
+
+// Turns quiet mode on for option (one of quietOptions), "Off" ends it
+func setQuietMode(option string, now time.Time) error {
+    if option == "Off" {
+        return endQuietMode()
+    }
+    return updateConfig(func(c *Config) {
+        c.QuietMode = true
+        c.QuietUntil = time.Time{}
+        if d, ok := quietDurations[option]; ok {
+            c.QuietUntil = now.Add(d)
+        }
+    })
+}
+
+// Ends quiet mode and shows what was held back
+func endQuietMode() error {
+    quietAlertsMutex.Lock()
+    var messages []string
+    config := configManager.GetConfig()
+    if config.QuietMode || len(config.QuietAlerts) > 0 {
+        if err := updateConfig(func(c *Config) {
+            messages = c.QuietAlerts
+            c.QuietMode = false
+            c.QuietUntil = time.Time{}
+            c.QuietAlerts = nil
+        }); err != nil {
+            quietAlertsMutex.Unlock()
+            return err
+        }
+    }
+    quietAlertsMutex.Unlock()
+    if len(messages) > 0 {
+        notify("Quiet Mode Ended", quietSummary(messages))
+    }
+    return nil
+}
+
+// Ends quiet mode once its time is up
+func startQuietWatcher() {
+    go func() {
+        ticker := time.NewTicker(quietCheckInterval)
+        defer ticker.Stop()
+        for range ticker.C {
+            config := configManager.GetConfig()
+            if config.QuietMode && !quietActive(config, time.Now()) {
+                if err := endQuietMode(); err != nil {
+                    log.Println("Error saving config:", err)
+                }
+            }
+        }
+    }()
+}
+
+// Option the Settings select starts on, "" for a timed quiet mode as its end is in the status
+func quietSelection(config Config, now time.Time) string {
+    switch {
+    case !quietActive(config, now):
+        return "Off"
+    case config.QuietUntil.IsZero():
+        return "Until turned off"
+    }
+    return ""
+}
+
+// Status shown under the Settings select
+func quietStatusText(config Config, now time.Time) string {
+    if !quietActive(config, now) {
+        return ""
+    }
+    if config.QuietUntil.IsZero() {
+        return "Alerts are held back until quiet mode is turned off"
+    }
+    return "Alerts are held back until " + config.QuietUntil.Format("02-01-2006 15:04")
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+    "testing"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/test"
+)
+
+// Alerts during quiet mode are held back and shown as one summary when it ends
+func TestQuietModeHoldsBackAlerts(t *testing.T) {
+    useTestApp(t)
+    useConfig(t, func(c *Config) {
+        c.InAppAlerts = true
+    })
+    useTempStorage(t)
+    previous := alertWindow
+    window := test.NewWindow(nil)
+    alertWindow = window
+    t.Cleanup(func() {
+        window.Close()
+        alertWindow = previous
+    })
+
+    if err := setQuietMode("2 hours", time.Now()); err != nil {
+        t.Fatal(err)
+    }
+    notify("Stake Ended", "A stake matured")
+    notify("Portfolio Value Alert", "Total T-Shares value has risen")
+    fyne.DoAndWait(func() {})
+    if window.Canvas().Overlays().Top() != nil {
+        t.Fatal("an alert was shown during quiet mode")
+    }
+
+    held := configManager.GetConfig().QuietAlerts
+    if len(held) != 2 || held[0] != "Stake Ended: A stake matured" {
+        t.Fatalf("held back %q, want both alerts", held)
+    }
+    // Kept over a restart
+    if stored, err := loadConfig(); err != nil || len(stored.QuietAlerts) != 2 {
+        t.Fatalf("stored alerts = %q, %v, want both", stored.QuietAlerts, err)
+    }
+    if err := endQuietMode(); err != nil {
+        t.Fatal(err)
+    }
+    fyne.DoAndWait(func() {})
+    if window.Canvas().Overlays().Top() == nil {
+        t.Error("no summary was shown when quiet mode ended")
+    }
+    if config := configManager.GetConfig(); config.QuietMode || !config.QuietUntil.IsZero() {
+        t.Errorf("quiet mode still on after ending it: %v until %v", config.QuietMode, config.QuietUntil)
+    }
+    if left := configManager.GetConfig().QuietAlerts; len(left) != 0 {
+        t.Errorf("alerts %q left queued after the summary", left)
+    }
+    if stored, _ := loadConfig(); len(stored.QuietAlerts) != 0 {
+        t.Errorf("alerts %q left stored after the summary", stored.QuietAlerts)
+    }
+    want := "2 alerts while quiet mode was on:\n- Stake Ended: A stake matured\n- Portfolio Value Alert: Total T-Shares value has risen\n"
+    if got := quietSummary(held); got != want {
+        t.Errorf("quietSummary = %q, want %q", got, want)
+    }
+}
+
+func TestQuietSelection(t *testing.T) {
+    now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+    tests := []struct {
+        name   string
+        config Config
+        want   string
+    }{
+        {"off", Config{}, "Off"},
+        {"until turned off", Config{QuietMode: true}, "Until turned off"},
+        {"timed", Config{QuietMode: true, QuietUntil: now.Add(time.Hour)}, ""},
+        {"expired", Config{QuietMode: true, QuietUntil: now.Add(-time.Minute)}, "Off"},
+    }
+    for _, tt := range tests {
+        if got := quietSelection(tt.config, now); got != tt.want {
+            t.Errorf("%s: quietSelection = %q, want %q", tt.name, got, tt.want)
+        }
+    }
+}
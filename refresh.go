@@ -0,0 +1,29 @@
+package main
+
+import (
+    "sync/atomic"
+
+    "fyne.io/fyne/v2"
+)
+
+// Runs the tab rebuild on the UI thread. Requests made before a queued rebuild has started
+// coalesce into it, so quick successive actions rebuild once with the latest miners.
+type refreshScheduler struct {
+    pending atomic.Bool
+    rebuild func()
+    do      func(func()) // Runs on the UI thread, fyne.Do when nil
+}
+
+func (r *refreshScheduler) Request() {
+    if !r.pending.CompareAndSwap(false, true) {
+        return
+    }
+    do := r.do
+    if do == nil {
+        do = fyne.Do
+    }
+    do(func() {
+        r.pending.Store(false) // Cleared first so a request made during the rebuild isn't lost
+        r.rebuild()
+    })
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+    "sync"
+    "sync/atomic"
+    "testing"
+)
+
+// Requests from many goroutines coalesce, rebuilds never overlap and the last one sees the
+// latest state
+func TestRefreshSchedulerConcurrent(t *testing.T) {
+    queue := make(chan func(), 100)
+    done := make(chan struct{})
+    go func() { // A single UI thread
+        for f := range queue {
+            f()
+        }
+        close(done)
+    }()
+
+    var state, shown atomic.Int32
+    var running, overlaps, rebuilds atomic.Int32
+    release := make(chan struct{})
+    first := true
+    scheduler := &refreshScheduler{
+        do: func(f func()) { queue <- f },
+        rebuild: func() {
+            if running.Add(1) > 1 {
+                overlaps.Add(1)
+            }
+            if first {
+                first = false
+                <-release // Busy while the requests below come in
+            }
+            shown.Store(state.Load())
+            rebuilds.Add(1)
+            running.Add(-1)
+        },
+    }
+    scheduler.Request()
+
+    var wg sync.WaitGroup
+    for i := 0; i < 50; i++ {
+        wg.Add(1)
+        go func() {
+            defer wg.Done()
+            scheduler.Request()
+        }()
+    }
+    wg.Wait()
+    close(release)
+    state.Store(100) // Changed after the requests, before the coalesced rebuild ran
+    scheduler.Request()
+    close(queue)
+    <-done
+
+    if overlaps.Load() != 0 {
+        t.Errorf("%d rebuilds overlapped", overlaps.Load())
+    }
+    if got := rebuilds.Load(); got < 2 || got > 3 {
+        t.Errorf("%d rebuilds for 52 requests, want the first and the coalesced ones", got)
+    }
+    if shown.Load() != 100 {
+        t.Errorf("last rebuild showed state %d, want the latest 100", shown.Load())
+    }
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+    "fmt"
+    "html/template"
+    "io"
+    "strings"
+    "time"
+)
+
+// Portfolio report rendered to HTML for record-keeping
+type reportMiner struct {
+    StartDate string
+    EndDate   string
+    TShares   string
+    Progress  string
+    Chain     string
+}
+
+type reportData struct {
+    GeneratedAt     string
+    TotalTShares    string
+    TotalValue      string
+    ActiveCount     int
+    CompletedCount  int
+    ActiveMiners    []reportMiner
+    CompletedMiners []reportMiner
+    LiveData        LiveData
+    Chain           string // Chain of the live data figures
+    Price           string
+    TsharePrice     string
+    TshareRate      string
+    Payout          string
+    Penalties       string
+    Beat            string
+}
+
+const reportTemplate = `<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>HEX Portfolio Report</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 1.5em; }
+th, td { border: 1px solid #ccc; padding: 4px 10px; text-align: left; }
+th { background: #eee; }
+</style>
+</head>
+<body>
+<h1>HEX Portfolio Report</h1>
+<p>Generated: {{.GeneratedAt}}</p>
+
+<h2>Portfolio</h2>
+<table>
+<tr><th>Total T-Shares</th><td>{{.TotalTShares}}</td></tr>
+<tr><th>Total T-Shares Value</th><td>{{.TotalValue}}</td></tr>
+<tr><th>Active Miners</th><td>{{.ActiveCount}}</td></tr>
+<tr><th>Completed Miners</th><td>{{.CompletedCount}}</td></tr>
+</table>
+
+<h2>Active Miners</h2>
+{{if .ActiveMiners}}<table>
+<tr><th>Start</th><th>End</th><th>T-Shares</th><th>Chain</th><th>Progress</th></tr>
+{{range .ActiveMiners}}<tr><td>{{.StartDate}}</td><td>{{.EndDate}}</td><td>{{.TShares}}</td><td>{{.Chain}}</td><td>{{.Progress}}</td></tr>
+{{end}}</table>{{else}}<p>No active miners.</p>{{end}}
+
+<h2>Completed Miners</h2>
+{{if .CompletedMiners}}<table>
+<tr><th>Start</th><th>End</th><th>T-Shares</th><th>Chain</th></tr>
+{{range .CompletedMiners}}<tr><td>{{.StartDate}}</td><td>{{.EndDate}}</td><td>{{.TShares}}</td><td>{{.Chain}}</td></tr>
+{{end}}</table>{{else}}<p>No completed miners.</p>{{end}}
+
+<h2>Live Data</h2>
+<table>
+<tr><th>Chain</th><td>{{.Chain}}</td></tr>
+<tr><th>Price</th><td>{{.Price}}</td></tr>
+<tr><th>T-Share Price</th><td>{{.TsharePrice}}</td></tr>
+<tr><th>T-Share Rate</th><td>{{.TshareRate}}</td></tr>
+<tr><th>Payout Per T-Share</th><td>{{.Payout}}</td></tr>
+<tr><th>Penalties</th><td>{{.Penalties}}</td></tr>
+<tr><th>Beat</th><td>{{.Beat}}</td></tr>
+</table>
+</body>
+</html>
+`
+
+var reportTmpl = template.Must(template.New("report").Parse(reportTemplate))
+
+func buildReportData(miners []Miner, data LiveData, now time.Time) reportData {
+    chain := configManager.GetConfig().Chain
+    chainData := data.Chain(chain)
+    report := reportData{
+        GeneratedAt: now.Format("02-01-2006 15:04"),
+        LiveData:    data,
+        Chain:       chainName(chain),
+        Price:       formatMoney(chainData.Price, 4),
+        TsharePrice: formatMoney(chainData.TsharePrice, 2),
+        TshareRate:  fmt.Sprintf("%s HEX", formatWithCommas(int(chainData.TshareRateHEX))),
+        Payout:      formatPayout(chainData.PayoutPerTshare),
+        Penalties:   fmt.Sprintf("%s HEX", formatWithCommas(int(chainData.PenaltiesHEX))),
+        Beat:        formatLongWithCommas(data.Beat),
+    }
+
+    for _, view := range buildMinerViewModels(miners, now, data) {
+        miner := view.Miner
+        entry := reportMiner{
+            StartDate: miner.StartDate,
+            EndDate:   miner.EndDate,
+            TShares:   formatTShares(miner.TShares),
+            Chain:     chainName(minerChain(miner)),
+        }
+        if view.State == stakeCompleted {
+            report.CompletedMiners = append(report.CompletedMiners, entry)
+            continue
+        }
+        if view.Err == nil {
+            entry.Progress = fmt.Sprintf("%.1f%%", view.Progress*100)
+        } else {
+            entry.Progress = "-"
+        }
+        report.ActiveMiners = append(report.ActiveMiners, entry)
+    }
+
+    summary := currentPortfolioSummary(miners, data, now)
+    report.ActiveCount = summary.ActiveCount
+    report.CompletedCount = summary.CompletedCount
+    report.TotalTShares = formatTShares(summary.TotalTShares)
+    report.TotalValue = maskPrivate(formatMoney(summary.TotalValue, 2))
+    return report
+}
+
+func writeReport(w io.Writer, report reportData) error {
+    return reportTmpl.Execute(w, report)
+}
+
+// Plain-text portfolio summary for pasting into chat, amounts follow privacy mode
+func buildSummaryText(miners []Miner, data LiveData, now time.Time) string {
+    summary := currentPortfolioSummary(miners, data, now)
+    var b strings.Builder
+    b.WriteString("HEX Portfolio Summary\n")
+    fmt.Fprintf(&b, "Total T-Shares: %s\n", formatTShares(summary.TotalTShares))
+    fmt.Fprintf(&b, "Total T-Shares Value: %s\n", maskPrivate(formatMoney(summary.TotalValue, 2)))
+    fmt.Fprintf(&b, "Active Miners: %d\n", summary.ActiveCount)
+    fmt.Fprintf(&b, "Completed Miners: %d\n", summary.CompletedCount)
+    if summary.NextMaturity.IsZero() {
+        b.WriteString("Next Maturity: -\n")
+    } else {
+        fmt.Fprintf(&b, "Next Maturity: %s\n", summary.NextMaturity.Format(dateLayout))
+    }
+    return b.String()
+}
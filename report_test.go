@@ -0,0 +1,106 @@
+package main
+
+import (
+    "strings"
+    "testing"
+)
+
+func TestWriteReport(t *testing.T) {
+    useConfig(t, nil)
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 12.5},
+        {ID: "b", StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 3, Status: "completed"},
+    }
+    data := LiveData{PricePulsechain: 0.0123, TsharePricePulsechain: 250, TshareRateHEXPulsechain: 20000, PayoutPerTsharePulsechain: 1.5, Beat: 1234567}
+
+    var b strings.Builder
+    if err := writeReport(&b, buildReportData(miners, data, now)); err != nil {
+        t.Fatal(err)
+    }
+    html := b.String()
+    for _, want := range []string{
+        "<td>12.50</td>",           // Total T-Shares, the completed miner doesn't count
+        "<td>$3125.00</td>",        // Total value at the T-Share price
+        "<td>01-01-2025</td><td>01-01-2026</td><td>12.50</td>",
+        "<td>01-01-2024</td><td>01-01-2025</td><td>3.00</td>",
+        "<td>$250.00</td>",
+        "<td>20,000 HEX</td>",
+        "<td>1,234,567</td>",
+        "Generated: 01-06-2025 12:00",
+    } {
+        if !strings.Contains(html, want) {
+            t.Errorf("report is missing %q", want)
+        }
+    }
+    if strings.Contains(html, "No active miners.") || strings.Contains(html, "No completed miners.") {
+        t.Error("report claims a miner list is empty")
+    }
+}
+
+func TestWriteReportEmpty(t *testing.T) {
+    useConfig(t, nil)
+    var b strings.Builder
+    if err := writeReport(&b, buildReportData(nil, LiveData{}, testDay(t, "01-06-2025"))); err != nil {
+        t.Fatal(err)
+    }
+    for _, want := range []string{"No active miners.", "No completed miners."} {
+        if !strings.Contains(b.String(), want) {
+            t.Errorf("report is missing %q", want)
+        }
+    }
+}
+
+func TestWriteReportPrivacyMode(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.PrivacyMode = true
+    })
+    miners := []Miner{{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 12.5}}
+    var b strings.Builder
+    if err := writeReport(&b, buildReportData(miners, LiveData{TsharePricePulsechain: 250}, testDay(t, "01-06-2025"))); err != nil {
+        t.Fatal(err)
+    }
+    if strings.Contains(b.String(), "3125") || strings.Contains(b.String(), "12.50") {
+        t.Error("report shows holdings in privacy mode")
+    }
+}
+
+func TestBuildSummaryTextPrivacyMode(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.PrivacyMode = true
+    })
+    miners := []Miner{{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 12.5}}
+    text := buildSummaryText(miners, LiveData{TsharePricePulsechain: 250}, testDay(t, "01-06-2025"))
+    for _, hidden := range []string{"12.5", "3125"} {
+        if strings.Contains(text, hidden) {
+            t.Errorf("summary shows %q in privacy mode:\n%s", hidden, text)
+        }
+    }
+    for _, shown := range []string{"Total T-Shares: " + privacyMask, "Next Maturity: 01-01-2026", "Active Miners: 1"} {
+        if !strings.Contains(text, shown) {
+            t.Errorf("summary is missing %q in privacy mode:\n%s", shown, text)
+        }
+    }
+}
+
+func TestBuildSummaryText(t *testing.T) {
+    useConfig(t, nil)
+    miners := []Miner{
+        {ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2027", TShares: 12.5},
+        {ID: "b", StartDate: "01-03-2025", EndDate: "01-09-2025", TShares: 7.5},
+        {ID: "c", StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 3, Status: "completed"},
+    }
+    text := buildSummaryText(miners, LiveData{TsharePricePulsechain: 250}, testDay(t, "01-06-2025"))
+    want := "HEX Portfolio Summary\n" +
+        "Total T-Shares: 20.00\n" +
+        "Total T-Shares Value: $5000.00\n" +
+        "Active Miners: 2\n" +
+        "Completed Miners: 1\n" +
+        "Next Maturity: 01-09-2025\n"
+    if text != want {
+        t.Errorf("summary:\n%s\nwant:\n%s", text, want)
+    }
+    if text := buildSummaryText(nil, LiveData{}, testDay(t, "01-06-2025")); !strings.Contains(text, "Next Maturity: -\n") {
+        t.Errorf("empty portfolio summary has a next maturity:\n%s", text)
+    }
+}
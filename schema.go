@@ -0,0 +1,138 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "reflect"
+    "sort"
+    "strings"
+    "sync"
+)
+
+// Diagnostic for upstream API changes. Normal decoding stays lenient, with strict decode
+// enabled the fields of each response are checked against the fields the app decodes and
+// the ones the API is known to send, anything else is reported as a hint that a newer app
+// version may be needed.
+
+// Fields the API sends that the app doesn't read. The responses carry far more than the app
+// uses, so without these every fetch would be reported.
+var knownAPIFields = map[string][]string{
+    "live data": {
+        "circulatingHEX", "stakedHEX", "liquidityHEX", "liquidityUSDC", "liquidityETH",
+        "circulatingHEX_Pulsechain", "stakedHEX_Pulsechain", "liquidityHEX_Pulsechain",
+        "liquidityPLS_Pulsechain", "liquidityEHEX_Pulsechain", "pricePLS_Pulsechain",
+        "pricePLSX_Pulsechain", "priceINC_Pulsechain",
+    },
+    "history": {
+        "date", "circulatingHEX", "stakedHEX", "totalTshares", "totalTsharesChange",
+        "penaltiesHEX", "averageStakeLength", "payoutPerTshareHEX", "actualAPYRate",
+        "dailyMintedInflationTotal", "priceUV2", "priceUV3", "priceChangeUV2",
+        "priceChangeUV3", "priceChangeUV2UV3", "liquidityUV2_HEXUSDC", "liquidityUV2_USDC",
+        "liquidityUV2_HEXETH", "liquidityUV2_ETH", "liquidityUV3_HEX", "liquidityUV3_USDC",
+        "liquidityUV3_ETH", "liquidityUV2UV3_HEX", "liquidityUV2UV3_USDC",
+        "liquidityUV2UV3_ETH", "liquidityPulseX_HEX", "liquidityPulseX_PLS",
+        "liquidityPulseX_EHEX", "priceChangePulseX", "tshareRateIncrease", "tshareRateUSD",
+        "tshareMarketCap", "tshareMarketCapToMarketCapRatio", "marketCap", "totalValueLocked",
+        "dailyPayoutUSD", "stakedSupplyChange", "circulatingSupplyChange", "stakedHEXGA",
+        "stakedHEXGAChange", "stakedHEXPercent", "totalHEX", "roiMultiplierFromATL",
+        "numberOfHolders", "numberOfHoldersChange", "currentHolders", "currentHoldersChange",
+        "currentStakerCount", "currentStakerCountChange", "totalStakerCount",
+        "totalStakerCountChange",
+    },
+}
+
+var schemaDrift = struct {
+    mu       sync.Mutex
+    warnings map[string]string // Source name -> last reported problem
+}{warnings: make(map[string]string)}
+
+// Names of the JSON fields a struct (or a slice of them) decodes, lower case as encoding/json
+// matches them without case
+func decodedFields(t reflect.Type) map[string]bool {
+    for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+        t = t.Elem()
+    }
+    fields := make(map[string]bool)
+    for i := 0; i < t.NumField(); i++ {
+        name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+        if name == "" {
+            name = t.Field(i).Name
+        }
+        fields[strings.ToLower(name)] = true
+    }
+    return fields
+}
+
+// Sorted fields of body, an object or a list of them, that v doesn't decode and known
+// doesn't list
+func unknownFields(body []byte, v any, known []string) ([]string, error) {
+    var objects []map[string]json.RawMessage
+    if strings.HasPrefix(strings.TrimSpace(string(body)), "[") {
+        if err := json.Unmarshal(body, &objects); err != nil {
+            return nil, err
+        }
+    } else {
+        var object map[string]json.RawMessage
+        if err := json.Unmarshal(body, &object); err != nil {
+            return nil, err
+        }
+        objects = append(objects, object)
+    }
+    expected := decodedFields(reflect.TypeOf(v))
+    for _, field := range known {
+        expected[strings.ToLower(field)] = true
+    }
+    var unknown []string
+    for _, object := range objects {
+        for field := range object {
+            if !expected[strings.ToLower(field)] && !contains(unknown, field) {
+                unknown = append(unknown, field)
+            }
+        }
+    }
+    sort.Strings(unknown)
+    return unknown, nil
+}
+
+// Records whether body from source matches v, does nothing unless strict decode is on
+func reportSchemaDrift(source string, body []byte, v any) {
+    if !configManager.GetConfig().StrictDecode {
+        return
+    }
+    unknown, err := unknownFields(body, v, knownAPIFields[source])
+    if err == nil && len(unknown) > 0 {
+        err = fmt.Errorf("unknown fields %s", strings.Join(unknown, ", "))
+    }
+    schemaDrift.mu.Lock()
+    defer schemaDrift.mu.Unlock()
+    if err == nil {
+        delete(schemaDrift.warnings, source)
+        return
+    }
+    if schemaDrift.warnings[source] != err.Error() {
+        log.Println("Warning: unexpected", source, "format, a newer version may be needed:", err)
+    }
+    schemaDrift.warnings[source] = err.Error()
+}
+
+func clearSchemaDrift() {
+    schemaDrift.mu.Lock()
+    defer schemaDrift.mu.Unlock()
+    schemaDrift.warnings = make(map[string]string)
+}
+
+// Note shown on the Live Data tab while any source has drifted
+func schemaDriftNote() string {
+    schemaDrift.mu.Lock()
+    defer schemaDrift.mu.Unlock()
+    if len(schemaDrift.warnings) == 0 {
+        return ""
+    }
+    var sources []string
+    for source := range schemaDrift.warnings {
+        sources = append(sources, source)
+    }
+    sort.Strings(sources)
+    return "API format changed (" + strings.Join(sources, ", ") + "), a newer version may be needed"
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestUnknownFields(t *testing.T) {
+    tests := []struct {
+        body  string
+        known []string
+        want  []string
+    }{
+        {`{"price": 0.01, "BEAT": 5}`, nil, nil}, // Matched without case like encoding/json
+        {`{"price": 0.01, "stakedHEX": 1}`, []string{"stakedHEX"}, nil},
+        {`{"price": 0.01, "newField": 1, "another": 2}`, nil, []string{"another", "newField"}},
+        {`[{"currentDay": 1, "extra": 1}, {"currentDay": 2, "extra": 2}]`, nil, []string{"extra"}},
+    }
+    for _, tt := range tests {
+        var v any = &LiveData{}
+        if strings.HasPrefix(tt.body, "[") {
+            v = &HEXJSON{}
+        }
+        got, err := unknownFields([]byte(tt.body), v, tt.known)
+        if err != nil {
+            t.Errorf("unknownFields(%s): %v", tt.body, err)
+            continue
+        }
+        if !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("unknownFields(%s) = %q, want %q", tt.body, got, tt.want)
+        }
+    }
+    if _, err := unknownFields([]byte(`"text"`), &LiveData{}, nil); err == nil {
+        t.Error("unknownFields accepted a response that isn't an object")
+    }
+}
+
+func TestReportSchemaDrift(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.StrictDecode = true
+    })
+    clearSchemaDrift()
+    t.Cleanup(clearSchemaDrift)
+
+    // What the API sends today, fields the app doesn't use included
+    reportSchemaDrift("live data", []byte(`{"price_Pulsechain": 0.01, "stakedHEX_Pulsechain": 1, "beat": 5}`), &LiveData{})
+    reportSchemaDrift("history", []byte(`[{"currentDay": 1, "pricePulseX": 0.01, "totalTshares": 5, "date": "x"}]`), &HEXJSON{})
+    if note := schemaDriftNote(); note != "" {
+        t.Errorf("known fields were reported: %q", note)
+    }
+
+    reportSchemaDrift("live data", []byte(`{"price_Pulsechain": 0.01, "tsharePriceV2": 250}`), &LiveData{})
+    if note := schemaDriftNote(); !strings.Contains(note, "live data") {
+        t.Errorf("note = %q, want the new live data field reported", note)
+    }
+    reportSchemaDrift("live data", []byte(`{"price_Pulsechain": 0.01}`), &LiveData{})
+    if note := schemaDriftNote(); note != "" {
+        t.Errorf("note = %q after the format matched again, want none", note)
+    }
+
+    useConfig(t, nil)
+    reportSchemaDrift("live data", []byte(`{"tsharePriceV2": 250}`), &LiveData{})
+    if note := schemaDriftNote(); note != "" {
+        t.Errorf("drift reported with strict decode off: %q", note)
+    }
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+    "fmt"
+    "math"
+)
+
+// Live data at the first fetch of this session (or the last reset), under liveDataMutex.
+// Changes since then show how values moved while the app has been open.
+var (
+    sessionBaseline    LiveData
+    hasSessionBaseline bool
+)
+
+// Records the baseline from the first fetch with prices, call with liveDataMutex held
+func captureSessionBaseline(data LiveData) {
+    if !hasSessionBaseline && hasLiveData(data) {
+        sessionBaseline, hasSessionBaseline = data, true
+    }
+}
+
+// Starts the session changes over from the current live data
+func resetSessionBaseline() {
+    liveDataMutex.Lock()
+    defer liveDataMutex.Unlock()
+    hasSessionBaseline = false
+    captureSessionBaseline(latestLiveData)
+}
+
+func sessionBaselineData() (LiveData, bool) {
+    liveDataMutex.Lock()
+    defer liveDataMutex.Unlock()
+    return sessionBaseline, hasSessionBaseline
+}
+
+// Relative change from baseline to current, ok is false without a baseline price
+func sinceLaunchChange(baseline, current float64) (float64, bool) {
+    if baseline <= 0 {
+        return 0, false
+    }
+    return (current - baseline) / baseline, true
+}
+
+func formatSignedPercent(fraction float64) string {
+    if math.Abs(fraction) < 0.0005 {
+        return "0.0%"
+    }
+    return fmt.Sprintf("%+.1f%%", fraction*100)
+}
+
+// Holdings line: the current T-Shares valued at the baseline and at the current prices, so
+// adding or ending a miner during the session doesn't show up as a market move
+func sinceLaunchValueText(baselineValue, currentValue float64) string {
+    change, ok := sinceLaunchChange(baselineValue, currentValue)
+    if !ok {
+        return "Since launch: -"
+    }
+    delta := currentValue - baselineValue
+    sign := "+"
+    if delta < 0 {
+        sign = "-"
+    }
+    return fmt.Sprintf("Since launch: %s / %s%s", formatSignedPercent(change), sign, maskPrivate(formatMoney(math.Abs(delta), 2)))
+}
+
+// Live Data line with the price changes
+func sinceLaunchPricesText(baseline, current chainLiveData) string {
+    price, ok := sinceLaunchChange(baseline.Price, current.Price)
+    tsharePrice, tshareOK := sinceLaunchChange(baseline.TsharePrice, current.TsharePrice)
+    if !ok || !tshareOK {
+        return "Since launch: -"
+    }
+    return fmt.Sprintf("Since launch: Price %s, T-Share Price %s", formatSignedPercent(price), formatSignedPercent(tsharePrice))
+}
@@ -0,0 +1,65 @@
+package main
+
+import "testing"
+
+func TestSinceLaunchChange(t *testing.T) {
+    useConfig(t, nil)
+    tests := []struct {
+        baseline float64
+        current  float64
+        want     string
+    }{
+        {1000, 1100, "Since launch: +10.0% / +$100.00"},
+        {1000, 950, "Since launch: -5.0% / -$50.00"},
+        {1000, 1000, "Since launch: 0.0% / +$0.00"},
+        {0, 500, "Since launch: -"}, // No baseline price yet
+    }
+    for _, tt := range tests {
+        if got := sinceLaunchValueText(tt.baseline, tt.current); got != tt.want {
+            t.Errorf("sinceLaunchValueText(%v, %v) = %q, want %q", tt.baseline, tt.current, got, tt.want)
+        }
+    }
+
+    baseline := chainLiveData{Price: 0.02, TsharePrice: 200}
+    if got, want := sinceLaunchPricesText(baseline, chainLiveData{Price: 0.025, TsharePrice: 180}), "Since launch: Price +25.0%, T-Share Price -10.0%"; got != want {
+        t.Errorf("sinceLaunchPricesText = %q, want %q", got, want)
+    }
+    if got := sinceLaunchPricesText(chainLiveData{Price: 0.02}, chainLiveData{Price: 0.025, TsharePrice: 180}); got != "Since launch: -" {
+        t.Errorf("sinceLaunchPricesText without a baseline T-Share price = %q", got)
+    }
+
+    useConfig(t, func(c *Config) {
+        c.PrivacyMode = true
+    })
+    if got := sinceLaunchValueText(1000, 1100); got != "Since launch: +10.0% / +"+privacyMask {
+        t.Errorf("privacy mode = %q, want the amount masked", got)
+    }
+}
+
+// The baseline is the first fetch with prices and only moves on a reset
+func TestSessionBaseline(t *testing.T) {
+    liveDataMutex.Lock()
+    previous, hadPrevious := sessionBaseline, hasSessionBaseline
+    hasSessionBaseline = false
+    liveDataMutex.Unlock()
+    t.Cleanup(func() {
+        liveDataMutex.Lock()
+        sessionBaseline, hasSessionBaseline = previous, hadPrevious
+        liveDataMutex.Unlock()
+    })
+
+    liveDataMutex.Lock()
+    captureSessionBaseline(LiveData{}) // Failed fetch, no prices
+    captureSessionBaseline(LiveData{TsharePricePulsechain: 200})
+    captureSessionBaseline(LiveData{TsharePricePulsechain: 300})
+    liveDataMutex.Unlock()
+    if baseline, ok := sessionBaselineData(); !ok || baseline.TsharePricePulsechain != 200 {
+        t.Errorf("baseline = %v (%v), want the first fetch with prices", baseline.TsharePricePulsechain, ok)
+    }
+
+    useLiveData(t, LiveData{TsharePricePulsechain: 300})
+    resetSessionBaseline()
+    if baseline, ok := sessionBaselineData(); !ok || baseline.TsharePricePulsechain != 300 {
+        t.Errorf("baseline after a reset = %v (%v), want the current live data", baseline.TsharePricePulsechain, ok)
+    }
+}
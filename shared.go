@@ -0,0 +1,96 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Shared portfolio: a miners JSON hosted at a URL, shown read-only and never saved
+
+func fetchSharedMiners(url string) ([]Miner, error) {
+    resp, err := httpGet(url)
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("unexpected status %s", resp.Status)
+    }
+    var miners []Miner
+    err = json.NewDecoder(resp.Body).Decode(&miners)
+    if err != nil {
+        return nil, fmt.Errorf("not a miners list: %v", err)
+    }
+    if err := validateMiners(miners); err != nil {
+        return nil, err
+    }
+    return miners, nil
+}
+
+// Checks every miner has valid dates and positive T-Shares
+func validateMiners(miners []Miner) error {
+    for i, miner := range miners {
+        if err := validateMiner(miner); err != nil {
+            return fmt.Errorf("miner %d: %v", i+1, err)
+        }
+    }
+    return nil
+}
+
+func validateMiner(miner Miner) error {
+    start, err := time.Parse(dateLayout, miner.StartDate)
+    if err != nil {
+        return fmt.Errorf("invalid start date %q", miner.StartDate)
+    }
+    end, err := time.Parse(dateLayout, miner.EndDate)
+    if err != nil {
+        return fmt.Errorf("invalid end date %q", miner.EndDate)
+    }
+    if end.Before(start) {
+        return fmt.Errorf("end date is before start date")
+    }
+    if miner.TShares <= 0 {
+        return fmt.Errorf("T-Shares must be positive")
+    }
+    if miner.PrincipalHEX < 0 {
+        return fmt.Errorf("principal HEX can't be negative")
+    }
+    if miner.Status != "" && miner.Status != "completed" {
+        return fmt.Errorf("unknown status %q", miner.Status)
+    }
+    if miner.Chain != "" && !isChain(miner.Chain) {
+        return fmt.Errorf("unknown chain %q", miner.Chain)
+    }
+    return nil
+}
+
+func showSharedPortfolioWindow(url string, w fyne.Window) {
+    go func() {
+        miners, err := fetchSharedMiners(url)
+        fyne.Do(func() {
+            if err != nil {
+                log.Println("Error loading shared portfolio:", err)
+                showError(fmt.Errorf("Failed to load shared portfolio: %v", err), w)
+                return
+            }
+            sharedWindow := fyne.CurrentApp().NewWindow("Shared Portfolio (read-only)")
+            sharedWindow.Resize(fyne.NewSize(700, 500))
+            banner := widget.NewLabel(fmt.Sprintf("Shared portfolio (read-only) from %s", url))
+            banner.TextStyle = fyne.TextStyle{Bold: true}
+            banner.Wrapping = fyne.TextWrapBreak
+            profile := createProfileTab(miners, sharedWindow, nil, true)
+            sharedWindow.SetContent(container.NewBorder(banner, nil, nil, nil, container.NewVScroll(profile)))
+            sharedWindow.SetOnClosed(func() {
+                stopTabWork(profile)
+            })
+            sharedWindow.Show()
+        })
+    }()
+}
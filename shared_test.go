@@ -0,0 +1,99 @@
+package main
+
+import (
+    "net/http"
+    "net/http/httptest"
+    "testing"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/test"
+    "fyne.io/fyne/v2/widget"
+)
+
+func TestFetchSharedMiners(t *testing.T) {
+    useConfig(t, nil)
+    responses := map[string]string{
+        "/ok":       `[{"startDate": "01-01-2025", "endDate": "01-01-2026", "tShares": 10}]`,
+        "/object":   `{"startDate": "01-01-2025"}`,
+        "/baddate":  `[{"startDate": "2025-01-01", "endDate": "01-01-2026", "tShares": 10}]`,
+        "/noshares": `[{"startDate": "01-01-2025", "endDate": "01-01-2026"}]`,
+    }
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        body, ok := responses[r.URL.Path]
+        if !ok {
+            http.NotFound(w, r)
+            return
+        }
+        w.Write([]byte(body))
+    }))
+    defer server.Close()
+
+    miners, err := fetchSharedMiners(server.URL + "/ok")
+    if err != nil || len(miners) != 1 || miners[0].TShares != 10 {
+        t.Errorf("fetchSharedMiners(/ok) = %+v, %v", miners, err)
+    }
+    for _, path := range []string{"/object", "/baddate", "/noshares", "/missing"} {
+        if _, err := fetchSharedMiners(server.URL + path); err == nil {
+            t.Errorf("fetchSharedMiners(%s) accepted the response", path)
+        }
+    }
+}
+
+// Buttons and checks of a tab, which are everything that could save
+func tabControls(object fyne.CanvasObject) (buttons []string, checks []string) {
+    switch o := object.(type) {
+    case *fyne.Container:
+        for _, child := range o.Objects {
+            b, c := tabControls(child)
+            buttons = append(buttons, b...)
+            checks = append(checks, c...)
+        }
+    case *widget.Button:
+        buttons = append(buttons, o.Text)
+    case *widget.Check:
+        checks = append(checks, o.Text)
+    }
+    return buttons, checks
+}
+
+func TestSharedProfileReadOnly(t *testing.T) {
+    useTestApp(t)
+    useConfig(t, nil)
+    useTempStorage(t)
+    miners := []Miner{
+        {ID: "a", StartDate: "01-01-2020", EndDate: "01-01-2021", TShares: 10}, // Matured, has END when writable
+        {ID: "b", StartDate: "01-01-2025", EndDate: "01-01-2040", TShares: 2},
+        {ID: "c", StartDate: "01-01-2019", EndDate: "01-01-2020", TShares: 1, Status: "completed"},
+        {ID: "d", StartDate: "bad", EndDate: "01-01-2040", TShares: 1}, // Has Edit when writable
+    }
+    w := test.NewWindow(nil)
+    defer w.Close()
+
+    buttons, checks := tabControls(createProfileTab(miners, w, func() {}, false))
+    for _, want := range []string{"END", "Edit", "Generate Report", "Copy Summary"} {
+        if !contains(buttons, want) {
+            t.Fatalf("writable profile has no %q button, the test doesn't cover it (buttons %q)", want, buttons)
+        }
+    }
+    if len(checks) == 0 {
+        t.Fatal("writable profile has no checks")
+    }
+
+    buttons, checks = tabControls(createProfileTab(miners, w, nil, true))
+    for _, button := range buttons {
+        switch button {
+        case "View Completed Miners (1)", "View Stake Ladder", "Previous", "Next", "Recompute", "":
+        default:
+            t.Errorf("read-only profile has a %q button", button)
+        }
+    }
+    if len(checks) != 0 {
+        t.Errorf("read-only profile has checks %q, they save the config", checks)
+    }
+    if data, _ := store.ReadMiners(""); data != nil {
+        t.Error("showing a shared portfolio saved miners")
+    }
+    if data, _ := store.ReadConfig(); data != nil {
+        t.Error("showing a shared portfolio saved the config")
+    }
+}
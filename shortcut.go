@@ -0,0 +1,38 @@
+package main
+
+import (
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/driver/desktop"
+)
+
+// Shortcut jumping to the Add New Miner form, Ctrl is Cmd on macOS
+var addMinerShortcuts = []string{"Ctrl+N", "Ctrl+Shift+N", "Off"}
+
+// Set by the Settings tab: scrolls to the Add New Miner form and focuses the start date,
+// Space then opens the calendar
+var focusNewMinerForm func()
+
+func addMinerShortcut(option string) *desktop.CustomShortcut {
+    switch option {
+    case "Ctrl+N":
+        return &desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierShortcutDefault}
+    case "Ctrl+Shift+N":
+        return &desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+    }
+    return nil
+}
+
+// Replaces the add miner shortcut registered on c (previous may be nil) with the one picked
+// by option, open runs when it is pressed. Returns the new shortcut, nil when it is off.
+func registerAddMinerShortcut(c fyne.Canvas, previous *desktop.CustomShortcut, option string, open func()) *desktop.CustomShortcut {
+    if previous != nil {
+        c.RemoveShortcut(previous)
+    }
+    shortcut := addMinerShortcut(option)
+    if shortcut != nil {
+        c.AddShortcut(shortcut, func(_ fyne.Shortcut) {
+            open()
+        })
+    }
+    return shortcut
+}
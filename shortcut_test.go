@@ -0,0 +1,71 @@
+package main
+
+import (
+    "testing"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/driver/desktop"
+    "fyne.io/fyne/v2/test"
+    "fyne.io/fyne/v2/widget"
+)
+
+// The shortcut opens the Settings tab with the focus on the new miner's start date
+func TestAddMinerShortcut(t *testing.T) {
+    useTestApp(t)
+    useConfig(t, nil)
+    useTempStorage(t)
+    w := test.NewWindow(nil)
+    defer w.Close()
+    previousFocus := focusNewMinerForm
+    t.Cleanup(func() {
+        focusNewMinerForm = previousFocus
+    })
+
+    profileTab := container.NewTabItem("Profile", widget.NewLabel(""))
+    settingsTab := container.NewTabItem("Settings", createSettingsTab(nil, w, func() {}))
+    tabs := container.NewAppTabs(profileTab, settingsTab)
+    w.SetContent(tabs)
+    open := func() {
+        tabs.Select(settingsTab)
+        focusNewMinerForm()
+    }
+    press := func(shortcut fyne.Shortcut) {
+        w.Canvas().(interface{ TypedShortcut(fyne.Shortcut) }).TypedShortcut(shortcut)
+    }
+    ctrlN := &desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierShortcutDefault}
+    ctrlShiftN := &desktop.CustomShortcut{KeyName: fyne.KeyN, Modifier: fyne.KeyModifierShortcutDefault | fyne.KeyModifierShift}
+
+    registered := registerAddMinerShortcut(w.Canvas(), nil, "Ctrl+N", open)
+    press(ctrlShiftN)
+    if tabs.Selected() != profileTab {
+        t.Error("another shortcut opened the form")
+    }
+    press(ctrlN)
+    if tabs.Selected() != settingsTab {
+        t.Fatal("Ctrl+N didn't switch to the Settings tab")
+    }
+    if _, ok := w.Canvas().Focused().(*widget.Button); !ok {
+        t.Errorf("focus is on %T, want the start date button", w.Canvas().Focused())
+    }
+
+    // Changing the setting replaces the old shortcut, Off removes it
+    tabs.Select(profileTab)
+    registered = registerAddMinerShortcut(w.Canvas(), registered, "Ctrl+Shift+N", open)
+    press(ctrlN)
+    if tabs.Selected() != profileTab {
+        t.Error("the replaced shortcut still opens the form")
+    }
+    press(ctrlShiftN)
+    if tabs.Selected() != settingsTab {
+        t.Error("Ctrl+Shift+N didn't open the form")
+    }
+    tabs.Select(profileTab)
+    if registered = registerAddMinerShortcut(w.Canvas(), registered, "Off", open); registered != nil {
+        t.Error("Off registered a shortcut")
+    }
+    press(ctrlShiftN)
+    if tabs.Selected() != profileTab {
+        t.Error("the shortcut still works with it off")
+    }
+}
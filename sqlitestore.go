@@ -0,0 +1,224 @@
+package main
+
+import (
+    "database/sql"
+    "fmt"
+    "log"
+
+    _ "modernc.org/sqlite"
+)
+
+// SQLite storage: every write is one transaction, so a crash leaves the previous state
+// instead of a half-written file. The history is a row per chain and day.
+
+const sqliteStoragePath = "settings/hexfetch.db"
+
+// Schema changes in order, user_version is the number of them applied
+var sqliteMigrations = []string{
+    `CREATE TABLE config (id INTEGER PRIMARY KEY CHECK (id = 1), data BLOB NOT NULL);
+    CREATE TABLE miners (portfolio TEXT PRIMARY KEY, data BLOB NOT NULL);
+    CREATE TABLE history (
+        chain TEXT NOT NULL,
+        day INTEGER NOT NULL,
+        tshare_rate_hex REAL NOT NULL,
+        daily_payout_hex REAL NOT NULL,
+        price_pulsex REAL NOT NULL,
+        price_uv2uv3 REAL NOT NULL,
+        PRIMARY KEY (chain, day)
+    );`,
+}
+
+type sqliteStorage struct {
+    db *sql.DB
+    tx *sql.Tx // Set while the JSON files are imported in the first migration
+}
+
+// What the queries run on, *sql.DB or the import's *sql.Tx
+type sqlConn interface {
+    Exec(query string, args ...interface{}) (sql.Result, error)
+    Query(query string, args ...interface{}) (*sql.Rows, error)
+    QueryRow(query string, args ...interface{}) *sql.Row
+}
+
+func (s *sqliteStorage) conn() sqlConn {
+    if s.tx != nil {
+        return s.tx
+    }
+    return s.db
+}
+
+func openSQLiteStorage(path string) (*sqliteStorage, error) {
+    db, err := sql.Open("sqlite", "file:"+path+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+    if err != nil {
+        return nil, err
+    }
+    db.SetMaxOpenConns(1) // One writer, the app has no use for more
+    s := &sqliteStorage{db: db}
+    if err := s.migrate(jsonStorage{}); err != nil {
+        db.Close()
+        return nil, fmt.Errorf("migrating %s: %v", path, err)
+    }
+    return s, nil
+}
+
+// Applies the migrations not applied yet in one transaction. A new database imports files in
+// the same one, so a failed import commits nothing and is tried again on the next start.
+func (s *sqliteStorage) migrate(files jsonStorage) error {
+    var version int
+    if err := s.db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+        return err
+    }
+    if version > len(sqliteMigrations) {
+        return fmt.Errorf("database is from a newer version (schema %d)", version)
+    }
+    if version == len(sqliteMigrations) {
+        return nil
+    }
+    tx, err := s.db.Begin()
+    if err != nil {
+        return err
+    }
+    for i := version; i < len(sqliteMigrations); i++ {
+        if _, err := tx.Exec(sqliteMigrations[i]); err != nil {
+            tx.Rollback()
+            return err
+        }
+    }
+    if version == 0 {
+        if err := importJSONFiles(&sqliteStorage{db: s.db, tx: tx}, files); err != nil {
+            tx.Rollback()
+            return fmt.Errorf("importing the JSON files: %v", err)
+        }
+    }
+    if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", len(sqliteMigrations))); err != nil {
+        tx.Rollback()
+        return err
+    }
+    return tx.Commit()
+}
+
+// Copies what the JSON files hold into a new database, the files are left as a backup
+func importJSONFiles(s Storage, files jsonStorage) error {
+    if data, err := files.ReadConfig(); err != nil {
+        return err
+    } else if data != nil {
+        if err := s.WriteConfig(data); err != nil {
+            return err
+        }
+    }
+    portfolios, err := listPortfolios()
+    if err != nil {
+        return err
+    }
+    for _, portfolio := range append([]string{""}, portfolios...) {
+        data, err := files.ReadMiners(portfolio)
+        if err != nil {
+            return err
+        }
+        if data != nil {
+            if err := s.WriteMiners(portfolio, data); err != nil {
+                return err
+            }
+        }
+    }
+    for _, chain := range chains {
+        history, err := files.ReadHistory(chain)
+        if err != nil {
+            return err
+        }
+        if err := s.AddHistory(chain, history); err != nil {
+            return err
+        }
+    }
+    log.Println("Imported the JSON settings, miners and history into", sqliteStoragePath)
+    return nil
+}
+
+// Blob in column data of the row matching query, nil when there is none
+func (s *sqliteStorage) readBlob(query string, args ...interface{}) ([]byte, error) {
+    var data []byte
+    err := s.conn().QueryRow(query, args...).Scan(&data)
+    if err == sql.ErrNoRows {
+        return nil, nil
+    }
+    return data, err
+}
+
+func (s *sqliteStorage) ReadConfig() ([]byte, error) {
+    return s.readBlob("SELECT data FROM config WHERE id = 1")
+}
+
+func (s *sqliteStorage) WriteConfig(data []byte) error {
+    _, err := s.conn().Exec("INSERT INTO config (id, data) VALUES (1, ?) ON CONFLICT (id) DO UPDATE SET data = excluded.data", data)
+    return err
+}
+
+func (s *sqliteStorage) ReadMiners(portfolio string) ([]byte, error) {
+    return s.readBlob("SELECT data FROM miners WHERE portfolio = ?", portfolio)
+}
+
+func (s *sqliteStorage) WriteMiners(portfolio string, data []byte) error {
+    _, err := s.conn().Exec("INSERT INTO miners (portfolio, data) VALUES (?, ?) ON CONFLICT (portfolio) DO UPDATE SET data = excluded.data", portfolio, data)
+    return err
+}
+
+func (s *sqliteStorage) RenameMiners(from, to string) error {
+    _, err := s.conn().Exec("UPDATE miners SET portfolio = ? WHERE portfolio = ?", to, from)
+    return err
+}
+
+func (s *sqliteStorage) DeleteMiners(portfolio string) error {
+    _, err := s.conn().Exec("DELETE FROM miners WHERE portfolio = ?", portfolio)
+    return err
+}
+
+func (s *sqliteStorage) ReadHistory(chain string) (HEXJSON, error) {
+    rows, err := s.conn().Query("SELECT day, tshare_rate_hex, daily_payout_hex, price_pulsex, price_uv2uv3 FROM history WHERE chain = ? ORDER BY day DESC", chain)
+    if err != nil {
+        return HEXJSON{}, err
+    }
+    defer rows.Close()
+    history := HEXJSON{}
+    for rows.Next() {
+        var entry HEXJSONEntry
+        if err := rows.Scan(&entry.CurrentDay, &entry.TshareRateHEX, &entry.DailyPayoutHEX, &entry.PricePulseX, &entry.PriceUV2UV3); err != nil {
+            return HEXJSON{}, err
+        }
+        history = append(history, entry)
+    }
+    return history, rows.Err()
+}
+
+// Days already stored are replaced, all entries are written or none
+func (s *sqliteStorage) AddHistory(chain string, entries HEXJSON) error {
+    if s.tx != nil {
+        return insertHistory(s.tx, chain, entries)
+    }
+    tx, err := s.db.Begin()
+    if err != nil {
+        return err
+    }
+    if err := insertHistory(tx, chain, entries); err != nil {
+        tx.Rollback()
+        return err
+    }
+    return tx.Commit()
+}
+
+func insertHistory(tx *sql.Tx, chain string, entries HEXJSON) error {
+    stmt, err := tx.Prepare("INSERT OR REPLACE INTO history (chain, day, tshare_rate_hex, daily_payout_hex, price_pulsex, price_uv2uv3) VALUES (?, ?, ?, ?, ?, ?)")
+    if err != nil {
+        return err
+    }
+    defer stmt.Close()
+    for _, entry := range entries {
+        if _, err := stmt.Exec(chain, entry.CurrentDay, entry.TshareRateHEX, entry.DailyPayoutHEX, entry.PricePulseX, entry.PriceUV2UV3); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+func (s *sqliteStorage) Close() error {
+    return s.db.Close()
+}
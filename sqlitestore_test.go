@@ -0,0 +1,78 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "testing"
+)
+
+// A new database takes in the JSON files, an import that fails commits nothing so the next
+// start tries again
+func TestSQLiteImportJSONFiles(t *testing.T) {
+    useConfig(t, nil)
+    useTempStorage(t)
+    files := jsonStorage{}
+    history := HEXJSON{{CurrentDay: 2, PricePulseX: 0.02}, {CurrentDay: 1, PricePulseX: 0.01}}
+    if err := files.WriteMiners("", []byte(`[{"id":"a"}]`)); err != nil {
+        t.Fatal(err)
+    }
+    if err := files.AddHistory(chainPulsechain, history); err != nil {
+        t.Fatal(err)
+    }
+    os.MkdirAll(configFilePath, 0755) // Unreadable as a file
+    path := filepath.Join("settings", "test.db")
+
+    if s, err := openSQLiteStorage(path); err == nil {
+        s.Close()
+        t.Fatal("opened the database although the import failed")
+    }
+    os.Remove(configFilePath)
+    if err := files.WriteConfig([]byte(`{"chain":"pulsechain"}`)); err != nil {
+        t.Fatal(err)
+    }
+    s, err := openSQLiteStorage(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer s.Close()
+    if data, err := s.ReadConfig(); err != nil || string(data) != `{"chain":"pulsechain"}` {
+        t.Errorf("config = %s, %v, want the imported file", data, err)
+    }
+    if data, err := s.ReadMiners(""); err != nil || string(data) != `[{"id":"a"}]` {
+        t.Errorf("miners = %s, %v, want the imported file", data, err)
+    }
+    if got, err := s.ReadHistory(chainPulsechain); err != nil || !reflect.DeepEqual(got, history) {
+        t.Errorf("history = %+v, %v, want %+v", got, err, history)
+    }
+
+    // Reopening doesn't import again over what was written since
+    if err := s.WriteMiners("", []byte(`[]`)); err != nil {
+        t.Fatal(err)
+    }
+    s.Close()
+    s, err = openSQLiteStorage(path)
+    if err != nil {
+        t.Fatal(err)
+    }
+    defer s.Close()
+    if data, _ := s.ReadMiners(""); string(data) != `[]` {
+        t.Errorf("miners after reopening = %s, want the ones written after the import", data)
+    }
+}
+
+// Once imported the JSON files are a stale backup and aren't used again
+func TestOpenStorageJSONAfterImport(t *testing.T) {
+    useTempStorage(t)
+    s, err := openStorage("json")
+    if err != nil {
+        t.Fatalf("json storage without a database: %v", err)
+    }
+    s.Close()
+    if err := os.WriteFile(sqliteStoragePath, nil, 0644); err != nil {
+        t.Fatal(err)
+    }
+    if _, err := openStorage("json"); err == nil {
+        t.Error("opened the JSON files although they were imported into the database")
+    }
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "os"
+    "path/filepath"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/app"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Where the config, the miners of each portfolio and the history of each chain are kept.
+// The SQLite database is the default, the JSON files of older versions are still read and
+// written with -storage json. Journals and archives stay files next to the portfolio.
+type Storage interface {
+    ReadConfig() ([]byte, error) // nil while nothing is stored
+    WriteConfig(data []byte) error
+    ReadMiners(portfolio string) ([]byte, error) // nil while the portfolio has no miners saved
+    WriteMiners(portfolio string, data []byte) error
+    RenameMiners(from, to string) error
+    DeleteMiners(portfolio string) error
+    ReadHistory(chain string) (HEXJSON, error) // Newest day first, like the API
+    AddHistory(chain string, entries HEXJSON) error
+    Close() error
+}
+
+var storageBackends = []string{"sqlite", "json"}
+
+// Set up in main before the config is loaded
+var store Storage
+
+func openStorage(backend string) (Storage, error) {
+    if backend == "json" {
+        // After the import the files are a stale backup, edits to them would be lost
+        if _, err := os.Stat(sqliteStoragePath); err == nil {
+            return nil, fmt.Errorf("the JSON files were imported into %s, move it away to use -storage json again", sqliteStoragePath)
+        }
+        return jsonStorage{}, nil
+    }
+    return openSQLiteStorage(sqliteStoragePath)
+}
+
+// Shown instead of the app when the storage can't be opened, running on the JSON backup
+// would let the two diverge
+func showStorageError(err error) {
+    a := app.New()
+    w := a.NewWindow("HEX Stats")
+    message := widget.NewLabel(fmt.Sprintf("HEX Stats could not open its storage: %v", err))
+    message.Wrapping = fyne.TextWrapWord
+    w.SetContent(container.NewVBox(
+        message,
+        widget.NewButton("OK", a.Quit),
+    ))
+    w.Resize(fyne.NewSize(500, 150))
+    w.ShowAndRun()
+}
+
+// The files used before the database, settings/config.json, miners.json per portfolio
+// directory and data/hexjson*.json
+type jsonStorage struct{}
+
+const configFilePath = "settings/config.json"
+
+// Contents of path, nil when it doesn't exist
+func readOptionalFile(path string) ([]byte, error) {
+    data, err := os.ReadFile(path)
+    if os.IsNotExist(err) {
+        return nil, nil
+    }
+    return data, err
+}
+
+func (jsonStorage) ReadConfig() ([]byte, error) {
+    return readOptionalFile(configFilePath)
+}
+
+func (jsonStorage) WriteConfig(data []byte) error {
+    return writeFileSynced(configFilePath, data)
+}
+
+func (jsonStorage) ReadMiners(portfolio string) ([]byte, error) {
+    return readOptionalFile(filepath.Join(portfolioDir(portfolio), "miners.json"))
+}
+
+func (jsonStorage) WriteMiners(portfolio string, data []byte) error {
+    return writeFileSynced(filepath.Join(portfolioDir(portfolio), "miners.json"), data)
+}
+
+// The file already moved with the portfolio directory
+func (jsonStorage) RenameMiners(from, to string) error {
+    return nil
+}
+
+func (jsonStorage) DeleteMiners(portfolio string) error {
+    err := os.Remove(filepath.Join(portfolioDir(portfolio), "miners.json"))
+    if os.IsNotExist(err) {
+        return nil
+    }
+    return err
+}
+
+func (jsonStorage) ReadHistory(chain string) (HEXJSON, error) {
+    data, err := readOptionalFile(historyFilePath(chain))
+    if err != nil || data == nil {
+        return HEXJSON{}, err
+    }
+    var history HEXJSON
+    if err := json.Unmarshal(data, &history); err != nil {
+        return HEXJSON{}, err
+    }
+    return history, nil
+}
+
+// Entries are newer than the stored days, so they go in front
+func (s jsonStorage) AddHistory(chain string, entries HEXJSON) error {
+    history, err := s.ReadHistory(chain)
+    if err != nil {
+        return err
+    }
+    data, err := json.MarshalIndent(append(entries, history...), "", "  ")
+    if err != nil {
+        return err
+    }
+    return writeFileSynced(historyFilePath(chain), append(data, '\n'))
+}
+
+func (jsonStorage) Close() error {
+    return nil
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+    "bufio"
+    "context"
+    "encoding/json"
+    "fmt"
+    "io"
+    "log"
+    "net/http"
+    "strings"
+    "sync/atomic"
+    "time"
+)
+
+// Optional push-based live data over Server-Sent Events. While the stream is connected
+// the polling loop skips its live data fetch, when it drops polling takes over again.
+
+var liveStreamConnected atomic.Bool
+
+const streamMinBackoff = time.Second
+const streamMaxBackoff = 5 * time.Minute
+
+// Calls onEvent with the data of each event, multi-line data is joined with newlines
+func readSSE(r io.Reader, onEvent func(data string)) error {
+    scanner := bufio.NewScanner(r)
+    var data []string
+    for scanner.Scan() {
+        line := scanner.Text()
+        switch {
+        case line == "":
+            if len(data) > 0 {
+                onEvent(strings.Join(data, "\n"))
+                data = nil
+            }
+        case strings.HasPrefix(line, ":"):
+            // Comment, used as keep-alive
+        case strings.HasPrefix(line, "data:"):
+            data = append(data, strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+        }
+    }
+    if err := scanner.Err(); err != nil {
+        return err
+    }
+    return io.EOF
+}
+
+// Holds one stream connection until it drops. Not routed through httpGet since
+// the connection stays open and would keep a request slot forever.
+func streamLiveData(ctx context.Context, url string, onData func(LiveData)) error {
+    if configManager.GetConfig().OfflineMode {
+        return errOffline // Retried with backoff, so it reconnects once offline mode is off
+    }
+    req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+    if err != nil {
+        return err
+    }
+    req.Header.Set("Accept", "text/event-stream")
+    resp, err := http.DefaultClient.Do(req)
+    if err != nil {
+        return err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return fmt.Errorf("unexpected status %s", resp.Status)
+    }
+    return readSSE(resp.Body, func(message string) {
+        var data LiveData
+        if err := json.Unmarshal([]byte(message), &data); err != nil {
+            log.Println("Error decoding live data event:", err)
+            return
+        }
+        onData(data)
+    })
+}
+
+// Next reconnect delay, doubling up to streamMaxBackoff
+func nextBackoff(current time.Duration) time.Duration {
+    if current < streamMinBackoff {
+        return streamMinBackoff
+    }
+    return min(current*2, streamMaxBackoff)
+}
+
+// Keeps the stream connected until ctx is done, reconnecting with backoff
+func runLiveDataStream(ctx context.Context, url string) {
+    backoff := time.Duration(0)
+    for {
+        err := streamLiveData(ctx, url, func(data LiveData) {
+            liveStreamConnected.Store(true)
+            backoff = 0 // Reset once the stream delivers again
+            setLiveData(data, time.Now())
+        })
+        liveStreamConnected.Store(false)
+        if ctx.Err() != nil {
+            return
+        }
+        backoff = nextBackoff(backoff)
+        log.Println("Live data stream dropped, retrying in", backoff, "-", err)
+        select {
+        case <-time.After(backoff):
+        case <-ctx.Done():
+            return
+        }
+    }
+}
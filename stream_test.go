@@ -0,0 +1,106 @@
+package main
+
+import (
+    "context"
+    "fmt"
+    "io"
+    "net/http"
+    "net/http/httptest"
+    "reflect"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestReadSSE(t *testing.T) {
+    input := ": keep-alive\n\ndata: one\n\ndata: two\ndata:three\n\nevent: ignored\n\ndata: unterminated"
+    var events []string
+    err := readSSE(strings.NewReader(input), func(data string) {
+        events = append(events, data)
+    })
+    if err != io.EOF {
+        t.Errorf("readSSE at the end of the stream returned %v, want io.EOF", err)
+    }
+    if want := []string{"one", "two\nthree"}; !reflect.DeepEqual(events, want) {
+        t.Errorf("events = %q, want %q", events, want)
+    }
+}
+
+func TestNextBackoff(t *testing.T) {
+    backoff := time.Duration(0)
+    var got []time.Duration
+    for i := 0; i < 11; i++ {
+        backoff = nextBackoff(backoff)
+        got = append(got, backoff)
+    }
+    if got[0] != streamMinBackoff || got[1] != 2*streamMinBackoff || got[len(got)-1] != streamMaxBackoff {
+        t.Errorf("backoffs = %v, want doubling from %s up to %s", got, streamMinBackoff, streamMaxBackoff)
+    }
+}
+
+func TestStreamLiveData(t *testing.T) {
+    useConfig(t, nil)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        if r.Header.Get("Accept") != "text/event-stream" {
+            http.Error(w, "not a stream request", http.StatusBadRequest)
+            return
+        }
+        w.Header().Set("Content-Type", "text/event-stream")
+        fmt.Fprint(w, ": hello\n\n")
+        fmt.Fprint(w, "data: {\"tsharePrice_Pulsechain\": 250, \"beat\": 7}\n\n")
+        fmt.Fprint(w, "data: not json\n\n") // Skipped, the stream stays up
+        fmt.Fprint(w, "data: {\"tsharePrice_Pulsechain\": 260}\n\n")
+    }))
+    defer server.Close()
+
+    var received []LiveData
+    err := streamLiveData(context.Background(), server.URL, func(data LiveData) {
+        received = append(received, data)
+    })
+    if err != io.EOF {
+        t.Errorf("streamLiveData returned %v once the server closed, want io.EOF", err)
+    }
+    if len(received) != 2 || received[0].TsharePricePulsechain != 250 || received[0].Beat != 7 || received[1].TsharePricePulsechain != 260 {
+        t.Errorf("received %+v", received)
+    }
+
+    if err := streamLiveData(context.Background(), server.URL+"/missing", func(LiveData) {}); err == nil {
+        t.Error("streamLiveData accepted a server without a stream")
+    }
+}
+
+// Each drop reconnects, and the stream counts as connected only while it delivers
+func TestRunLiveDataStreamReconnects(t *testing.T) {
+    useConfig(t, nil)
+    useLiveData(t, LiveData{})
+    connections := make(chan struct{}, 4)
+    server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+        connections <- struct{}{}
+        fmt.Fprint(w, "data: {\"tsharePrice_Pulsechain\": 250}\n\n")
+    }))
+    defer server.Close()
+
+    ctx, cancel := context.WithCancel(context.Background())
+    done := make(chan struct{})
+    go func() {
+        runLiveDataStream(ctx, server.URL)
+        close(done)
+    }()
+    for i := 0; i < 2; i++ {
+        select {
+        case <-connections:
+        case <-time.After(5 * time.Second):
+            t.Fatalf("stream connected %d times, want a reconnect after the drop", i)
+        }
+    }
+    cancel()
+    <-done
+    if liveStreamConnected.Load() {
+        t.Error("stream still counts as connected after it stopped")
+    }
+    liveDataMutex.Lock()
+    defer liveDataMutex.Unlock()
+    if latestLiveData.TsharePricePulsechain != 250 {
+        t.Errorf("latest live data = %+v, want the streamed values", latestLiveData)
+    }
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+    "fmt"
+    "strings"
+    "time"
+)
+
+// Totals and counts of a portfolio, shared by the Profile tab, the report and the copied summary
+type portfolioSummary struct {
+    ActiveTShares   float64
+    LifetimeTShares float64
+    TotalTShares    float64 // Active T-Shares, plus completed ones when they count toward totals
+    TotalValue      float64 // Total T-Shares at the T-Share price of their chain, in USD
+    ChainTShares    map[string]float64 // Total T-Shares per chain
+    ChainActive     map[string]float64 // Active T-Shares per chain
+    ActiveCount     int
+    MaturedCount    int // Active miners past their end date
+    CompletedCount  int
+    NextMaturity    time.Time // Zero when no active miner ends today or later
+    Gain            float64 // Of completed miners too only when they count toward totals
+    HasGain         bool    // Whether any counted miner has a principal
+    RealizedHEX     float64 // Yield recorded when completed miners were ended
+    ProjectedHEX    float64 // Estimated yield of active miners
+    UnrecordedCount int     // Completed miners ended before yields were recorded
+}
+
+// includeCompleted decides whether completed miners count toward the totals (T-Shares, value, gain)
+func computePortfolioSummary(miners []Miner, data LiveData, now time.Time, includeCompleted bool) portfolioSummary {
+    summary := portfolioSummary{ChainTShares: map[string]float64{}, ChainActive: map[string]float64{}}
+    today := calendarDay(now)
+    for _, miner := range miners {
+        completed := miner.Status == "completed"
+        chain := minerChain(miner)
+        payout := data.Chain(chain).PayoutPerTshare
+        summary.LifetimeTShares += miner.TShares
+        if completed && includeCompleted {
+            summary.TotalTShares += miner.TShares
+            summary.ChainTShares[chain] += miner.TShares
+        }
+        if gain, _, ok := minerGain(miner, payout); ok && (!completed || includeCompleted) {
+            summary.Gain += gain
+            summary.HasGain = true
+        }
+        yield, hasYield := stakeYield(miner, payout)
+        if completed {
+            summary.CompletedCount++
+            if hasYield {
+                summary.RealizedHEX += yield
+            } else {
+                summary.UnrecordedCount++
+            }
+            continue
+        }
+        summary.ProjectedHEX += yield
+        summary.ActiveCount++
+        summary.ActiveTShares += miner.TShares
+        summary.TotalTShares += miner.TShares
+        summary.ChainActive[chain] += miner.TShares
+        summary.ChainTShares[chain] += miner.TShares
+        end, err := time.Parse(dateLayout, miner.EndDate)
+        if err != nil {
+            continue
+        }
+        if state, _ := minerState(miner, now); state.Matured() {
+            summary.MaturedCount++
+        }
+        if end.Before(today) {
+            continue
+        }
+        if summary.NextMaturity.IsZero() || end.Before(summary.NextMaturity) {
+            summary.NextMaturity = end
+        }
+    }
+    summary.TotalValue = chainValue(summary.ChainTShares, data, 0)
+    return summary
+}
+
+// Lifetime yield line of the Profile tab
+func lifetimeYieldText(summary portfolioSummary) string {
+    text := fmt.Sprintf("Lifetime HEX earned + projected: %s HEX (earned %s, projected %s)",
+        maskPrivate(formatWithCommas(int(summary.RealizedHEX+summary.ProjectedHEX))),
+        maskPrivate(formatWithCommas(int(summary.RealizedHEX))),
+        maskPrivate(formatWithCommas(int(summary.ProjectedHEX))))
+    if summary.UnrecordedCount > 0 {
+        text += fmt.Sprintf(" - %d completed miners ended before earnings were recorded are not included", summary.UnrecordedCount)
+    }
+    return text
+}
+
+// Estimated gain line of the Profile tab, it waits for a fetched payout
+func gainText(summary portfolioSummary, hasPayout bool) string {
+    if !hasPayout {
+        return "Estimated Gain (miners with principal): " + calculatingText
+    }
+    return fmt.Sprintf("Estimated Gain (miners with principal): +%s HEX", maskPrivate(formatWithCommas(int(summary.Gain))))
+}
+
+// Per-chain T-Shares and value line of the Profile tab, empty unless miners are on more than one chain
+func chainTotalsText(summary portfolioSummary, data LiveData, pinned float64) string {
+    if len(summary.ChainTShares) < 2 {
+        return ""
+    }
+    var parts []string
+    for _, chain := range chains {
+        tShares, ok := summary.ChainTShares[chain]
+        if !ok {
+            continue
+        }
+        value := calculatingText
+        if chainTsharePrice(chain, data, pinned) > 0 {
+            value = maskPrivate(formatMoney(chainValue(map[string]float64{chain: tShares}, data, pinned), 2))
+        }
+        parts = append(parts, fmt.Sprintf("%s: %s T-Shares, %s", chainName(chain), formatTShares(tShares), value))
+    }
+    return strings.Join(parts, " · ")
+}
+
+// T-Share price holdings are valued at, a pinned price wins over the live one
+func valuationPrice(live, pinned float64) (float64, bool) {
+    if pinned > 0 {
+        return pinned, true
+    }
+    return live, false
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+    "fmt"
+    "math/rand/v2"
+    "testing"
+    "time"
+)
+
+func TestPortfolioSummaryTShares(t *testing.T) {
+    useConfig(t, nil)
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {StartDate: "01-01-2025", EndDate: "01-01-2026", TShares: 10},
+        {StartDate: "01-02-2025", EndDate: "01-02-2027", TShares: 2.5},
+        {StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 4, Status: "completed"},
+    }
+    summary := computePortfolioSummary(miners, LiveData{}, now, false)
+    if summary.ActiveTShares != 12.5 {
+        t.Errorf("ActiveTShares = %v, want 12.5", summary.ActiveTShares)
+    }
+    if summary.LifetimeTShares != 16.5 {
+        t.Errorf("LifetimeTShares = %v, want 16.5", summary.LifetimeTShares)
+    }
+    if summary.TotalTShares != 12.5 {
+        t.Errorf("TotalTShares = %v, want 12.5 without completed miners", summary.TotalTShares)
+    }
+    if summary.ActiveCount != 2 || summary.CompletedCount != 1 {
+        t.Errorf("counts = %d active, %d completed, want 2 and 1", summary.ActiveCount, summary.CompletedCount)
+    }
+
+    summary = computePortfolioSummary(miners, LiveData{}, now, true)
+    if summary.TotalTShares != 16.5 {
+        t.Errorf("TotalTShares = %v, want 16.5 with completed miners", summary.TotalTShares)
+    }
+    if summary.ActiveTShares != 12.5 {
+        t.Errorf("ActiveTShares = %v, want 12.5 with completed miners counted", summary.ActiveTShares)
+    }
+}
+
+// The one setting decides for every total whether completed miners count
+func TestPortfolioSummaryIncludeCompleted(t *testing.T) {
+    useConfig(t, nil)
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {StartDate: "01-05-2025", EndDate: "11-05-2026", TShares: 2, PrincipalHEX: 1000},
+        {StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 4, Chain: chainEthereum, Status: "completed", RealizedHEX: 300, PrincipalHEX: 1000},
+    }
+    data := LiveData{TsharePricePulsechain: 100, PayoutPerTsharePulsechain: 1, TsharePriceEthereum: 10}
+    tests := []struct {
+        include bool
+        tShares float64
+        value   float64
+        gain    float64
+    }{
+        {false, 2, 200, 750},
+        {true, 6, 240, 1050},
+    }
+    for _, tt := range tests {
+        summary := computePortfolioSummary(miners, data, now, tt.include)
+        if summary.TotalTShares != tt.tShares || summary.TotalValue != tt.value || summary.Gain != tt.gain {
+            t.Errorf("include completed %v: T-Shares %v, value %v, gain %v, want %v, %v, %v", tt.include, summary.TotalTShares, summary.TotalValue, summary.Gain, tt.tShares, tt.value, tt.gain)
+        }
+        // The rest doesn't depend on it
+        if summary.ActiveTShares != 2 || summary.ProjectedHEX != 750 || summary.RealizedHEX != 300 || summary.ChainActive[chainEthereum] != 0 {
+            t.Errorf("include completed %v changed other figures: %+v", tt.include, summary)
+        }
+    }
+
+    // Callers like the report and the title take it from the config
+    useConfig(t, func(c *Config) {
+        c.IncludeCompletedInTotals = true
+    })
+    if summary := currentPortfolioSummary(miners, data, now); summary.TotalTShares != 6 {
+        t.Errorf("currentPortfolioSummary with the setting on = %v T-Shares, want 6", summary.TotalTShares)
+    }
+}
+
+func TestValuationPrice(t *testing.T) {
+    tests := []struct {
+        live, pinned float64
+        want         float64
+        wantPinned   bool
+    }{
+        {250, 0, 250, false},
+        {250, 100, 100, true},
+        {0, 100, 100, true}, // Pinned values don't wait for live data
+        {0, 0, 0, false},
+    }
+    for _, tt := range tests {
+        if got, pinned := valuationPrice(tt.live, tt.pinned); got != tt.want || pinned != tt.wantPinned {
+            t.Errorf("valuationPrice(%v, %v) = %v, %v, want %v, %v", tt.live, tt.pinned, got, pinned, tt.want, tt.wantPinned)
+        }
+    }
+    // The pin is a PulseChain price, Ethereum T-Shares keep their live one
+    tShares := map[string]float64{chainPulsechain: 2, chainEthereum: 3}
+    data := LiveData{TsharePricePulsechain: 250, TsharePriceEthereum: 10}
+    if got := chainValue(tShares, data, 0); got != 530 {
+        t.Errorf("live value = %v, want 530", got)
+    }
+    if got := chainValue(tShares, data, 100); got != 230 {
+        t.Errorf("pinned value = %v, want 230", got)
+    }
+}
+
+// Values wait for the price of each chain holding T-Shares, not only PulseChain's
+func TestChainPricesKnown(t *testing.T) {
+    useConfig(t, nil)
+    ethereumOnly := map[string]float64{chainEthereum: 3}
+    both := map[string]float64{chainPulsechain: 2, chainEthereum: 3}
+    tests := []struct {
+        tShares map[string]float64
+        data    LiveData
+        pinned  float64
+        want    bool
+    }{
+        {ethereumOnly, LiveData{TsharePriceEthereum: 10}, 0, true},
+        {ethereumOnly, LiveData{TsharePricePulsechain: 250}, 0, false},
+        {both, LiveData{TsharePricePulsechain: 250}, 0, false},
+        {both, LiveData{TsharePricePulsechain: 250, TsharePriceEthereum: 10}, 0, true},
+        {both, LiveData{TsharePriceEthereum: 10}, 100, true}, // The pin stands in for PulseChain
+        {ethereumOnly, LiveData{}, 100, false},
+    }
+    for _, tt := range tests {
+        if got := chainPricesKnown(tt.tShares, tt.data, tt.pinned); got != tt.want {
+            t.Errorf("chainPricesKnown(%v, %+v, %v) = %v, want %v", tt.tShares, tt.data, tt.pinned, got, tt.want)
+        }
+    }
+
+    // An Ethereum-only portfolio gets its value in the title once its own price is known
+    miners := []Miner{{ID: "eth", StartDate: "01-01-2025", EndDate: "01-01-2040", TShares: 3, Chain: chainEthereum}}
+    now := testDay(t, "01-06-2025")
+    if got := windowTitle("value", miners, LiveData{TsharePriceEthereum: 10}, now); got != "HEX Stats - $30.00" {
+        t.Errorf("Ethereum-only title = %q, want its value", got)
+    }
+    if got := windowTitle("value", miners, LiveData{TsharePricePulsechain: 250}, now); got != appTitle {
+        t.Errorf("title without the Ethereum price = %q, want the plain title", got)
+    }
+}
+
+// T-Shares aren't masked in privacy mode anywhere, only money values
+func TestChainTotalsText(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.PrivacyMode = true
+    })
+    summary := portfolioSummary{ChainTShares: map[string]float64{chainPulsechain: 2, chainEthereum: 3}}
+    got := chainTotalsText(summary, LiveData{TsharePricePulsechain: 250}, 0)
+    want := fmt.Sprintf("PulseChain: %s T-Shares, %s · Ethereum: %s T-Shares, %s", formatTShares(2), privacyMask, formatTShares(3), calculatingText)
+    if got != want {
+        t.Errorf("chainTotalsText = %q, want %q", got, want)
+    }
+}
+
+func TestLifetimeYield(t *testing.T) {
+    useConfig(t, nil)
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {StartDate: "01-05-2025", EndDate: "11-05-2026", TShares: 2},                                              // 375 days active
+        {StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 4, Status: "completed", RealizedHEX: 500},       // Recorded
+        {StartDate: "01-01-2023", EndDate: "01-01-2024", TShares: 8, Status: "completed"},                         // Ended before yields were recorded
+        {StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 1, Chain: chainEthereum, Status: "completed", RealizedHEX: 40},
+    }
+    summary := computePortfolioSummary(miners, LiveData{PayoutPerTsharePulsechain: 0.5, PayoutPerTshareEthereum: 9}, now, false)
+    if summary.RealizedHEX != 540 || summary.ProjectedHEX != 375 || summary.UnrecordedCount != 1 {
+        t.Errorf("earned %v, projected %v, %d unrecorded, want 540, 375 and 1", summary.RealizedHEX, summary.ProjectedHEX, summary.UnrecordedCount)
+    }
+    want := "Lifetime HEX earned + projected: 915 HEX (earned 540, projected 375) - 1 completed miners ended before earnings were recorded are not included"
+    if got := lifetimeYieldText(summary); got != want {
+        t.Errorf("lifetimeYieldText = %q, want %q", got, want)
+    }
+}
+
+// n synthetic miners around now, in a fixed mix of states, chains and principals. Every
+// seventh is completed, every fifth remaining one matured, every ninth pending, every third
+// on Ethereum and every other one has a principal.
+func syntheticMiners(n int, now time.Time) []Miner {
+    rng := rand.New(rand.NewPCG(1, uint64(n)))
+    miners := make([]Miner, 0, n)
+    for i := 0; i < n; i++ {
+        days := 30 + rng.IntN(5555-30)
+        start := now.AddDate(0, 0, -rng.IntN(days))
+        switch {
+        case i%7 == 0, i%5 == 0:
+            start = now.AddDate(0, 0, -days-1-rng.IntN(365)) // Ended before today
+        case i%9 == 0:
+            start = now.AddDate(0, 0, 1+rng.IntN(30))
+        }
+        miner := Miner{
+            ID:        fmt.Sprintf("m%d", i),
+            StartDate: start.Format(dateLayout),
+            EndDate:   start.AddDate(0, 0, days).Format(dateLayout),
+            TShares:   float64(1+rng.IntN(1000)) / 10,
+        }
+        if i%7 == 0 {
+            miner.Status = "completed"
+            miner.RealizedHEX = miner.TShares * float64(days)
+        }
+        if i%3 == 0 {
+            miner.Chain = chainEthereum
+        }
+        if i%2 == 0 {
+            miner.PrincipalHEX = miner.TShares * 1000
+        }
+        miners = append(miners, miner)
+    }
+    return miners
+}
+
+func TestSyntheticPortfolioSummary(t *testing.T) {
+    now := testDay(t, "01-06-2025")
+    miners := syntheticMiners(630, now)
+    var completed, matured, pending int
+    var active, ethereum float64
+    for i, miner := range miners {
+        switch {
+        case i%7 == 0:
+            completed++
+            continue
+        case i%5 == 0:
+            matured++
+        case i%9 == 0:
+            pending++
+        }
+        active += miner.TShares
+        if i%3 == 0 {
+            ethereum += miner.TShares
+        }
+    }
+    if pending == 0 {
+        t.Fatal("fixture has no pending miners")
+    }
+    data := LiveData{TsharePricePulsechain: 200, TsharePriceEthereum: 100, PayoutPerTsharePulsechain: 1, PayoutPerTshareEthereum: 2}
+    summary := computePortfolioSummary(miners, data, now, false)
+    if summary.CompletedCount != completed || summary.ActiveCount != len(miners)-completed || summary.MaturedCount != matured {
+        t.Errorf("counts = %d completed, %d active, %d matured, want %d, %d, %d",
+            summary.CompletedCount, summary.ActiveCount, summary.MaturedCount, completed, len(miners)-completed, matured)
+    }
+    if !near(float32(summary.ActiveTShares), float32(active)) || !near(float32(summary.ChainTShares[chainEthereum]), float32(ethereum)) {
+        t.Errorf("T-Shares = %v active, %v on Ethereum, want %v and %v", summary.ActiveTShares, summary.ChainTShares[chainEthereum], active, ethereum)
+    }
+    if want := (active-ethereum)*200 + ethereum*100; !near(float32(summary.TotalValue), float32(want)) {
+        t.Errorf("TotalValue = %v, want %v", summary.TotalValue, want)
+    }
+    if !summary.HasGain || summary.Gain <= 0 || summary.RealizedHEX <= 0 || summary.ProjectedHEX <= 0 {
+        t.Errorf("gain %v and yields %v/%v, want all positive", summary.Gain, summary.RealizedHEX, summary.ProjectedHEX)
+    }
+    if summary.NextMaturity.Before(calendarDay(now)) {
+        t.Errorf("next maturity %s is before today", summary.NextMaturity.Format(dateLayout))
+    }
+}
+
+func TestPortfolioSummaryNextMaturity(t *testing.T) {
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {StartDate: "01-01-2024", EndDate: "01-05-2025", TShares: 1},                      // Matured, not next
+        {StartDate: "01-01-2025", EndDate: "01-06-2025", TShares: 1},                      // Ends today
+        {StartDate: "01-01-2025", EndDate: "01-08-2025", TShares: 1},
+        {StartDate: "01-01-2024", EndDate: "02-06-2025", TShares: 1, Status: "completed"}, // Ended early, never next
+    }
+    summary := computePortfolioSummary(miners, LiveData{}, now, false)
+    if got := summary.NextMaturity.Format(dateLayout); got != "01-06-2025" {
+        t.Errorf("NextMaturity = %s, want today's stake", got)
+    }
+    if summary.MaturedCount != 2 {
+        t.Errorf("MaturedCount = %d, want 2 counting the one ending today", summary.MaturedCount)
+    }
+    if summary := computePortfolioSummary(miners[:1], LiveData{}, now, false); !summary.NextMaturity.IsZero() {
+        t.Errorf("NextMaturity = %s with only matured miners, want none", summary.NextMaturity.Format(dateLayout))
+    }
+}
+
+func benchmarkPortfolio(b *testing.B, n int) {
+    now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+    miners := syntheticMiners(n, now)
+    data := LiveData{TsharePricePulsechain: 200, TsharePriceEthereum: 100, PayoutPerTsharePulsechain: 1, PayoutPerTshareEthereum: 2}
+    b.Run("summary", func(b *testing.B) {
+        for b.Loop() {
+            computePortfolioSummary(miners, data, now, true)
+        }
+    })
+    b.Run("views", func(b *testing.B) {
+        for b.Loop() {
+            buildMinerViewModels(miners, now, data)
+        }
+    })
+}
+
+func BenchmarkPortfolio100(b *testing.B)   { benchmarkPortfolio(b, 100) }
+func BenchmarkPortfolio1000(b *testing.B)  { benchmarkPortfolio(b, 1000) }
+func BenchmarkPortfolio10000(b *testing.B) { benchmarkPortfolio(b, 10000) }
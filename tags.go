@@ -0,0 +1,80 @@
+package main
+
+import (
+    "sort"
+    "strings"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/canvas"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/theme"
+)
+
+// Tags for grouping miners, entered comma separated
+
+// Splits a comma separated tag list, dropping blanks and duplicates
+func parseTags(s string) []string {
+    var tags []string
+    for _, tag := range strings.Split(s, ",") {
+        tag = strings.TrimSpace(tag)
+        if tag != "" && !contains(tags, tag) {
+            tags = append(tags, tag)
+        }
+    }
+    return tags
+}
+
+// Sorted set of all tags used by miners
+func allTags(miners []Miner) []string {
+    var tags []string
+    for _, miner := range miners {
+        for _, tag := range miner.Tags {
+            if !contains(tags, tag) {
+                tags = append(tags, tag)
+            }
+        }
+    }
+    sort.Strings(tags)
+    return tags
+}
+
+// Miners carrying every selected tag, all miners when nothing is selected
+func filterByTags(views []minerView, selected []string) []minerView {
+    if len(selected) == 0 {
+        return views
+    }
+    var filtered []minerView
+    for _, view := range views {
+        matches := true
+        for _, tag := range selected {
+            if !contains(view.Miner.Tags, tag) {
+                matches = false
+                break
+            }
+        }
+        if matches {
+            filtered = append(filtered, view)
+        }
+    }
+    return filtered
+}
+
+func tagChip(tag string) fyne.CanvasObject {
+    background := canvas.NewRectangle(theme.Color(theme.ColorNameInputBackground))
+    background.CornerRadius = 8
+    text := canvas.NewText(tag, theme.Color(theme.ColorNameForeground))
+    text.TextSize = theme.CaptionTextSize()
+    return container.NewCenter(container.NewStack(background, container.NewPadded(text)))
+}
+
+// Row content followed by the miner's tag chips
+func withTagChips(content fyne.CanvasObject, tags []string) fyne.CanvasObject {
+    if len(tags) == 0 {
+        return content
+    }
+    row := container.NewHBox(content)
+    for _, tag := range tags {
+        row.Add(tagChip(tag))
+    }
+    return row
+}
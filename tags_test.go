@@ -0,0 +1,85 @@
+package main
+
+import (
+    "os"
+    "path/filepath"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestParseTags(t *testing.T) {
+    tests := []struct {
+        in   string
+        want []string
+    }{
+        {"", nil},
+        {" , ,", nil},
+        {"long-term", []string{"long-term"}},
+        {" long-term , 2025,long-term,", []string{"long-term", "2025"}},
+    }
+    for _, tt := range tests {
+        if got := parseTags(tt.in); !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("parseTags(%q) = %q, want %q", tt.in, got, tt.want)
+        }
+    }
+}
+
+func TestFilterByTags(t *testing.T) {
+    views := []minerView{
+        {Miner: Miner{ID: "a", Tags: []string{"long-term", "2025"}}},
+        {Miner: Miner{ID: "b", Tags: []string{"2025"}}},
+        {Miner: Miner{ID: "c"}},
+    }
+    ids := func(views []minerView) []string {
+        var result []string
+        for _, view := range views {
+            result = append(result, view.Miner.ID)
+        }
+        return result
+    }
+    tests := []struct {
+        selected []string
+        want     []string
+    }{
+        {nil, []string{"a", "b", "c"}},
+        {[]string{"2025"}, []string{"a", "b"}},
+        {[]string{"2025", "long-term"}, []string{"a"}}, // Every selected tag has to match
+        {[]string{"unused"}, nil},
+    }
+    for _, tt := range tests {
+        if got := ids(filterByTags(views, tt.selected)); !reflect.DeepEqual(got, tt.want) {
+            t.Errorf("filterByTags(%q) = %q, want %q", tt.selected, got, tt.want)
+        }
+    }
+    if got := allTags([]Miner{views[1].Miner, views[0].Miner, views[2].Miner}); !reflect.DeepEqual(got, []string{"2025", "long-term"}) {
+        t.Errorf("allTags = %q, want both tags sorted once", got)
+    }
+}
+
+// Files written before tags existed load with no tags and are saved without the field
+func TestLoadMinersWithoutTags(t *testing.T) {
+    useConfig(t, nil)
+    useTempStorage(t)
+    old := `[{"id": "a", "startDate": "01-01-2025", "endDate": "01-01-2026", "tShares": 2.5}]`
+    if err := os.WriteFile(filepath.Join("settings", "miners.json"), []byte(old), 0644); err != nil {
+        t.Fatal(err)
+    }
+    miners, err := loadMiners()
+    if err != nil {
+        t.Fatal(err)
+    }
+    if len(miners) != 1 || miners[0].Tags != nil || miners[0].TShares != 2.5 {
+        t.Fatalf("loaded %+v, want the miner without tags", miners)
+    }
+    if err := saveMiners(miners); err != nil {
+        t.Fatal(err)
+    }
+    data, err := os.ReadFile(filepath.Join("settings", "miners.json"))
+    if err != nil {
+        t.Fatal(err)
+    }
+    if strings.Contains(string(data), `"tags"`) {
+        t.Errorf("miner without tags saved with the field:\n%s", data)
+    }
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+    "fmt"
+    "time"
+
+    "fyne.io/fyne/v2"
+)
+
+// Optional figure after the app name in the window title, for a glance at the taskbar
+
+const appTitle = "HEX Stats"
+
+var titleMetrics = []string{"none", "price", "value", "next-maturity"}
+
+func windowTitle(metric string, miners []Miner, data LiveData, now time.Time) string {
+    switch metric {
+    case "price":
+        if price := data.Chain(configManager.GetConfig().Chain).TsharePrice; price > 0 {
+            return fmt.Sprintf("%s - T-Share %s", appTitle, formatMoney(price, tsharePriceDecimals))
+        }
+    case "value":
+        if summary := currentPortfolioSummary(miners, data, now); chainPricesKnown(summary.ChainTShares, data, 0) {
+            return fmt.Sprintf("%s - %s", appTitle, maskPrivate(formatMoney(summary.TotalValue, 2)))
+        }
+    case "next-maturity":
+        summary := currentPortfolioSummary(miners, data, now)
+        if text := nextMaturityText(summary, now); text != "-" {
+            return fmt.Sprintf("%s - Next maturity %s", appTitle, text)
+        }
+    }
+    return appTitle
+}
+
+// Sets the title from miners as shown in the tabs, call on the UI thread
+func updateWindowTitle(w fyne.Window, miners []Miner) {
+    metric := configManager.GetConfig().TitleMetric
+    if metric == "none" {
+        w.SetTitle(appTitle)
+        return
+    }
+    liveDataMutex.Lock()
+    data := latestLiveData
+    liveDataMutex.Unlock()
+    w.SetTitle(windowTitle(metric, miners, data, time.Now()))
+}
+
+// Keeps the title current as live data arrives, shownMiners is called on the UI thread
+func startTitleUpdater(w fyne.Window, shownMiners func() []Miner) {
+    liveCh := liveDataUpdated.Subscribe()
+    go func() {
+        for range liveCh {
+            fyne.Do(func() {
+                updateWindowTitle(w, shownMiners())
+            })
+        }
+    }()
+}
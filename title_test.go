@@ -0,0 +1,42 @@
+package main
+
+import "testing"
+
+func TestWindowTitle(t *testing.T) {
+    useConfig(t, nil)
+    now := testDay(t, "01-06-2025")
+    miners := []Miner{
+        {ID: "a", StartDate: "01-01-2025", EndDate: "11-06-2025", TShares: 2},
+        {ID: "b", StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 5, Status: "completed"},
+    }
+    data := LiveData{TsharePricePulsechain: 250}
+    tests := []struct {
+        metric string
+        data   LiveData
+        want   string
+    }{
+        {"none", data, "HEX Stats"},
+        {"price", data, "HEX Stats - T-Share $250.00"},
+        {"value", data, "HEX Stats - $500.00"},
+        {"next-maturity", data, "HEX Stats - Next maturity 11-06-2025 (in " + formatDuration(10) + ")"},
+        // Without live data only the maturity can be shown
+        {"price", LiveData{}, "HEX Stats"},
+        {"value", LiveData{}, "HEX Stats"},
+        {"next-maturity", LiveData{}, "HEX Stats - Next maturity 11-06-2025 (in " + formatDuration(10) + ")"},
+    }
+    for _, tt := range tests {
+        if got := windowTitle(tt.metric, miners, tt.data, now); got != tt.want {
+            t.Errorf("windowTitle(%s) = %q, want %q", tt.metric, got, tt.want)
+        }
+    }
+    if got := windowTitle("next-maturity", miners[1:], data, now); got != appTitle {
+        t.Errorf("next maturity without active miners = %q, want the plain title", got)
+    }
+
+    useConfig(t, func(c *Config) {
+        c.PrivacyMode = true
+    })
+    if got := windowTitle("value", miners, data, now); got != "HEX Stats - "+privacyMask {
+        t.Errorf("value in privacy mode = %q, want it masked", got)
+    }
+}
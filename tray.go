@@ -0,0 +1,118 @@
+package main
+
+import (
+    "fmt"
+    "sync"
+    "sync/atomic"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/driver/desktop"
+)
+
+// System tray menu with the HEX and T-Share price of the chain shown on the Live Data tab,
+// the matured stakes when those are on, and items to open the window, refresh or quit.
+// Set up at startup, the tray can't be removed while running.
+
+// Whether the tray menu was set up, only on desktops that have a tray
+var trayInstalled bool
+
+// Set in tray mode once closing the window only hides it, read by the fetch loop
+var runningInTray atomic.Bool
+
+var trayState struct {
+    mu      sync.Mutex
+    matured int
+}
+
+// Asks the live data loop for a fetch now instead of at the next tick
+var fetchNowCh = make(chan struct{}, 1)
+
+func requestLiveFetch() {
+    select {
+    case fetchNowCh <- struct{}{}:
+    default: // One is already pending
+    }
+}
+
+func installTray(w fyne.Window) bool {
+    if _, ok := fyne.CurrentApp().(desktop.App); !ok {
+        return false
+    }
+    trayInstalled = true
+    updateTrayMenu(w)
+    // Keeps the menu prices current as live data arrives
+    liveCh := liveDataUpdated.Subscribe()
+    go func() {
+        for range liveCh {
+            fyne.Do(func() {
+                updateTrayMenu(w)
+            })
+        }
+    }()
+    return true
+}
+
+func setTrayMatured(w fyne.Window, matured int) {
+    trayState.mu.Lock()
+    trayState.matured = matured
+    trayState.mu.Unlock()
+    if trayInstalled {
+        updateTrayMenu(w)
+    }
+}
+
+func maturedTrayText(matured int) string {
+    switch {
+    case matured == 1:
+        return "1 matured stake to end"
+    case matured > 1:
+        return fmt.Sprintf("%d matured stakes to end", matured)
+    }
+    return "No matured stakes"
+}
+
+func trayPriceTexts(data LiveData) (string, string) {
+    chain := configManager.GetConfig().Chain
+    chainData := data.Chain(chain)
+    if chainData.Price <= 0 {
+        return fmt.Sprintf("%s HEX: %s", chainName(chain), calculatingText), "T-Share: " + calculatingText
+    }
+    return fmt.Sprintf("%s HEX: %s", chainName(chain), formatMoney(chainData.Price, priceDecimals)),
+        "T-Share: " + formatMoney(chainData.TsharePrice, tsharePriceDecimals)
+}
+
+func updateTrayMenu(w fyne.Window) {
+    desk, ok := fyne.CurrentApp().(desktop.App)
+    if !ok {
+        return
+    }
+    liveDataMutex.Lock()
+    data := latestLiveData
+    liveDataMutex.Unlock()
+    trayState.mu.Lock()
+    matured := trayState.matured
+    trayState.mu.Unlock()
+
+    var items []*fyne.MenuItem
+    priceText, tsharePriceText := trayPriceTexts(data)
+    for _, text := range []string{priceText, tsharePriceText} {
+        item := fyne.NewMenuItem(text, nil)
+        item.Disabled = true
+        items = append(items, item)
+    }
+    if configManager.GetConfig().TrayAlerts {
+        item := fyne.NewMenuItem(maturedTrayText(matured), nil)
+        item.Disabled = true
+        items = append(items, item)
+    }
+    items = append(items,
+        fyne.NewMenuItemSeparator(),
+        fyne.NewMenuItem("Show "+appTitle, func() {
+            w.Show()
+            w.RequestFocus()
+        }),
+        fyne.NewMenuItem("Refresh Now", requestLiveFetch),
+        fyne.NewMenuItem("Quit", fyne.CurrentApp().Quit),
+    )
+    desk.SetSystemTrayMenu(fyne.NewMenu(appTitle, items...))
+}
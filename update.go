@@ -0,0 +1,165 @@
+package main
+
+import (
+    "encoding/json"
+    "fmt"
+    "log"
+    "net/http"
+    "net/url"
+    "strconv"
+    "strings"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Version of this build, release builds set it with -ldflags "-X main.appVersion=1.2.3".
+// Empty in other builds, which skip the update check as there is nothing to compare
+var appVersion = ""
+
+const defaultUpdateCheckURL = "https://api.github.com/repos/hiltar/hexfetch-ui/releases/latest"
+
+type releaseInfo struct {
+    TagName string `json:"tag_name"`
+    HTMLURL string `json:"html_url"`
+}
+
+func fetchLatestRelease(url string) (releaseInfo, error) {
+    resp, err := httpGet(url)
+    if err != nil {
+        return releaseInfo{}, err
+    }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return releaseInfo{}, fmt.Errorf("unexpected status %s", resp.Status)
+    }
+    var release releaseInfo
+    err = json.NewDecoder(resp.Body).Decode(&release)
+    if err != nil {
+        return releaseInfo{}, err
+    }
+    if release.TagName == "" {
+        return releaseInfo{}, fmt.Errorf("release has no tag")
+    }
+    return release, nil
+}
+
+// Compares semantic versions (optional "v" prefix), returns -1, 0 or 1.
+// A pre-release sorts before its release, build metadata is ignored.
+func compareVersions(a, b string) int {
+    coreA, preA := splitVersion(a)
+    coreB, preB := splitVersion(b)
+    for i := 0; i < len(coreA) || i < len(coreB); i++ {
+        var x, y int
+        if i < len(coreA) {
+            x = coreA[i]
+        }
+        if i < len(coreB) {
+            y = coreB[i]
+        }
+        if x != y {
+            return compareInts(x, y)
+        }
+    }
+    switch {
+    case preA == "" && preB == "":
+        return 0
+    case preA == "":
+        return 1
+    case preB == "":
+        return -1
+    }
+    return comparePreRelease(strings.Split(preA, "."), strings.Split(preB, "."))
+}
+
+func splitVersion(v string) ([]int, string) {
+    v = strings.TrimPrefix(strings.TrimSpace(v), "v")
+    if i := strings.Index(v, "+"); i >= 0 {
+        v = v[:i]
+    }
+    pre := ""
+    if i := strings.Index(v, "-"); i >= 0 {
+        v, pre = v[:i], v[i+1:]
+    }
+    var core []int
+    for _, part := range strings.Split(v, ".") {
+        n, _ := strconv.Atoi(part)
+        core = append(core, n)
+    }
+    return core, pre
+}
+
+// Numeric identifiers compare numerically and sort before alphanumeric ones
+func comparePreRelease(a, b []string) int {
+    for i := 0; i < len(a) && i < len(b); i++ {
+        x, errX := strconv.Atoi(a[i])
+        y, errY := strconv.Atoi(b[i])
+        switch {
+        case errX == nil && errY == nil:
+            if x != y {
+                return compareInts(x, y)
+            }
+        case errX == nil:
+            return -1
+        case errY == nil:
+            return 1
+        default:
+            if c := strings.Compare(a[i], b[i]); c != 0 {
+                return c
+            }
+        }
+    }
+    return compareInts(len(a), len(b))
+}
+
+func compareInts(x, y int) int {
+    switch {
+    case x < y:
+        return -1
+    case x > y:
+        return 1
+    }
+    return 0
+}
+
+// Whether the startup check runs: turned on, not offline and the build has a version
+func updateCheckEnabled(config Config, version string) bool {
+    return config.UpdateCheck && !config.OfflineMode && version != ""
+}
+
+// Fills banner with a link when a newer release exists, never downloads anything
+func checkForUpdate(banner *fyne.Container) {
+    config := configManager.GetConfig()
+    if !updateCheckEnabled(config, appVersion) {
+        if config.UpdateCheck && appVersion == "" {
+            log.Println("Skipping the update check, this build has no version")
+        }
+        return
+    }
+    go func() {
+        release, err := fetchLatestRelease(config.UpdateCheckURL)
+        if err != nil {
+            log.Println("Error checking for updates:", err)
+            return
+        }
+        if compareVersions(release.TagName, appVersion) <= 0 {
+            return
+        }
+        link, err := url.Parse(release.HTMLURL)
+        fyne.Do(func() {
+            text := fmt.Sprintf("HEX Stats %s is available (you have %s)", release.TagName, appVersion)
+            var items []fyne.CanvasObject
+            if err == nil && release.HTMLURL != "" {
+                items = append(items, widget.NewHyperlink(text, link))
+            } else {
+                items = append(items, widget.NewLabel(text))
+            }
+            items = append(items, widget.NewButton("Dismiss", func() {
+                banner.RemoveAll()
+            }))
+            banner.Objects = []fyne.CanvasObject{container.NewHBox(items...)}
+            banner.Refresh()
+        })
+    }()
+}
@@ -0,0 +1,64 @@
+package main
+
+import "testing"
+
+func TestCompareVersions(t *testing.T) {
+    tests := []struct {
+        a, b string
+        want int
+    }{
+        {"1.2.3", "1.2.3", 0},
+        {"v1.2.3", "1.2.3", 0},
+        {"1.2.4", "1.2.3", 1},
+        {"1.10.0", "1.9.0", 1}, // Numerically, not as text
+        {"2.0.0", "1.99.99", 1},
+        {"1.2", "1.2.0", 0},
+        {"1.2.3-beta", "1.2.3", -1}, // A pre-release comes before its release
+        {"1.2.3", "1.2.3-rc.1", 1},
+        {"1.2.3-alpha", "1.2.3-beta", -1},
+        {"1.2.3-rc.2", "1.2.3-rc.10", -1},
+        {"1.2.3-rc.1", "1.2.3-rc", 1},
+        {"1.2.3-1", "1.2.3-alpha", -1}, // Numeric identifiers sort first
+        {"1.2.4-beta", "1.2.3", 1},
+        {"1.2.3+build.5", "1.2.3", 0},
+    }
+    for _, tt := range tests {
+        if got := compareVersions(tt.a, tt.b); got != tt.want {
+            t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+        }
+        if got := compareVersions(tt.b, tt.a); got != -tt.want {
+            t.Errorf("compareVersions(%q, %q) = %d, want %d", tt.b, tt.a, got, -tt.want)
+        }
+    }
+}
+
+func TestUpdateCheckEnabled(t *testing.T) {
+    config := defaultConfig()
+    config.UpdateCheck = true
+    if !updateCheckEnabled(config, "1.2.3") {
+        t.Error("update check off while turned on")
+    }
+    if updateCheckEnabled(config, "") {
+        t.Error("update check runs for a build without a version")
+    }
+    config.OfflineMode = true
+    if updateCheckEnabled(config, "1.2.3") {
+        t.Error("update check runs in offline mode")
+    }
+    config.OfflineMode, config.UpdateCheck = false, false
+    if updateCheckEnabled(config, "1.2.3") {
+        t.Error("update check runs while turned off")
+    }
+}
+
+func TestHTTPOffline(t *testing.T) {
+    useConfig(t, func(c *Config) {
+        c.OfflineMode = true
+    })
+    if _, err := httpGet("https://example.invalid"); err != errOffline {
+        t.Errorf("httpGet in offline mode: err = %v, want errOffline", err)
+    }
+    if _, err := httpPost("https://example.invalid", "application/json", nil); err != errOffline {
+        t.Errorf("httpPost in offline mode: err = %v, want errOffline", err)
+    }
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "time"
+)
+
+// Portfolio value alerts: notifies once when the total value crosses a threshold and
+// only re-arms after the value has moved back past it by valueAlertHysteresis
+
+const valueAlertHysteresis = 0.02
+
+const (
+    valueAlertNone  = ""
+    valueAlertAbove = "above"
+    valueAlertBelow = "below"
+)
+
+// New alert state for value given the last one, fired is set when an alert should be shown.
+// A threshold of 0 is off.
+func nextValueAlert(state string, value, above, below float64) (string, bool) {
+    switch state {
+    case valueAlertAbove:
+        if above <= 0 || value < above*(1-valueAlertHysteresis) {
+            state = valueAlertNone
+        }
+    case valueAlertBelow:
+        if below <= 0 || value > below*(1+valueAlertHysteresis) {
+            state = valueAlertNone
+        }
+    }
+    if state != valueAlertAbove && above > 0 && value >= above {
+        return valueAlertAbove, true
+    }
+    if state != valueAlertBelow && below > 0 && value <= below {
+        return valueAlertBelow, true
+    }
+    return state, false
+}
+
+func checkValueAlert(data LiveData) {
+    config := configManager.GetConfig()
+    if config.ValueAlertAbove <= 0 && config.ValueAlertBelow <= 0 {
+        return
+    }
+    miners, err := loadMiners()
+    if err != nil {
+        log.Println("Error loading miners:", err)
+        return
+    }
+    summary := currentPortfolioSummary(miners, data, time.Now())
+    if !chainPricesKnown(summary.ChainTShares, data, 0) {
+        return
+    }
+    // Thresholds are in the display currency, so wait for its rate rather than compare USD
+    code, rate, _ := fxCache.lookup(config.Currency)
+    if code != config.Currency {
+        return
+    }
+    value := summary.TotalValue * rate
+    state, fired := nextValueAlert(config.ValueAlertState, value, config.ValueAlertAbove, config.ValueAlertBelow)
+    if state == config.ValueAlertState {
+        return
+    }
+    // Persisted so a restart doesn't alert again for the same crossing
+    if err := updateConfig(func(c *Config) {
+        c.ValueAlertState = state
+    }); err != nil {
+        log.Println("Error saving config:", err)
+    }
+    if !fired {
+        return
+    }
+    threshold, direction := config.ValueAlertAbove, "risen above"
+    if state == valueAlertBelow {
+        threshold, direction = config.ValueAlertBelow, "fallen below"
+    }
+    notify("Portfolio Value Alert", fmt.Sprintf("Total T-Shares value has %s %s", direction, formatMoney(threshold/rate, 2)))
+}
+
+// Checks the alert after every live data update
+func startValueAlertWatcher() {
+    liveCh := liveDataUpdated.Subscribe()
+    go func() {
+        for range liveCh {
+            liveDataMutex.Lock()
+            data := latestLiveData
+            liveDataMutex.Unlock()
+            checkValueAlert(data)
+        }
+    }()
+}
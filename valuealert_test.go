@@ -0,0 +1,81 @@
+package main
+
+import (
+    "testing"
+    "time"
+)
+
+func TestNextValueAlert(t *testing.T) {
+    const above, below = 10000.0, 5000.0
+    steps := []struct {
+        value float64
+        state string
+        fired bool
+    }{
+        {8000, valueAlertNone, false},
+        {10000, valueAlertAbove, true},
+        {10100, valueAlertAbove, false},
+        {9900, valueAlertAbove, false}, // Within the hysteresis band, no re-arm
+        {10050, valueAlertAbove, false},
+        {9700, valueAlertNone, false}, // Moved back far enough
+        {10001, valueAlertAbove, true},
+        {4900, valueAlertBelow, true},
+        {5050, valueAlertBelow, false},
+        {4990, valueAlertBelow, false},
+        {5200, valueAlertNone, false},
+        {5000, valueAlertBelow, true},
+    }
+    state := valueAlertNone
+    for i, step := range steps {
+        var fired bool
+        state, fired = nextValueAlert(state, step.value, above, below)
+        if state != step.state || fired != step.fired {
+            t.Errorf("step %d at %v: state %q fired %v, want %q %v", i, step.value, state, fired, step.state, step.fired)
+        }
+    }
+    // Turning a threshold off clears its state
+    if state, fired := nextValueAlert(valueAlertAbove, 20000, 0, below); state != valueAlertNone || fired {
+        t.Errorf("with the threshold off: state %q fired %v, want cleared", state, fired)
+    }
+}
+
+// Thresholds are in the display currency, so no alert is decided on USD values while its
+// rate isn't fetched yet
+func TestCheckValueAlertWaitsForRate(t *testing.T) {
+    useTestApp(t)
+    useTempStorage(t)
+    useConfig(t, func(c *Config) {
+        c.Currency = "EUR"
+        c.ValueAlertAbove = 2000
+    })
+    previous := fxCache
+    fxCache = &fxRateCache{}
+    t.Cleanup(func() {
+        fxCache = previous
+    })
+    if err := saveMiners([]Miner{{ID: "a", StartDate: "01-01-2025", EndDate: "01-01-2040", TShares: 10}}); err != nil {
+        t.Fatal(err)
+    }
+    data := LiveData{TsharePricePulsechain: 250} // $2500, €2250 at 0.9
+
+    checkValueAlert(data)
+    if state := configManager.GetConfig().ValueAlertState; state != valueAlertNone {
+        t.Fatalf("alert state %q without a EUR rate, want none", state)
+    }
+    fxCache.update("GBP", 0.8, nil, time.Now())
+    checkValueAlert(data)
+    if state := configManager.GetConfig().ValueAlertState; state != valueAlertNone {
+        t.Fatalf("alert state %q with only a GBP rate, want none", state)
+    }
+
+    fxCache.update("EUR", 0.9, nil, time.Now())
+    checkValueAlert(data)
+    if state := configManager.GetConfig().ValueAlertState; state != valueAlertAbove {
+        t.Errorf("alert state %q once the EUR rate arrived, want above", state)
+    }
+    fxCache.update("EUR", 0.7, nil, time.Now()) // €1750, well below the threshold
+    checkValueAlert(data)
+    if state := configManager.GetConfig().ValueAlertState; state != valueAlertNone {
+        t.Errorf("alert state %q after falling below, want re-armed", state)
+    }
+}
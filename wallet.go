@@ -0,0 +1,203 @@
+package main
+
+import (
+    "bytes"
+    "encoding/hex"
+    "encoding/json"
+    "fmt"
+    "io"
+    "math/big"
+    "net/url"
+    "regexp"
+    "strings"
+    "time"
+)
+
+// Importing the active stakes of a PulseChain wallet straight from the HEX contract over
+// JSON-RPC. Only public contract state is read, nothing is signed or sent.
+
+const (
+    hexContractAddress = "0x2b591e99afE9f32eAA6214f7B7629768c40Eeb39"
+    defaultRPCURL      = "https://rpc.pulsechain.com"
+    stakeCountSelector = "33060d90" // stakeCount(address)
+    stakeListsSelector = "2607443b" // stakeLists(address,uint256)
+    maxWalletStakes    = 1000       // A wallet claiming more is taken as a bad answer
+)
+
+// Day 0 of the HEX contract, stake days count from it
+var hexLaunch = time.Date(2019, 12, 3, 0, 0, 0, 0, time.UTC)
+
+var walletAddressPattern = regexp.MustCompile(`^0x[0-9a-fA-F]{40}$`)
+
+func isWalletAddress(address string) bool {
+    return walletAddressPattern.MatchString(address)
+}
+
+// Checks an RPC URL entered in Settings. Unlike the API base URL it is used as given, path
+// and query included, as providers often take the API key there (https://host/?apikey=...).
+func validateRPCURL(raw string) (string, error) {
+    raw = strings.TrimSpace(raw)
+    parsed, err := url.Parse(raw)
+    if err != nil {
+        return "", fmt.Errorf("not a valid URL")
+    }
+    if parsed.Scheme != "https" {
+        return "", fmt.Errorf("URL must start with https://")
+    }
+    if parsed.Host == "" || parsed.Fragment != "" {
+        return "", fmt.Errorf("URL must be an https://host address")
+    }
+    return raw, nil
+}
+
+type rpcRequest struct {
+    JSONRPC string        `json:"jsonrpc"`
+    ID      int           `json:"id"`
+    Method  string        `json:"method"`
+    Params  []interface{} `json:"params"`
+}
+
+type rpcResponse struct {
+    Result string `json:"result"`
+    Error  *struct {
+        Code    int    `json:"code"`
+        Message string `json:"message"`
+    } `json:"error"`
+}
+
+// Read-only call of the HEX contract, returns the ABI encoded result
+func hexContractCall(rpcURL, data string) ([]byte, error) {
+    request := rpcRequest{
+        JSONRPC: "2.0",
+        ID:      1,
+        Method:  "eth_call",
+        Params:  []interface{}{map[string]string{"to": hexContractAddress, "data": "0x" + data}, "latest"},
+    }
+    body, err := json.Marshal(request)
+    if err != nil {
+        return nil, err
+    }
+    resp, err := httpPost(rpcURL, "application/json", bytes.NewReader(body))
+    if err != nil {
+        return nil, err
+    }
+    defer resp.Body.Close()
+    raw, err := io.ReadAll(resp.Body)
+    if err != nil {
+        return nil, err
+    }
+    var response rpcResponse
+    if err := json.Unmarshal(raw, &response); err != nil {
+        return nil, fmt.Errorf("unexpected RPC response (HTTP %d)", resp.StatusCode)
+    }
+    if response.Error != nil {
+        return nil, fmt.Errorf("RPC error %d: %s", response.Error.Code, response.Error.Message)
+    }
+    return hex.DecodeString(strings.TrimPrefix(response.Result, "0x"))
+}
+
+// ABI words of the call arguments
+func abiAddress(address string) string {
+    return strings.Repeat("0", 24) + strings.ToLower(strings.TrimPrefix(address, "0x"))
+}
+
+func abiUint(n uint64) string {
+    return fmt.Sprintf("%064x", n)
+}
+
+// Word i of an ABI encoded result
+func abiWord(result []byte, i int) (*big.Int, error) {
+    if len(result) < (i+1)*32 {
+        return nil, fmt.Errorf("short contract result")
+    }
+    return new(big.Int).SetBytes(result[i*32 : (i+1)*32]), nil
+}
+
+// One entry of stakeLists: stakeId, stakedHearts, stakeShares, lockedDay, stakedDays,
+// unlockedDay, isAutoStake
+type walletStake struct {
+    StakeID      uint64
+    StakedHearts *big.Int
+    StakeShares  *big.Int
+    LockedDay    int
+    StakedDays   int
+}
+
+func decodeWalletStake(result []byte) (walletStake, error) {
+    words := make([]*big.Int, 5)
+    for i := range words {
+        word, err := abiWord(result, i)
+        if err != nil {
+            return walletStake{}, err
+        }
+        words[i] = word
+    }
+    if !words[0].IsUint64() || !words[3].IsInt64() || !words[4].IsInt64() {
+        return walletStake{}, fmt.Errorf("stake out of range")
+    }
+    return walletStake{
+        StakeID:      words[0].Uint64(),
+        StakedHearts: words[1],
+        StakeShares:  words[2],
+        LockedDay:    int(words[3].Int64()),
+        StakedDays:   int(words[4].Int64()),
+    }, nil
+}
+
+// Miner of a stake: shares are in 1e-12 T-Shares and hearts in 1e-8 HEX
+func walletStakeToMiner(stake walletStake) Miner {
+    tShares, _ := new(big.Float).Quo(new(big.Float).SetInt(stake.StakeShares), big.NewFloat(1e12)).Float64()
+    principal, _ := new(big.Float).Quo(new(big.Float).SetInt(stake.StakedHearts), big.NewFloat(1e8)).Float64()
+    return Miner{
+        StartDate:    hexLaunch.AddDate(0, 0, stake.LockedDay).Format(dateLayout),
+        EndDate:      hexLaunch.AddDate(0, 0, stake.LockedDay+stake.StakedDays).Format(dateLayout),
+        TShares:      tShares,
+        PrincipalHEX: principal,
+        StakeID:      stake.StakeID,
+    }
+}
+
+// Active stakes of address as miners, ended stakes are no longer in the contract's list
+func fetchWalletStakes(rpcURL, address string) ([]Miner, error) {
+    result, err := hexContractCall(rpcURL, stakeCountSelector+abiAddress(address))
+    if err != nil {
+        return nil, err
+    }
+    count, err := abiWord(result, 0)
+    if err != nil {
+        return nil, err
+    }
+    if !count.IsUint64() || count.Uint64() > maxWalletStakes {
+        return nil, fmt.Errorf("contract reported %s stakes", count)
+    }
+    var miners []Miner
+    for i := uint64(0); i < count.Uint64(); i++ {
+        result, err := hexContractCall(rpcURL, stakeListsSelector+abiAddress(address)+abiUint(i))
+        if err != nil {
+            return nil, fmt.Errorf("stake %d: %v", i+1, err)
+        }
+        stake, err := decodeWalletStake(result)
+        if err != nil {
+            return nil, fmt.Errorf("stake %d: %v", i+1, err)
+        }
+        miners = append(miners, walletStakeToMiner(stake))
+    }
+    return miners, nil
+}
+
+// Preview rows of the fetched stakes, duplicates are flagged the same way as in a CSV import
+func walletImportRows(stakes, existing []Miner) []importRow {
+    rows := make([]importRow, 0, len(stakes))
+    for i, miner := range stakes {
+        row := importRow{
+            Line:   i + 1,
+            Label:  fmt.Sprintf("Stake #%d", miner.StakeID),
+            Fields: []string{miner.StartDate, miner.EndDate, formatTShares(miner.TShares) + " T-Shares", formatWithCommas(int(miner.PrincipalHEX)) + " HEX"},
+            Miner:  miner,
+        }
+        row.Err = validateMiner(miner)
+        row.Include = row.Err == nil
+        rows = append(rows, row)
+    }
+    return markDuplicates(rows, existing)
+}
@@ -0,0 +1,53 @@
+package main
+
+import "testing"
+
+// Wallet stakes are checked for duplicates like CSV rows: by stake ID and by dates and T-Shares
+func TestWalletImportRows(t *testing.T) {
+    existing := []Miner{
+        {StartDate: "01-01-2025", EndDate: "01-01-2027", TShares: 1},             // Added by hand
+        {StartDate: "01-02-2025", EndDate: "01-02-2027", TShares: 2, StakeID: 7}, // Imported before
+    }
+    stakes := []Miner{
+        {StartDate: "01-01-2025", EndDate: "01-01-2027", TShares: 1, StakeID: 5},
+        {StartDate: "01-03-2025", EndDate: "01-03-2027", TShares: 3, StakeID: 7},
+        {StartDate: "01-04-2025", EndDate: "01-04-2027", TShares: 4, StakeID: 8},
+    }
+    rows := walletImportRows(stakes, existing)
+    if len(rows) != 3 {
+        t.Fatalf("got %d rows, want one per stake", len(rows))
+    }
+    if rows[0].Include || rows[0].Warning != "matches a saved miner" || rows[0].Err != nil {
+        t.Errorf("stake matching a hand-added miner: include %v, warning %q, error %v", rows[0].Include, rows[0].Warning, rows[0].Err)
+    }
+    if rows[1].Include || rows[1].Err == nil {
+        t.Errorf("stake imported before: include %v, error %v, want it refused", rows[1].Include, rows[1].Err)
+    }
+    if !rows[2].Include || rows[2].Warning != "" || rows[2].Err != nil || rows[2].Label != "Stake #8" {
+        t.Errorf("new stake = %+v, want it included", rows[2])
+    }
+}
+
+func TestValidateRPCURL(t *testing.T) {
+    tests := []struct {
+        raw  string
+        want string
+        ok   bool
+    }{
+        {"https://rpc.pulsechain.com", "https://rpc.pulsechain.com", true},
+        {" https://rpc.example.com/?apikey=abc123 ", "https://rpc.example.com/?apikey=abc123", true},
+        {"https://rpc.example.com/v1/abc123", "https://rpc.example.com/v1/abc123", true},
+        {"http://rpc.pulsechain.com", "", false},
+        {"rpc.pulsechain.com", "", false},
+        {"https://", "", false},
+        {"https://?apikey=abc123", "", false},
+        {"https://rpc.pulsechain.com#top", "", false},
+        {"", "", false},
+    }
+    for _, tt := range tests {
+        got, err := validateRPCURL(tt.raw)
+        if (err == nil) != tt.ok || got != tt.want {
+            t.Errorf("validateRPCURL(%q) = %q, %v, want %q, ok %v", tt.raw, got, err, tt.want, tt.ok)
+        }
+    }
+}
@@ -0,0 +1,181 @@
+package main
+
+import (
+    "fmt"
+    "log"
+    "sort"
+    "time"
+
+    "fyne.io/fyne/v2"
+    "fyne.io/fyne/v2/container"
+    "fyne.io/fyne/v2/widget"
+)
+
+// Yield tab: HEX each active miner is projected to have earned at maturity. The expected
+// figure keeps the current payout per T-Share for the days left, the pessimistic and
+// optimistic ones scale it by how low and high the daily payout went in the recent history.
+// The T-Share rate isn't used, it only sets how many T-Shares new stakes get.
+
+const (
+    yieldLookbackDays   = 90  // History days the scenarios are taken from
+    yieldLowPercentile  = 0.1 // Ignores the odd day with a large penalty payout
+    yieldHighPercentile = 0.9
+)
+
+// Factors applied to the current payout per T-Share, 1 is no change
+type yieldScenarios struct {
+    Pessimistic float64
+    Optimistic  float64
+}
+
+// Scenarios from the daily payouts of the newest history days relative to the newest one,
+// unchanged payouts when there is no history yet
+func payoutScenarios(history HEXJSON) yieldScenarios {
+    var payouts []float64
+    for _, entry := range history { // Newest day first
+        if entry.DailyPayoutHEX > 0 {
+            payouts = append(payouts, entry.DailyPayoutHEX)
+        }
+        if len(payouts) == yieldLookbackDays {
+            break
+        }
+    }
+    if len(payouts) < 2 {
+        return yieldScenarios{Pessimistic: 1, Optimistic: 1}
+    }
+    latest := payouts[0]
+    sort.Float64s(payouts)
+    percentile := func(p float64) float64 {
+        return payouts[int(p*float64(len(payouts)-1))]
+    }
+    scenarios := yieldScenarios{
+        Pessimistic: percentile(yieldLowPercentile) / latest,
+        Optimistic:  percentile(yieldHighPercentile) / latest,
+    }
+    // The current payout is always within the range
+    if scenarios.Pessimistic > 1 {
+        scenarios.Pessimistic = 1
+    }
+    if scenarios.Optimistic < 1 {
+        scenarios.Optimistic = 1
+    }
+    return scenarios
+}
+
+// Yield of one miner at maturity
+type yieldProjection struct {
+    Miner          Miner
+    DaysLeft       int
+    EarnedHEX      float64 // Estimated at the current payout, the history has no payout per T-Share
+    ExpectedHEX    float64 // Total yield at maturity, as stakeYield
+    PessimisticHEX float64
+    OptimisticHEX  float64
+}
+
+// ok is false for completed miners and miners whose dates can't be used
+func projectYield(miner Miner, now time.Time, payoutPerTShare float64, scenarios yieldScenarios) (yieldProjection, bool) {
+    if miner.Status == "completed" || validateMiner(miner) != nil {
+        return yieldProjection{}, false
+    }
+    expected, ok := stakeYield(miner, payoutPerTShare)
+    if !ok {
+        return yieldProjection{}, false
+    }
+    total, err := stakeDays(miner.StartDate, miner.EndDate)
+    if err != nil {
+        return yieldProjection{}, false
+    }
+    left, err := daysLeftAt(miner.EndDate, now)
+    if err != nil {
+        return yieldProjection{}, false
+    }
+    if left > total {
+        left = total // Not started yet
+    }
+    daily := miner.TShares * payoutPerTShare
+    p := yieldProjection{
+        Miner:       miner,
+        DaysLeft:    left,
+        EarnedHEX:   expected - daily*float64(left),
+        ExpectedHEX: expected,
+    }
+    p.PessimisticHEX = p.EarnedHEX + daily*scenarios.Pessimistic*float64(left)
+    p.OptimisticHEX = p.EarnedHEX + daily*scenarios.Optimistic*float64(left)
+    return p, true
+}
+
+func projectYields(miners []Miner, now time.Time, data LiveData, scenarios map[string]yieldScenarios) []yieldProjection {
+    var projections []yieldProjection
+    for _, miner := range miners {
+        chain := minerChain(miner)
+        if p, ok := projectYield(miner, now, data.Chain(chain).PayoutPerTshare, scenarios[chain]); ok {
+            projections = append(projections, p)
+        }
+    }
+    return projections
+}
+
+func scenarioText(factor float64) string {
+    return fmt.Sprintf("%+.1f%%", (factor-1)*100)
+}
+
+func createYieldTab(miners []Miner) fyne.CanvasObject {
+    liveDataMutex.Lock()
+    data := latestLiveData
+    liveDataMutex.Unlock()
+
+    scenarios := map[string]yieldScenarios{}
+    for _, chain := range chains {
+        history, err := loadLocalHEXJSON(chain)
+        if err != nil {
+            log.Println("Error loading local HEX JSON:", err)
+        }
+        scenarios[chain] = payoutScenarios(history)
+    }
+
+    if !hasLiveData(data) {
+        return container.NewVBox(widget.NewLabel("Projected Yield"), widget.NewLabel(calculatingText))
+    }
+    projections := projectYields(miners, time.Now(), data, scenarios)
+    if len(projections) == 0 {
+        return container.NewVBox(widget.NewLabel("Projected Yield"), widget.NewLabel("No active miners"))
+    }
+
+    scenarioLines := ""
+    for _, chain := range chains {
+        if scenarios[chain].Pessimistic == 1 && scenarios[chain].Optimistic == 1 {
+            continue
+        }
+        scenarioLines += fmt.Sprintf("\n%s: pessimistic %s, optimistic %s", chainName(chain), scenarioText(scenarios[chain].Pessimistic), scenarioText(scenarios[chain].Optimistic))
+    }
+    intro := widget.NewLabel(fmt.Sprintf("Earned so far and expected use the current payout per T-Share. The scenarios change it for the days left by the low and high daily payouts of the last %d days:%s", yieldLookbackDays, scenarioLines))
+    intro.Wrapping = fyne.TextWrapWord
+
+    box := container.NewVBox(widget.NewLabel("Projected Yield"), intro)
+    // HEX on the two chains are separate tokens, so each has its own total
+    totals := map[string]*yieldProjection{}
+    for _, p := range projections {
+        miner := p.Miner
+        box.Add(widget.NewLabel(fmt.Sprintf("Miner: Start: %s, End: %s, T-Shares: %s%s (%s left)\nEarned so far (estimated): %s, at maturity: %s expected (%s - %s)",
+            miner.StartDate, miner.EndDate, formatTShares(miner.TShares), chainNote(miner), formatDuration(p.DaysLeft),
+            maskPrivate(formatHEX(p.EarnedHEX)), maskPrivate(formatHEX(p.ExpectedHEX)), maskPrivate(formatHEX(p.PessimisticHEX)), maskPrivate(formatHEX(p.OptimisticHEX)))))
+        chain := minerChain(miner)
+        if totals[chain] == nil {
+            totals[chain] = &yieldProjection{}
+        }
+        totals[chain].ExpectedHEX += p.ExpectedHEX
+        totals[chain].PessimisticHEX += p.PessimisticHEX
+        totals[chain].OptimisticHEX += p.OptimisticHEX
+    }
+    for _, chain := range chains {
+        t := totals[chain]
+        if t == nil {
+            continue
+        }
+        total := widget.NewLabel(fmt.Sprintf("Total at maturity (%s): %s expected, %s pessimistic, %s optimistic", chainName(chain),
+            maskPrivate(formatHEX(t.ExpectedHEX)), maskPrivate(formatHEX(t.PessimisticHEX)), maskPrivate(formatHEX(t.OptimisticHEX))))
+        total.TextStyle = fyne.TextStyle{Bold: true}
+        box.Add(total)
+    }
+    return container.NewVScroll(box)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+    "reflect"
+    "testing"
+)
+
+func TestPayoutScenarios(t *testing.T) {
+    history := func(payouts ...float64) HEXJSON {
+        var data HEXJSON
+        for i, payout := range payouts { // Newest day first
+            data = append(data, HEXJSONEntry{CurrentDay: len(payouts) - i, DailyPayoutHEX: payout})
+        }
+        return data
+    }
+    lookback := make([]float64, 0, yieldLookbackDays+10)
+    for i := 0; i < yieldLookbackDays; i++ {
+        lookback = append(lookback, 10)
+    }
+    for i := 0; i < 10; i++ {
+        lookback = append(lookback, 1000) // Older than the lookback
+    }
+    tests := []struct {
+        name    string
+        history HEXJSON
+        want    yieldScenarios
+    }{
+        {"no history", nil, yieldScenarios{1, 1}},
+        {"one day", history(10), yieldScenarios{1, 1}},
+        {"percentiles", history(10, 5, 6, 7, 8, 9, 11, 12, 13, 14, 15), yieldScenarios{0.6, 1.4}},
+        {"days without payout skipped", history(10, 0, 5, 6, 7, 8, 9, 11, 12, 13, 14, 0, 15), yieldScenarios{0.6, 1.4}},
+        {"current payout the highest", history(20, 5, 10), yieldScenarios{0.25, 1}},
+        {"current payout the lowest", history(5, 10, 20), yieldScenarios{1, 2}},
+        {"only the lookback days", history(lookback...), yieldScenarios{1, 1}},
+    }
+    for _, tt := range tests {
+        if got := payoutScenarios(tt.history); !near(float32(got.Pessimistic), float32(tt.want.Pessimistic)) || !near(float32(got.Optimistic), float32(tt.want.Optimistic)) {
+            t.Errorf("%s: payoutScenarios = %+v, want %+v", tt.name, got, tt.want)
+        }
+    }
+}
+
+func TestProjectYield(t *testing.T) {
+    now := testDay(t, "01-06-2025")
+    scenarios := yieldScenarios{Pessimistic: 0.5, Optimistic: 1.5}
+    active := Miner{StartDate: "01-05-2025", EndDate: "11-06-2025", TShares: 2}
+    p, ok := projectYield(active, now, 3, scenarios)
+    if !ok {
+        t.Fatal("no projection for an active miner")
+    }
+    // 41 days at 6 HEX a day, 10 of them left
+    want := yieldProjection{Miner: active, DaysLeft: 10, EarnedHEX: 186, ExpectedHEX: 246, PessimisticHEX: 216, OptimisticHEX: 276}
+    if !reflect.DeepEqual(p, want) {
+        t.Errorf("projection = %+v, want %+v", p, want)
+    }
+    if yield, _ := stakeYield(active, 3); p.ExpectedHEX != yield {
+        t.Errorf("expected %v, want the stake yield %v", p.ExpectedHEX, yield)
+    }
+
+    pending := Miner{StartDate: "11-06-2025", EndDate: "21-06-2025", TShares: 1}
+    if p, ok := projectYield(pending, now, 3, scenarios); !ok || p.DaysLeft != 10 || p.EarnedHEX != 0 || p.ExpectedHEX != 30 {
+        t.Errorf("pending projection = %+v, want nothing earned and all 10 days left", p)
+    }
+    for _, miner := range []Miner{
+        {StartDate: "01-01-2024", EndDate: "01-01-2025", TShares: 4, Status: "completed"},
+        {StartDate: "31-02-2025", EndDate: "01-01-2026", TShares: 1},
+    } {
+        if _, ok := projectYield(miner, now, 3, scenarios); ok {
+            t.Errorf("projected %+v", miner)
+        }
+    }
+}